@@ -0,0 +1,114 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodOverrides customizes the Pod that runs the agent.
+type PodOverrides struct {
+	// Resources sets the compute resource requirements of the agent container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the agent Pod to nodes with matching labels.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations allows the agent Pod to be scheduled onto tainted nodes.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Env adds environment variables to the agent container. Variables
+	// Axon sets itself, such as credentials, GITHUB_TOKEN, GH_TOKEN and
+	// those prefixed with AXON_, cannot be overridden.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// TaskDefaults defines default values for Tasks in a namespace.
+// Each field is only applied when the corresponding field of the Task is unset.
+type TaskDefaults struct {
+	// Credentials specifies how to authenticate with the agent.
+	// +optional
+	Credentials *Credentials `json:"credentials,omitempty"`
+
+	// Model overrides the default model.
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// ActiveDeadlineSeconds limits how long the agent may run.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// PodOverrides customizes the Pod that runs the agent.
+	// +optional
+	PodOverrides *PodOverrides `json:"podOverrides,omitempty"`
+
+	// AllowedTools restricts the tools the agent may use.
+	// +optional
+	AllowedTools []string `json:"allowedTools,omitempty"`
+
+	// DisallowedTools lists tools the agent must not use.
+	// +optional
+	DisallowedTools []string `json:"disallowedTools,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of a Task that has finished
+	// execution.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Artifacts configures the artifacts collected from Tasks. A Task that
+	// sets artifacts without a store uses the store from here.
+	// +optional
+	Artifacts *ArtifactsSpec `json:"artifacts,omitempty"`
+}
+
+// AxonConfigSpec defines the desired state of AxonConfig.
+type AxonConfigSpec struct {
+	// TaskDefaults are merged into every Task in the namespace.
+	// +optional
+	TaskDefaults *TaskDefaults `json:"taskDefaults,omitempty"`
+
+	// TranscriptArchive is where the compressed stream-json transcript of
+	// every Task in the namespace is archived once its agent exits, so that
+	// it can still be read after the Pod or the Task is deleted.
+	// +optional
+	TranscriptArchive *ArtifactStore `json:"transcriptArchive,omitempty"`
+
+	// Notifications are sent for every Task in the namespace.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	Notifications []Notification `json:"notifications,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AxonConfig is the Schema for the axonconfigs API.
+// It holds namespace-level configuration that the controller applies to
+// every Task in the namespace. When several AxonConfigs exist in a
+// namespace, they are applied in name order and the first one that sets a
+// field wins.
+type AxonConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AxonConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AxonConfigList contains a list of AxonConfig.
+type AxonConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AxonConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AxonConfig{}, &AxonConfigList{})
+}
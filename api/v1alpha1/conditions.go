@@ -0,0 +1,125 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types of Tasks.
+const (
+	// TaskConditionWorkspaceReady is True once the Task's Workspace has
+	// been found.
+	TaskConditionWorkspaceReady = "WorkspaceReady"
+	// TaskConditionJobCreated is True once the agent's Job has been
+	// created, and False with the reason if it cannot be.
+	TaskConditionJobCreated = "JobCreated"
+	// TaskConditionAgentStarted is True once the agent's Job is running.
+	TaskConditionAgentStarted = "AgentStarted"
+	// TaskConditionAwaitingInput is True while the agent waits for the
+	// answer to a question.
+	TaskConditionAwaitingInput = "AwaitingInput"
+	// TaskConditionAwaitingApproval is True while the agent's changes wait
+	// to be approved.
+	TaskConditionAwaitingApproval = "AwaitingApproval"
+	// TaskConditionSuspended is True while the Task is suspended.
+	TaskConditionSuspended = "Suspended"
+	// TaskConditionSucceeded is True once the Task has succeeded and False
+	// once it has failed.
+	TaskConditionSucceeded = "Succeeded"
+)
+
+// Condition types of TaskSpawners.
+const (
+	// TaskSpawnerConditionWorkspaceReady is True once the TaskSpawner's
+	// Workspace has been found.
+	TaskSpawnerConditionWorkspaceReady = "WorkspaceReady"
+	// TaskSpawnerConditionDeploymentAvailable is True while the spawner
+	// Deployment has an available replica.
+	TaskSpawnerConditionDeploymentAvailable = "DeploymentAvailable"
+	// TaskSpawnerConditionSourceReachable is True once the spawner has
+	// discovered work items from its source, and False while discovery
+	// fails.
+	TaskSpawnerConditionSourceReachable = "SourceReachable"
+	// TaskSpawnerConditionRateLimited is True while the source rejects
+	// the spawner's requests because of a rate limit.
+	TaskSpawnerConditionRateLimited = "RateLimited"
+	// TaskSpawnerConditionSuspended is True while discovery is suspended.
+	TaskSpawnerConditionSuspended = "Suspended"
+)
+
+// SetCondition sets a condition of the Task observed at its current
+// generation, and updates the Task's phase accordingly.
+func (t *Task) SetCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&t.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: t.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	t.Status.Phase = taskPhase(t.Status.Conditions)
+}
+
+// ClearCondition sets a condition of the Task that is True to False. Absent
+// conditions are left absent.
+func (t *Task) ClearCondition(conditionType, reason, message string) {
+	if meta.IsStatusConditionTrue(t.Status.Conditions, conditionType) {
+		t.SetCondition(conditionType, metav1.ConditionFalse, reason, message)
+	}
+}
+
+// taskPhase derives the phase of a Task from its conditions.
+func taskPhase(conditions []metav1.Condition) TaskPhase {
+	switch {
+	case meta.IsStatusConditionTrue(conditions, TaskConditionSucceeded):
+		return TaskPhaseSucceeded
+	case meta.IsStatusConditionFalse(conditions, TaskConditionSucceeded):
+		return TaskPhaseFailed
+	case meta.IsStatusConditionTrue(conditions, TaskConditionSuspended):
+		return TaskPhaseSuspended
+	case meta.IsStatusConditionTrue(conditions, TaskConditionAwaitingApproval):
+		return TaskPhasePendingApproval
+	case meta.IsStatusConditionTrue(conditions, TaskConditionAwaitingInput):
+		return TaskPhaseAwaitingInput
+	case meta.IsStatusConditionTrue(conditions, TaskConditionAgentStarted):
+		return TaskPhaseRunning
+	default:
+		return TaskPhasePending
+	}
+}
+
+// SetCondition sets a condition of the TaskSpawner observed at its current
+// generation, and updates the TaskSpawner's phase accordingly.
+func (ts *TaskSpawner) SetCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&ts.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: ts.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	ts.Status.Phase = taskSpawnerPhase(ts.Status.Conditions)
+}
+
+// ClearCondition sets a condition of the TaskSpawner that is True to False.
+// Absent conditions are left absent.
+func (ts *TaskSpawner) ClearCondition(conditionType, reason, message string) {
+	if meta.IsStatusConditionTrue(ts.Status.Conditions, conditionType) {
+		ts.SetCondition(conditionType, metav1.ConditionFalse, reason, message)
+	}
+}
+
+// taskSpawnerPhase derives the phase of a TaskSpawner from its conditions.
+func taskSpawnerPhase(conditions []metav1.Condition) TaskSpawnerPhase {
+	switch {
+	case meta.IsStatusConditionTrue(conditions, TaskSpawnerConditionSuspended):
+		return TaskSpawnerPhaseSuspended
+	case meta.IsStatusConditionFalse(conditions, TaskSpawnerConditionWorkspaceReady),
+		meta.IsStatusConditionFalse(conditions, TaskSpawnerConditionSourceReachable):
+		return TaskSpawnerPhaseFailed
+	case meta.IsStatusConditionTrue(conditions, TaskSpawnerConditionSourceReachable):
+		return TaskSpawnerPhaseRunning
+	default:
+		return TaskSpawnerPhasePending
+	}
+}
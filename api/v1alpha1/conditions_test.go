@@ -0,0 +1,136 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTaskPhaseFromConditions(t *testing.T) {
+	type cond struct {
+		conditionType string
+		status        metav1.ConditionStatus
+	}
+
+	tests := []struct {
+		name       string
+		conditions []cond
+		want       TaskPhase
+	}{
+		{
+			name: "Job created",
+			conditions: []cond{
+				{TaskConditionWorkspaceReady, metav1.ConditionTrue},
+				{TaskConditionJobCreated, metav1.ConditionTrue},
+			},
+			want: TaskPhasePending,
+		},
+		{
+			name: "Agent started",
+			conditions: []cond{
+				{TaskConditionJobCreated, metav1.ConditionTrue},
+				{TaskConditionAgentStarted, metav1.ConditionTrue},
+			},
+			want: TaskPhaseRunning,
+		},
+		{
+			name: "Awaiting input",
+			conditions: []cond{
+				{TaskConditionAgentStarted, metav1.ConditionTrue},
+				{TaskConditionAwaitingInput, metav1.ConditionTrue},
+			},
+			want: TaskPhaseAwaitingInput,
+		},
+		{
+			name: "Awaiting approval",
+			conditions: []cond{
+				{TaskConditionAgentStarted, metav1.ConditionTrue},
+				{TaskConditionAwaitingApproval, metav1.ConditionTrue},
+			},
+			want: TaskPhasePendingApproval,
+		},
+		{
+			name: "Suspended",
+			conditions: []cond{
+				{TaskConditionAgentStarted, metav1.ConditionFalse},
+				{TaskConditionSuspended, metav1.ConditionTrue},
+			},
+			want: TaskPhaseSuspended,
+		},
+		{
+			name: "Succeeded",
+			conditions: []cond{
+				{TaskConditionAgentStarted, metav1.ConditionTrue},
+				{TaskConditionSucceeded, metav1.ConditionTrue},
+			},
+			want: TaskPhaseSucceeded,
+		},
+		{
+			name: "Failed",
+			conditions: []cond{
+				{TaskConditionWorkspaceReady, metav1.ConditionFalse},
+				{TaskConditionSucceeded, metav1.ConditionFalse},
+			},
+			want: TaskPhaseFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{}
+			for _, c := range tt.conditions {
+				task.SetCondition(c.conditionType, c.status, "Test", "")
+			}
+			if task.Status.Phase != tt.want {
+				t.Errorf("phase = %q, want %q", task.Status.Phase, tt.want)
+			}
+		})
+	}
+}
+
+func TestClearCondition(t *testing.T) {
+	task := &Task{}
+	task.ClearCondition(TaskConditionSuspended, "Resumed", "")
+	if len(task.Status.Conditions) != 0 {
+		t.Fatalf("expected an absent condition to stay absent, got %+v", task.Status.Conditions)
+	}
+
+	task.SetCondition(TaskConditionSuspended, metav1.ConditionTrue, "Suspended", "")
+	task.ClearCondition(TaskConditionSuspended, "Resumed", "")
+	c := task.Status.Conditions[0]
+	if c.Status != metav1.ConditionFalse || c.Reason != "Resumed" {
+		t.Errorf("condition = %+v, want False with reason Resumed", c)
+	}
+	if task.Status.Phase != TaskPhasePending {
+		t.Errorf("phase = %q, want %q", task.Status.Phase, TaskPhasePending)
+	}
+}
+
+func TestTaskSpawnerPhaseFromConditions(t *testing.T) {
+	ts := &TaskSpawner{}
+	ts.SetCondition(TaskSpawnerConditionDeploymentAvailable, metav1.ConditionTrue, "Test", "")
+	if ts.Status.Phase != TaskSpawnerPhasePending {
+		t.Errorf("phase = %q, want %q", ts.Status.Phase, TaskSpawnerPhasePending)
+	}
+
+	ts.SetCondition(TaskSpawnerConditionSourceReachable, metav1.ConditionTrue, "Test", "")
+	if ts.Status.Phase != TaskSpawnerPhaseRunning {
+		t.Errorf("phase = %q, want %q", ts.Status.Phase, TaskSpawnerPhaseRunning)
+	}
+
+	// A rate limit leaves the source reachable.
+	ts.SetCondition(TaskSpawnerConditionRateLimited, metav1.ConditionTrue, "Test", "")
+	if ts.Status.Phase != TaskSpawnerPhaseRunning {
+		t.Errorf("phase = %q, want %q", ts.Status.Phase, TaskSpawnerPhaseRunning)
+	}
+
+	ts.SetCondition(TaskSpawnerConditionSourceReachable, metav1.ConditionFalse, "Test", "")
+	if ts.Status.Phase != TaskSpawnerPhaseFailed {
+		t.Errorf("phase = %q, want %q", ts.Status.Phase, TaskSpawnerPhaseFailed)
+	}
+
+	ts.SetCondition(TaskSpawnerConditionSuspended, metav1.ConditionTrue, "Test", "")
+	if ts.Status.Phase != TaskSpawnerPhaseSuspended {
+		t.Errorf("phase = %q, want %q", ts.Status.Phase, TaskSpawnerPhaseSuspended)
+	}
+}
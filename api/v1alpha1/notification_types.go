@@ -0,0 +1,100 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Notification sends a message when a Task enters one of the given phases.
+// +kubebuilder:validation:XValidation:rule="(has(self.slack) ? 1 : 0) + (has(self.webhook) ? 1 : 0) + (has(self.githubComment) ? 1 : 0) == 1",message="exactly one of slack, webhook and githubComment must be set"
+type Notification struct {
+	// Name identifies the notification in the Task's status.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Phases are the Task phases that trigger the notification. Defaults to
+	// Succeeded and Failed.
+	// +optional
+	// +kubebuilder:validation:items:Enum=Pending;Running;Succeeded;Failed;Suspended;AwaitingInput;PendingApproval
+	Phases []TaskPhase `json:"phases,omitempty"`
+
+	// Slack posts a message to a Slack-compatible incoming webhook.
+	// +optional
+	Slack *SlackNotification `json:"slack,omitempty"`
+
+	// Webhook posts the Task's status as JSON to a URL.
+	// +optional
+	Webhook *WebhookNotification `json:"webhook,omitempty"`
+
+	// GitHubComment comments on the GitHub issue or pull request the Task
+	// was spawned for, using the token of the Task's Workspace. Tasks that
+	// were not spawned from GitHub are skipped.
+	// +optional
+	GitHubComment *GitHubCommentNotification `json:"githubComment,omitempty"`
+}
+
+// SlackNotification configures a Slack-compatible incoming webhook.
+type SlackNotification struct {
+	// SecretRef references a Secret containing the incoming webhook URL in
+	// a SLACK_WEBHOOK_URL key.
+	// +kubebuilder:validation:Required
+	SecretRef SecretReference `json:"secretRef"`
+}
+
+// WebhookNotification configures a generic JSON webhook.
+type WebhookNotification struct {
+	// URL is the URL the JSON payload is posted to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// SecretRef optionally references a Secret containing a WEBHOOK_SECRET
+	// key. If set, the payload is signed with HMAC-SHA256 using the secret
+	// and the signature is sent in the X-Axon-Signature-256 header as
+	// sha256=<hex digest>.
+	// +optional
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+}
+
+// GitHubCommentNotification configures comments on the GitHub issue or
+// pull request a Task was spawned for.
+type GitHubCommentNotification struct{}
+
+// NotificationState is the delivery state of a notification.
+// +kubebuilder:validation:Enum=Pending;Delivered;Failed
+type NotificationState string
+
+const (
+	// NotificationStatePending means the notification has not been
+	// delivered yet and is retried with exponential backoff.
+	NotificationStatePending NotificationState = "Pending"
+	// NotificationStateDelivered means the notification was delivered.
+	NotificationStateDelivered NotificationState = "Delivered"
+	// NotificationStateFailed means delivery was given up, either after too
+	// many attempts or because the receiver rejected it.
+	NotificationStateFailed NotificationState = "Failed"
+)
+
+// NotificationStatus records the delivery of a notification for a phase.
+type NotificationStatus struct {
+	// Name is the name of the notification.
+	Name string `json:"name"`
+
+	// Phase is the Task phase the notification was sent for.
+	Phase TaskPhase `json:"phase"`
+
+	// State is the delivery state of the notification.
+	State NotificationState `json:"state"`
+
+	// Attempts is the number of delivery attempts.
+	Attempts int32 `json:"attempts"`
+
+	// LastAttemptTime is when delivery was last attempted.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// LastError is the error of the last failed delivery attempt.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
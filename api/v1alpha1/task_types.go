@@ -26,6 +26,54 @@ const (
 	TaskPhaseSucceeded TaskPhase = "Succeeded"
 	// TaskPhaseFailed means the Task has failed.
 	TaskPhaseFailed TaskPhase = "Failed"
+	// TaskPhaseSuspended means the Task's agent has been stopped because
+	// spec.suspend is set. The Task runs again from scratch once resumed.
+	TaskPhaseSuspended TaskPhase = "Suspended"
+	// TaskPhaseAwaitingInput means the agent is running but blocked on a
+	// question for a human (see status.inputRequest).
+	TaskPhaseAwaitingInput TaskPhase = "AwaitingInput"
+	// TaskPhasePendingApproval means the agent has finished and its changes
+	// are waiting to be approved before they are pushed.
+	TaskPhasePendingApproval TaskPhase = "PendingApproval"
+)
+
+// TaskFailureReason classifies why a Task failed.
+// +kubebuilder:validation:Enum=ConfigurationError;ImagePullFailed;Unschedulable;ContainerConfigError;GitAuthenticationFailed;GitCloneFailed;OOMKilled;DeadlineExceeded;Evicted;AgentError;PushFailed;Unknown
+type TaskFailureReason string
+
+const (
+	// TaskFailureConfigurationError means the Task's Job could not be
+	// created, e.g. because its Workspace or credentials are missing.
+	TaskFailureConfigurationError TaskFailureReason = "ConfigurationError"
+	// TaskFailureImagePullFailed means an image of the Pod could not be
+	// pulled.
+	TaskFailureImagePullFailed TaskFailureReason = "ImagePullFailed"
+	// TaskFailureUnschedulable means the Pod could not be scheduled.
+	TaskFailureUnschedulable TaskFailureReason = "Unschedulable"
+	// TaskFailureContainerConfigError means a container of the Pod could
+	// not be created, e.g. because a Secret it references is missing.
+	TaskFailureContainerConfigError TaskFailureReason = "ContainerConfigError"
+	// TaskFailureGitAuthenticationFailed means the workspace repository
+	// could not be cloned because authentication failed.
+	TaskFailureGitAuthenticationFailed TaskFailureReason = "GitAuthenticationFailed"
+	// TaskFailureGitCloneFailed means the workspace repository could not
+	// be cloned.
+	TaskFailureGitCloneFailed TaskFailureReason = "GitCloneFailed"
+	// TaskFailureOOMKilled means a container was killed for exceeding its
+	// memory limit.
+	TaskFailureOOMKilled TaskFailureReason = "OOMKilled"
+	// TaskFailureDeadlineExceeded means the Task ran longer than its
+	// activeDeadlineSeconds.
+	TaskFailureDeadlineExceeded TaskFailureReason = "DeadlineExceeded"
+	// TaskFailureEvicted means the Pod was evicted from its node.
+	TaskFailureEvicted TaskFailureReason = "Evicted"
+	// TaskFailureAgentError means the agent exited with an error.
+	TaskFailureAgentError TaskFailureReason = "AgentError"
+	// TaskFailurePushFailed means the approved changes could not be pushed.
+	TaskFailurePushFailed TaskFailureReason = "PushFailed"
+	// TaskFailureUnknown means the cause of the failure could not be
+	// determined.
+	TaskFailureUnknown TaskFailureReason = "Unknown"
 )
 
 // SecretReference refers to a Secret containing credentials.
@@ -55,8 +103,9 @@ type TaskSpec struct {
 	Prompt string `json:"prompt"`
 
 	// Credentials specifies how to authenticate with the agent.
-	// +kubebuilder:validation:Required
-	Credentials Credentials `json:"credentials"`
+	// If unset, the credentials from the namespace's AxonConfig are used.
+	// +optional
+	Credentials *Credentials `json:"credentials,omitempty"`
 
 	// Model optionally overrides the default model.
 	// +optional
@@ -76,6 +125,224 @@ type TaskSpec struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// ActiveDeadlineSeconds limits how long the agent may run. The Task fails
+	// once the deadline is exceeded.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// PodOverrides customizes the Pod that runs the agent.
+	// +optional
+	PodOverrides *PodOverrides `json:"podOverrides,omitempty"`
+
+	// AllowedTools restricts the tools the agent may use.
+	// +optional
+	AllowedTools []string `json:"allowedTools,omitempty"`
+
+	// DisallowedTools lists tools the agent must not use.
+	// +optional
+	DisallowedTools []string `json:"disallowedTools,omitempty"`
+
+	// Suspend stops the Task's agent without deleting the Task. Setting it
+	// on a running Task deletes the Job and moves the Task to the Suspended
+	// phase; clearing it starts a new Job. It has no effect on Tasks that
+	// have already finished.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// PersistSession keeps the agent's session state (~/.claude) in a
+	// PersistentVolumeClaim so that a later Task can continue the
+	// conversation with ContinueFrom. The claim is deleted together with
+	// the last Task that uses it.
+	// +optional
+	PersistSession bool `json:"persistSession,omitempty"`
+
+	// ContinueFrom references a finished Task whose persisted session is
+	// restored before the agent starts, so the agent continues the most
+	// recent conversation of that Task instead of starting from scratch.
+	// The referenced Task must have been run with PersistSession or
+	// ContinueFrom set. Only the session is restored: the Workspace is
+	// cloned afresh, so changes the previous agent did not push are lost.
+	// A session can be continued by one unfinished Task at a time.
+	// +optional
+	ContinueFrom *TaskReference `json:"continueFrom,omitempty"`
+
+	// HumanInput lets the agent ask a human for a decision while it runs.
+	// When set, the agent is given an ask_human tool; calling it records the
+	// question in status.inputRequest and blocks the agent until the
+	// question is answered (for example with "axon answer") or times out.
+	// The tool is served by the axon-agent helper, which a custom agent
+	// image must include.
+	// +optional
+	HumanInput *HumanInputPolicy `json:"humanInput,omitempty"`
+
+	// RequireApproval holds the agent's changes for review instead of
+	// letting the agent push them. The agent runs without the Workspace's
+	// GitHub token; once it finishes, its diff against the cloned ref is
+	// stored in an immutable ConfigMap and the Task enters the
+	// PendingApproval phase. Setting Approved along with the digest of the
+	// reviewed diff then pushes it to the branch axon/<task> and opens a
+	// pull request with the Workspace's credentials.
+	// Requires WorkspaceRef.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// Approved approves the changes of a Task with RequireApproval set.
+	// Requires ApprovedDiffSHA256.
+	// +optional
+	Approved bool `json:"approved,omitempty"`
+
+	// ApprovedDiffSHA256 is the hex-encoded SHA-256 digest of the diff
+	// that was reviewed and approved, as shown by "axon diff". Only a diff
+	// with this digest is pushed.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{64}$`
+	ApprovedDiffSHA256 string `json:"approvedDiffSHA256,omitempty"`
+
+	// Artifacts uploads the agent's stream-json transcript, its final diff
+	// and the files matching Paths to an artifact store once the agent
+	// exits, so they outlive the Pod. The uploaded artifacts are listed in
+	// status.artifacts.
+	// +optional
+	Artifacts *ArtifactsSpec `json:"artifacts,omitempty"`
+}
+
+// ArtifactsSpec configures the artifacts collected when the agent exits.
+type ArtifactsSpec struct {
+	// Paths lists files, directories or glob patterns to collect in
+	// addition to the transcript and the diff. Relative paths are resolved
+	// against the repository, or the agent's working directory if the Task
+	// has no Workspace.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// Store is where the artifacts are uploaded. If unset, the store from
+	// the namespace's AxonConfig is used.
+	// +optional
+	Store *ArtifactStore `json:"store,omitempty"`
+}
+
+// ArtifactStore is a location artifacts are uploaded to. Exactly one of
+// S3 and PersistentVolumeClaim must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.s3) != has(self.persistentVolumeClaim)",message="exactly one of s3 and persistentVolumeClaim must be set"
+type ArtifactStore struct {
+	// S3 stores artifacts in an S3-compatible bucket.
+	// +optional
+	S3 *S3ArtifactStore `json:"s3,omitempty"`
+
+	// PersistentVolumeClaim stores artifacts on a PersistentVolumeClaim.
+	// The claim must be mountable by every agent Pod in the namespace, so
+	// a ReadWriteMany claim is recommended.
+	// +optional
+	PersistentVolumeClaim *PVCArtifactStore `json:"persistentVolumeClaim,omitempty"`
+}
+
+// S3ArtifactStore is an S3-compatible bucket, such as Amazon S3 or MinIO.
+type S3ArtifactStore struct {
+	// Endpoint is the URL of the S3 API, e.g. https://s3.us-east-1.amazonaws.com
+	// or http://minio.minio.svc:9000. Buckets are addressed path-style.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket.
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+
+	// Region is the region of the bucket. Defaults to us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Prefix is prepended to the key of every artifact.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// SecretRef references the Secret holding the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY used to access the bucket.
+	SecretRef SecretReference `json:"secretRef"`
+}
+
+// PVCArtifactStore is a PersistentVolumeClaim artifacts are written to.
+type PVCArtifactStore struct {
+	// ClaimName is the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	ClaimName string `json:"claimName"`
+
+	// Prefix is the directory of the claim artifacts are written to.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// TranscriptArchive is where the transcript of a Task is archived.
+type TranscriptArchive struct {
+	// Store is the store the transcript is archived to.
+	Store ArtifactStore `json:"store"`
+
+	// Key is the object key, or the path within the claim, of the
+	// gzip-compressed transcript.
+	Key string `json:"key"`
+}
+
+// Artifact is a file uploaded to the artifact store.
+type Artifact struct {
+	// Name identifies the artifact within the Task, e.g. transcript.jsonl,
+	// diff.patch or files/<path>.
+	Name string `json:"name"`
+
+	// Key is the object key, or the path within the claim, the artifact is
+	// stored at.
+	Key string `json:"key"`
+
+	// Size is the size of the artifact in bytes.
+	Size int64 `json:"size"`
+}
+
+// HumanInputPolicy configures how an agent may ask a human for input.
+type HumanInputPolicy struct {
+	// Timeout is how long a question may stay unanswered. Once it elapses,
+	// the question is answered with DefaultAnswer. If unset, the agent
+	// waits until the question is answered or the Task's deadline passes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// DefaultAnswer is the answer given to the agent when Timeout elapses.
+	// If empty, the agent is told that no answer was received and is
+	// expected to proceed on its own judgement.
+	// +optional
+	DefaultAnswer string `json:"defaultAnswer,omitempty"`
+}
+
+// InputRequest is a question the agent has asked a human.
+type InputRequest struct {
+	// ID uniquely identifies the question within the Task.
+	ID string `json:"id"`
+
+	// Question is the question asked by the agent.
+	Question string `json:"question"`
+
+	// RequestedAt is when the agent asked the question.
+	RequestedAt metav1.Time `json:"requestedAt"`
+
+	// Answer is the answer given to the agent.
+	// +optional
+	Answer string `json:"answer,omitempty"`
+
+	// AnsweredBy records who answered the question, such as "cli",
+	// "github:<login>", or "timeout".
+	// +optional
+	AnsweredBy string `json:"answeredBy,omitempty"`
+
+	// AnsweredAt is when the question was answered. The question is
+	// pending while it is unset.
+	// +optional
+	AnsweredAt *metav1.Time `json:"answeredAt,omitempty"`
+}
+
+// TaskReference refers to a Task resource by name.
+type TaskReference struct {
+	// Name is the name of the Task resource.
+	Name string `json:"name"`
 }
 
 // TaskStatus defines the observed state of Task.
@@ -103,6 +370,73 @@ type TaskStatus struct {
 	// Message provides additional information about the current status.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// FailureReason classifies why the Task failed. Message describes the
+	// failure in more detail.
+	// +optional
+	FailureReason TaskFailureReason `json:"failureReason,omitempty"`
+
+	// Conditions represent the latest observations of the Task's state.
+	// The phase is derived from them.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// InputRequest is the most recent question the agent asked a human,
+	// along with its answer once given.
+	// +optional
+	InputRequest *InputRequest `json:"inputRequest,omitempty"`
+
+	// DiffConfigMapName is the name of the immutable ConfigMap holding the
+	// snapshot of the agent's changes of a Task with RequireApproval set,
+	// taken once the agent finished. The agent cannot modify it.
+	// +optional
+	DiffConfigMapName string `json:"diffConfigMapName,omitempty"`
+
+	// PushJobName is the name of the Job that pushes the approved changes.
+	// +optional
+	PushJobName string `json:"pushJobName,omitempty"`
+
+	// SessionClaimName is the name of the PersistentVolumeClaim holding the
+	// agent's session state.
+	// +optional
+	SessionClaimName string `json:"sessionClaimName,omitempty"`
+
+	// Artifacts lists the artifacts uploaded when the agent exited.
+	// +optional
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+
+	// TranscriptArchive is where the agent's transcript is archived, if the
+	// namespace's AxonConfig configures a transcript archive.
+	// +optional
+	TranscriptArchive *TranscriptArchive `json:"transcriptArchive,omitempty"`
+
+	// Usage is what the agent reported in its result event when it exited.
+	// +optional
+	Usage *TaskUsage `json:"usage,omitempty"`
+
+	// EffectiveSpec is the spec the Job was built from, after merging the
+	// defaults from the namespace's AxonConfigs into the Task's spec.
+	// +optional
+	EffectiveSpec *TaskSpec `json:"effectiveSpec,omitempty"`
+
+	// Notifications records the delivery of the notifications sent when the
+	// Task entered its phases.
+	// +optional
+	Notifications []NotificationStatus `json:"notifications,omitempty"`
+}
+
+// TaskUsage is the usage reported by the agent when it exited.
+type TaskUsage struct {
+	// CostUSD is the total cost of the agent's API requests in US dollars,
+	// as a decimal string.
+	// +optional
+	CostUSD string `json:"costUSD,omitempty"`
+
+	// NumTurns is the number of turns the agent took.
+	// +optional
+	NumTurns int32 `json:"numTurns,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -116,7 +450,26 @@ type Task struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   TaskSpec   `json:"spec,omitempty"`
+	// Spec describes a single run of the agent. Only suspend, approved and
+	// ttlSecondsAfterFinished can be changed once the Task is created, so that
+	// the status always describes a run of the current spec. To run the agent
+	// with a different spec, create a new Task, e.g. with continueFrom.
+	// +kubebuilder:validation:XValidation:rule="self.type == oldSelf.type",message="type is immutable"
+	// +kubebuilder:validation:XValidation:rule="self.prompt == oldSelf.prompt",message="prompt is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.credentials) == has(oldSelf.credentials) && (!has(self.credentials) || self.credentials == oldSelf.credentials)",message="credentials is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.model) == has(oldSelf.model) && (!has(self.model) || self.model == oldSelf.model)",message="model is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.workspaceRef) == has(oldSelf.workspaceRef) && (!has(self.workspaceRef) || self.workspaceRef == oldSelf.workspaceRef)",message="workspaceRef is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.activeDeadlineSeconds) == has(oldSelf.activeDeadlineSeconds) && (!has(self.activeDeadlineSeconds) || self.activeDeadlineSeconds == oldSelf.activeDeadlineSeconds)",message="activeDeadlineSeconds is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.podOverrides) == has(oldSelf.podOverrides) && (!has(self.podOverrides) || self.podOverrides == oldSelf.podOverrides)",message="podOverrides is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.allowedTools) == has(oldSelf.allowedTools) && (!has(self.allowedTools) || self.allowedTools == oldSelf.allowedTools)",message="allowedTools is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.disallowedTools) == has(oldSelf.disallowedTools) && (!has(self.disallowedTools) || self.disallowedTools == oldSelf.disallowedTools)",message="disallowedTools is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.persistSession) == has(oldSelf.persistSession) && (!has(self.persistSession) || self.persistSession == oldSelf.persistSession)",message="persistSession is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.continueFrom) == has(oldSelf.continueFrom) && (!has(self.continueFrom) || self.continueFrom == oldSelf.continueFrom)",message="continueFrom is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.humanInput) == has(oldSelf.humanInput) && (!has(self.humanInput) || self.humanInput == oldSelf.humanInput)",message="humanInput is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.requireApproval) == has(oldSelf.requireApproval) && (!has(self.requireApproval) || self.requireApproval == oldSelf.requireApproval)",message="requireApproval is immutable"
+	// +kubebuilder:validation:XValidation:rule="has(self.artifacts) == has(oldSelf.artifacts) && (!has(self.artifacts) || self.artifacts == oldSelf.artifacts)",message="artifacts is immutable"
+	Spec TaskSpec `json:"spec,omitempty"`
+
 	Status TaskStatus `json:"status,omitempty"`
 }
 
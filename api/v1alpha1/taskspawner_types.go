@@ -14,6 +14,8 @@ const (
 	TaskSpawnerPhaseRunning TaskSpawnerPhase = "Running"
 	// TaskSpawnerPhaseFailed means the spawner has failed.
 	TaskSpawnerPhaseFailed TaskSpawnerPhase = "Failed"
+	// TaskSpawnerPhaseSuspended means discovery is paused because spec.suspend is set.
+	TaskSpawnerPhaseSuspended TaskSpawnerPhase = "Suspended"
 )
 
 // When defines the conditions that trigger task spawning.
@@ -22,6 +24,49 @@ type When struct {
 	// GitHubIssues discovers issues from a GitHub repository.
 	// +optional
 	GitHubIssues *GitHubIssues `json:"githubIssues,omitempty"`
+
+	// GitHubComments discovers commands in comments on the issues and pull
+	// requests of a GitHub repository.
+	// +optional
+	GitHubComments *GitHubComments `json:"githubComments,omitempty"`
+
+	// GitHubWorkflowRuns discovers failing GitHub Actions workflows of a
+	// GitHub repository.
+	// +optional
+	GitHubWorkflowRuns *GitHubWorkflowRuns `json:"githubWorkflowRuns,omitempty"`
+
+	// HTTP discovers items from a JSON API, like the one of an in-house
+	// ticket system.
+	// +optional
+	HTTP *HTTPSource `json:"http,omitempty"`
+
+	// JiraIssues discovers the issues of a Jira site that match a JQL
+	// query.
+	// +optional
+	JiraIssues *JiraIssues `json:"jiraIssues,omitempty"`
+
+	// LinearIssues discovers the issues of a Linear team.
+	// +optional
+	LinearIssues *LinearIssues `json:"linearIssues,omitempty"`
+}
+
+// WorkspaceRef returns the Workspace of the source that is set, if any.
+func (w *When) WorkspaceRef() *WorkspaceReference {
+	switch {
+	case w.GitHubIssues != nil:
+		return w.GitHubIssues.WorkspaceRef
+	case w.GitHubComments != nil:
+		return w.GitHubComments.WorkspaceRef
+	case w.GitHubWorkflowRuns != nil:
+		return w.GitHubWorkflowRuns.WorkspaceRef
+	case w.HTTP != nil:
+		return w.HTTP.WorkspaceRef
+	case w.JiraIssues != nil:
+		return w.JiraIssues.WorkspaceRef
+	case w.LinearIssues != nil:
+		return w.LinearIssues.WorkspaceRef
+	}
+	return nil
 }
 
 // GitHubIssues discovers issues from a GitHub repository.
@@ -51,6 +96,313 @@ type GitHubIssues struct {
 	// +kubebuilder:default=open
 	// +optional
 	State string `json:"state,omitempty"`
+
+	// Assignee filters issues by the login of a user they are assigned to.
+	// "@me" stands for the user the spawner's GitHub token belongs to, "*"
+	// for issues assigned to anyone and "none" for unassigned issues.
+	// +optional
+	Assignee string `json:"assignee,omitempty"`
+
+	// Author filters issues by the login of the user who opened them.
+	// +optional
+	Author string `json:"author,omitempty"`
+
+	// AuthorAssociations filters issues by the association of their author
+	// with the repository (client-side). Allowing only OWNER, MEMBER and
+	// COLLABORATOR keeps outsiders from putting prompts in front of agents.
+	// +kubebuilder:validation:Items:Enum=OWNER;MEMBER;COLLABORATOR;CONTRIBUTOR;FIRST_TIME_CONTRIBUTOR;FIRST_TIMER;MANNEQUIN;NONE
+	// +optional
+	AuthorAssociations []string `json:"authorAssociations,omitempty"`
+
+	// Milestone filters issues by the title of their milestone. "*" stands
+	// for issues with any milestone and "none" for issues without one.
+	// +optional
+	Milestone string `json:"milestone,omitempty"`
+
+	// CreatedWithin filters out issues created longer ago than this
+	// (client-side).
+	// +optional
+	CreatedWithin *metav1.Duration `json:"createdWithin,omitempty"`
+
+	// UpdatedWithin filters out issues last updated longer ago than this
+	// (client-side).
+	// +optional
+	UpdatedWithin *metav1.Duration `json:"updatedWithin,omitempty"`
+
+	// Query is a GitHub search query, like "-label:wontfix in:title crash",
+	// that issues must match in addition to the other filters. The
+	// repository is added to it. Issues are then discovered with the search
+	// API, which is searched anew on every poll.
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// API selects the GitHub API issues are discovered with. The graphql API
+	// fetches the issues along with their comments, assignees, milestone
+	// and linked pull requests in one paginated query, instead of fetching
+	// the comments of each issue with a separate rest API request.
+	// +kubebuilder:validation:Enum=rest;graphql
+	// +kubebuilder:default=rest
+	// +optional
+	API string `json:"api,omitempty"`
+
+	// PersistCache stores the issues the spawner discovered so far and its
+	// cache of GitHub API responses in the ConfigMap
+	// <taskspawner>-github-cache, so that a restarted spawner does not list
+	// every issue again. Otherwise they are only kept in memory: after the
+	// first listing, only issues updated since the last poll are fetched.
+	// +optional
+	PersistCache bool `json:"persistCache,omitempty"`
+}
+
+// GitHubComments discovers commands in comments on the issues and pull
+// requests of a GitHub repository, like "/axon review focus on tests". A Task
+// is created for every command, which the spawner acknowledges with an
+// "eyes" reaction on the comment. Comments made before the spawner first ran
+// are ignored. Where the discovery continues is kept in the ConfigMap
+// <taskspawner>-github-cache, so that a restarted spawner picks up there.
+type GitHubComments struct {
+	// WorkspaceRef references the Workspace that defines the GitHub repository.
+	// +kubebuilder:validation:Required
+	WorkspaceRef *WorkspaceReference `json:"workspaceRef"`
+
+	// Command starts the first line of the comments that are commands. The
+	// rest of the line are the command's arguments, available to the
+	// prompt template as {{.Args}}.
+	// +kubebuilder:validation:Pattern=`^\S+$`
+	// +kubebuilder:default="/axon"
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// Types specifies on which items commands are accepted: "issues",
+	// "pulls", or both.
+	// +kubebuilder:validation:Items:Enum=issues;pulls
+	// +kubebuilder:default={"issues","pulls"}
+	// +optional
+	Types []string `json:"types,omitempty"`
+
+	// AuthorAssociations are the associations with the repository of the
+	// users whose commands are accepted.
+	// +kubebuilder:validation:Items:Enum=OWNER;MEMBER;COLLABORATOR;CONTRIBUTOR;FIRST_TIME_CONTRIBUTOR;FIRST_TIMER;MANNEQUIN;NONE
+	// +kubebuilder:default={"OWNER","MEMBER","COLLABORATOR"}
+	// +optional
+	AuthorAssociations []string `json:"authorAssociations,omitempty"`
+
+	// Users are the logins of further users whose commands are accepted.
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
+// GitHubWorkflowRuns discovers failing GitHub Actions workflows. For every
+// workflow and branch, the latest run that succeeded or failed is looked
+// at; if it failed, a Task is created for the run, with the failed jobs and
+// the end of their logs. A workflow that keeps failing gets a Task for
+// every new run, or re-run of a run, that fails.
+type GitHubWorkflowRuns struct {
+	// WorkspaceRef references the Workspace that defines the GitHub repository.
+	// +kubebuilder:validation:Required
+	WorkspaceRef *WorkspaceReference `json:"workspaceRef"`
+
+	// Workflows are the file names or IDs of the workflows to watch, like
+	// "ci.yaml". All workflows are watched if none are set.
+	// +optional
+	Workflows []string `json:"workflows,omitempty"`
+
+	// Branches are the branches to watch. Defaults to the default branch of
+	// the repository.
+	// +optional
+	Branches []string `json:"branches,omitempty"`
+}
+
+// HTTPSource discovers items from a JSON API. The URL is fetched on every
+// poll, page by page, and every element of the response the ItemsPath
+// selects is an item, whose fields are extracted with JSONPath expressions
+// like "{.fields.summary}".
+type HTTPSource struct {
+	// URL is the URL items are listed from.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Headers are sent with every request, e.g. for authentication.
+	// +optional
+	Headers []HTTPHeader `json:"headers,omitempty"`
+
+	// ItemsPath is a JSONPath expression selecting the items in the
+	// response, like "{.issues[*]}". Defaults to the response itself,
+	// which must then be an array.
+	// +optional
+	ItemsPath string `json:"itemsPath,omitempty"`
+
+	// Fields map the fields of an item to those of the spawned Task's
+	// prompt.
+	// +kubebuilder:validation:Required
+	Fields HTTPFields `json:"fields"`
+
+	// Pagination is how the pages of items after the first are fetched.
+	// +optional
+	Pagination *HTTPPagination `json:"pagination,omitempty"`
+
+	// WorkspaceRef optionally references the Workspace the spawned Tasks
+	// work in.
+	// +optional
+	WorkspaceRef *WorkspaceReference `json:"workspaceRef,omitempty"`
+}
+
+// HTTPHeader is a header sent with the requests of an HTTP source.
+type HTTPHeader struct {
+	// Name is the name of the header.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9-]+$`
+	Name string `json:"name"`
+
+	// Value is the value of the header. With a SecretKeyRef, it is
+	// prepended to the value of the Secret's key, like "Bearer ".
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretKeyRef selects the key of a Secret in the TaskSpawner's
+	// namespace that holds the value of the header.
+	// +optional
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	// Name is the name of the secret.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Key is the key of the secret.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+// HTTPFields are the JSONPath expressions extracting the fields of an item
+// of an HTTP source, evaluated against the item.
+type HTTPFields struct {
+	// ID identifies the item, like "{.key}". The spawned Task is named
+	// after it.
+	// +kubebuilder:validation:Required
+	ID string `json:"id"`
+
+	// Number is the number of the item, if it has one.
+	// +optional
+	Number string `json:"number,omitempty"`
+
+	// Title is the title of the item.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Body is the description of the item.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// URL is the web page of the item.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Labels are the labels of the item, like "{.labels[*].name}".
+	// +optional
+	Labels string `json:"labels,omitempty"`
+}
+
+// HTTPPagination is how the pages of an HTTP source after the first are
+// fetched. At most 10 pages are fetched per poll. Next pages must be on the
+// scheme and host of the source's URL, so that its headers are not sent
+// elsewhere.
+type HTTPPagination struct {
+	// Type is the pagination scheme: "link" follows the URL of the Link
+	// header with rel="next", "nextURL" the URL the Path selects in the
+	// response, "cursor" sends the cursor the Path selects in the response
+	// as the query parameter Param, and "page" counts the query parameter
+	// Param up from 1 until a page has no items. There is no next page
+	// once the Path selects nothing.
+	// +kubebuilder:validation:Enum=link;nextURL;cursor;page
+	// +kubebuilder:validation:Required
+	Type string `json:"type"`
+
+	// Path is a JSONPath expression selecting the URL of the next page or
+	// its cursor in the response, like "{.next}".
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Param is the query parameter the cursor or page number is sent as.
+	// +optional
+	Param string `json:"param,omitempty"`
+}
+
+// JiraIssues discovers the issues of a Jira Cloud, Server or Data Center
+// site that match a JQL query. Tasks are named after the issue keys, and
+// descriptions and comments are converted to Markdown.
+type JiraIssues struct {
+	// URL is the URL of the Jira site, like "https://example.atlassian.net".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// JQL selects the issues to discover, like
+	// "project = PROJ AND labels = axon AND statusCategory != Done".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	JQL string `json:"jql"`
+
+	// APIVersion is the version of the REST API: "3" for Jira Cloud, "2"
+	// for Jira Server and Data Center.
+	// +kubebuilder:validation:Enum="2";"3"
+	// +kubebuilder:default="3"
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// SecretRef references a Secret in the TaskSpawner's namespace with
+	// the JIRA_TOKEN key. With a JIRA_USER key, like the email address of
+	// a Jira Cloud account, the API token is sent with basic
+	// authentication; without one, JIRA_TOKEN is a personal access token
+	// sent as bearer token.
+	// +optional
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// WorkspaceRef optionally references the Workspace the spawned Tasks
+	// work in.
+	// +optional
+	WorkspaceRef *WorkspaceReference `json:"workspaceRef,omitempty"`
+}
+
+// LinearIssues discovers the issues of a Linear team with Linear's GraphQL
+// API. Tasks are named after the issue identifiers, like "ENG-123". The
+// onComplete and onFailure actions can move the issues to another workflow
+// state and comment on them.
+type LinearIssues struct {
+	// Team is the key of the team, like "ENG".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Team string `json:"team"`
+
+	// States are the names of the workflow states of the issues to
+	// discover, like "Todo". Defaults to the states that are neither
+	// completed nor canceled.
+	// +optional
+	States []string `json:"states,omitempty"`
+
+	// Labels are the names of labels the issues must all have, like
+	// "Agent-ready".
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Assignee filters issues by the email address of the user they are
+	// assigned to. "none" selects unassigned issues.
+	// +optional
+	Assignee string `json:"assignee,omitempty"`
+
+	// SecretRef references a Secret in the TaskSpawner's namespace with
+	// the LINEAR_API_KEY key: a personal API key, or an OAuth access token
+	// prefixed with "Bearer ".
+	// +kubebuilder:validation:Required
+	SecretRef *SecretReference `json:"secretRef"`
+
+	// WorkspaceRef optionally references the Workspace the spawned Tasks
+	// work in.
+	// +optional
+	WorkspaceRef *WorkspaceReference `json:"workspaceRef,omitempty"`
 }
 
 // TaskTemplate defines the template for spawned Tasks.
@@ -60,15 +412,25 @@ type TaskTemplate struct {
 	Type string `json:"type"`
 
 	// Credentials specifies how to authenticate with the agent.
-	// +kubebuilder:validation:Required
-	Credentials Credentials `json:"credentials"`
+	// If unset, the credentials from the namespace's AxonConfig are used.
+	// +optional
+	Credentials *Credentials `json:"credentials,omitempty"`
 
 	// Model optionally overrides the default model.
 	// +optional
 	Model string `json:"model,omitempty"`
 
 	// PromptTemplate is a Go text/template for rendering the task prompt.
-	// Available variables: {{.Number}}, {{.Title}}, {{.Body}}, {{.URL}}, {{.Comments}}, {{.Labels}}, {{.Kind}}.
+	// Available variables: {{.Number}}, {{.Title}}, {{.Body}}, {{.URL}}, {{.Comments}}, {{.Labels}}, {{.Kind}},
+	// {{.Author}}, {{.Assignees}}, {{.Milestone}}, {{.LinkedPRs}} and {{.CommentList}}, whose
+	// elements have an Author, Body and CreatedAt. LinkedPRs are only discovered with the graphql API.
+	// For pull requests, {{.Branch}} and {{.BaseBranch}} are its branches, {{.Diff}} its diff,
+	// {{.Reviews}} and {{.ReviewComments}} the review feedback and {{.FailedChecks}} the failed check runs.
+	// For commands in comments, {{.Args}} are the command's arguments and {{.Requester}} the commenter.
+	// For workflow runs, {{.Workflow}}, {{.Branch}} and {{.Commit}} are what failed where, {{.FailedChecks}}
+	// the failed jobs and {{.Logs}} the end of their logs; {{.Number}} is 0.
+	// For Jira issues, {{.ID}} is the issue key and {{.Components}} its components; {{.Number}} is 0.
+	// For Linear issues, {{.ID}} is the issue identifier; {{.Number}} is 0.
 	// +optional
 	PromptTemplate string `json:"promptTemplate,omitempty"`
 
@@ -82,6 +444,43 @@ type TaskTemplate struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// HumanInput lets spawned agents ask a human for a decision. For Tasks
+	// spawned from GitHub issues, the question is posted as a comment on
+	// the issue and a "/answer <text>" comment answers it.
+	// +optional
+	HumanInput *HumanInputPolicy `json:"humanInput,omitempty"`
+}
+
+// CompletionActions are applied by the spawner to the issue or pull request
+// a Task was spawned for once the Task finished. Linear issues support only
+// State and Comment.
+type CompletionActions struct {
+	// AddLabels are added to the item.
+	// +optional
+	AddLabels []string `json:"addLabels,omitempty"`
+
+	// RemoveLabels are removed from the item, if present.
+	// +optional
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+
+	// Comment is a Go text/template for a comment posted on the item.
+	// Available variables: {{.Number}}, {{.Task}}, {{.Namespace}}, {{.Phase}}, {{.Message}}, {{.FailureReason}}, {{.CostUSD}}, {{.NumTurns}}, {{.Duration}}.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+
+	// Assignees are GitHub users assigned to the item.
+	// +optional
+	Assignees []string `json:"assignees,omitempty"`
+
+	// Close closes the item.
+	// +optional
+	Close bool `json:"close,omitempty"`
+
+	// State is the name of the workflow state Linear issues are moved to,
+	// like "In Review".
+	// +optional
+	State string `json:"state,omitempty"`
 }
 
 // TaskSpawnerSpec defines the desired state of TaskSpawner.
@@ -98,6 +497,54 @@ type TaskSpawnerSpec struct {
 	// +kubebuilder:default="5m"
 	// +optional
 	PollInterval string `json:"pollInterval,omitempty"`
+
+	// Suspend pauses discovery without deleting the spawner Deployment.
+	// Existing Tasks are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Notifications are sent for the Tasks created by this TaskSpawner, in
+	// addition to the notifications of the namespace's AxonConfigs.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	Notifications []Notification `json:"notifications,omitempty"`
+
+	// OnComplete is applied to the item a Task was spawned for once the
+	// Task succeeded. Only GitHub issues and pull requests, including
+	// those of comment commands, and Linear issues support it.
+	// +optional
+	OnComplete *CompletionActions `json:"onComplete,omitempty"`
+
+	// OnFailure is applied to the item a Task was spawned for once the
+	// Task failed, including when the agent crashed.
+	// +optional
+	OnFailure *CompletionActions `json:"onFailure,omitempty"`
+}
+
+// RateLimitStatus is the state of the source's API rate limit.
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed per window.
+	Limit int `json:"limit"`
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int `json:"remaining"`
+
+	// ResetTime is when the current window ends.
+	// +optional
+	ResetTime *metav1.Time `json:"resetTime,omitempty"`
+
+	// LastPollRequests is the number of requests the last poll used.
+	// Requests for unchanged resources are free.
+	// +optional
+	LastPollRequests int `json:"lastPollRequests,omitempty"`
+
+	// NextPollTime is when the source is polled next. It is later than
+	// the poll interval requires when polling at that interval would
+	// exhaust the rate limit.
+	// +optional
+	NextPollTime *metav1.Time `json:"nextPollTime,omitempty"`
 }
 
 // TaskSpawnerStatus defines the observed state of TaskSpawner.
@@ -125,6 +572,18 @@ type TaskSpawnerStatus struct {
 	// Message provides additional information about the current status.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// RateLimit is the state of the source's API rate limit as of the last
+	// poll.
+	// +optional
+	RateLimit *RateLimitStatus `json:"rateLimit,omitempty"`
+
+	// Conditions represent the latest observations of the TaskSpawner's
+	// state. The phase is derived from them.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,56 +21,685 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Artifact) DeepCopyInto(out *Artifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Artifact.
+func (in *Artifact) DeepCopy() *Artifact {
+	if in == nil {
+		return nil
+	}
+	out := new(Artifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactStore) DeepCopyInto(out *ArtifactStore) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3ArtifactStore)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PVCArtifactStore)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactStore.
+func (in *ArtifactStore) DeepCopy() *ArtifactStore {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactsSpec) DeepCopyInto(out *ArtifactsSpec) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Store != nil {
+		in, out := &in.Store, &out.Store
+		*out = new(ArtifactStore)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactsSpec.
+func (in *ArtifactsSpec) DeepCopy() *ArtifactsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxonConfig) DeepCopyInto(out *AxonConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxonConfig.
+func (in *AxonConfig) DeepCopy() *AxonConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AxonConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AxonConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxonConfigList) DeepCopyInto(out *AxonConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AxonConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxonConfigList.
+func (in *AxonConfigList) DeepCopy() *AxonConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(AxonConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AxonConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AxonConfigSpec) DeepCopyInto(out *AxonConfigSpec) {
+	*out = *in
+	if in.TaskDefaults != nil {
+		in, out := &in.TaskDefaults, &out.TaskDefaults
+		*out = new(TaskDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TranscriptArchive != nil {
+		in, out := &in.TranscriptArchive, &out.TranscriptArchive
+		*out = new(ArtifactStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]Notification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AxonConfigSpec.
+func (in *AxonConfigSpec) DeepCopy() *AxonConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AxonConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompletionActions) DeepCopyInto(out *CompletionActions) {
+	*out = *in
+	if in.AddLabels != nil {
+		in, out := &in.AddLabels, &out.AddLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemoveLabels != nil {
+		in, out := &in.RemoveLabels, &out.RemoveLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Assignees != nil {
+		in, out := &in.Assignees, &out.Assignees
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompletionActions.
+func (in *CompletionActions) DeepCopy() *CompletionActions {
+	if in == nil {
+		return nil
+	}
+	out := new(CompletionActions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Credentials) DeepCopyInto(out *Credentials) {
 	*out = *in
-	out.SecretRef = in.SecretRef
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credentials.
+func (in *Credentials) DeepCopy() *Credentials {
+	if in == nil {
+		return nil
+	}
+	out := new(Credentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubCommentNotification) DeepCopyInto(out *GitHubCommentNotification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubCommentNotification.
+func (in *GitHubCommentNotification) DeepCopy() *GitHubCommentNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubCommentNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubComments) DeepCopyInto(out *GitHubComments) {
+	*out = *in
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthorAssociations != nil {
+		in, out := &in.AuthorAssociations, &out.AuthorAssociations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubComments.
+func (in *GitHubComments) DeepCopy() *GitHubComments {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubComments)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubIssues) DeepCopyInto(out *GitHubIssues) {
+	*out = *in
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeLabels != nil {
+		in, out := &in.ExcludeLabels, &out.ExcludeLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthorAssociations != nil {
+		in, out := &in.AuthorAssociations, &out.AuthorAssociations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreatedWithin != nil {
+		in, out := &in.CreatedWithin, &out.CreatedWithin
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.UpdatedWithin != nil {
+		in, out := &in.UpdatedWithin, &out.UpdatedWithin
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubIssues.
+func (in *GitHubIssues) DeepCopy() *GitHubIssues {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubIssues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubWorkflowRuns) DeepCopyInto(out *GitHubWorkflowRuns) {
+	*out = *in
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+	if in.Workflows != nil {
+		in, out := &in.Workflows, &out.Workflows
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubWorkflowRuns.
+func (in *GitHubWorkflowRuns) DeepCopy() *GitHubWorkflowRuns {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubWorkflowRuns)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPFields) DeepCopyInto(out *HTTPFields) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPFields.
+func (in *HTTPFields) DeepCopy() *HTTPFields {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
+func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPPagination) DeepCopyInto(out *HTTPPagination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPagination.
+func (in *HTTPPagination) DeepCopy() *HTTPPagination {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPPagination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSource) DeepCopyInto(out *HTTPSource) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Fields = in.Fields
+	if in.Pagination != nil {
+		in, out := &in.Pagination, &out.Pagination
+		*out = new(HTTPPagination)
+		**out = **in
+	}
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSource.
+func (in *HTTPSource) DeepCopy() *HTTPSource {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HumanInputPolicy) DeepCopyInto(out *HumanInputPolicy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HumanInputPolicy.
+func (in *HumanInputPolicy) DeepCopy() *HumanInputPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HumanInputPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputRequest) DeepCopyInto(out *InputRequest) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+	if in.AnsweredAt != nil {
+		in, out := &in.AnsweredAt, &out.AnsweredAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputRequest.
+func (in *InputRequest) DeepCopy() *InputRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(InputRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JiraIssues) DeepCopyInto(out *JiraIssues) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JiraIssues.
+func (in *JiraIssues) DeepCopy() *JiraIssues {
+	if in == nil {
+		return nil
+	}
+	out := new(JiraIssues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinearIssues) DeepCopyInto(out *LinearIssues) {
+	*out = *in
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinearIssues.
+func (in *LinearIssues) DeepCopy() *LinearIssues {
+	if in == nil {
+		return nil
+	}
+	out := new(LinearIssues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notification) DeepCopyInto(out *Notification) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]TaskPhase, len(*in))
+		copy(*out, *in)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackNotification)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookNotification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitHubComment != nil {
+		in, out := &in.GitHubComment, &out.GitHubComment
+		*out = new(GitHubCommentNotification)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
+func (in *Notification) DeepCopy() *Notification {
+	if in == nil {
+		return nil
+	}
+	out := new(Notification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationStatus) DeepCopyInto(out *NotificationStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credentials.
-func (in *Credentials) DeepCopy() *Credentials {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationStatus.
+func (in *NotificationStatus) DeepCopy() *NotificationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Credentials)
+	out := new(NotificationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitHubIssues) DeepCopyInto(out *GitHubIssues) {
+func (in *PVCArtifactStore) DeepCopyInto(out *PVCArtifactStore) {
 	*out = *in
-	if in.WorkspaceRef != nil {
-		in, out := &in.WorkspaceRef, &out.WorkspaceRef
-		*out = new(WorkspaceReference)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCArtifactStore.
+func (in *PVCArtifactStore) DeepCopy() *PVCArtifactStore {
+	if in == nil {
+		return nil
 	}
-	if in.Types != nil {
-		in, out := &in.Types, &out.Types
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(PVCArtifactStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodOverrides) DeepCopyInto(out *PodOverrides) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.ExcludeLabels != nil {
-		in, out := &in.ExcludeLabels, &out.ExcludeLabels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubIssues.
-func (in *GitHubIssues) DeepCopy() *GitHubIssues {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverrides.
+func (in *PodOverrides) DeepCopy() *PodOverrides {
 	if in == nil {
 		return nil
 	}
-	out := new(GitHubIssues)
+	out := new(PodOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitStatus) DeepCopyInto(out *RateLimitStatus) {
+	*out = *in
+	if in.ResetTime != nil {
+		in, out := &in.ResetTime, &out.ResetTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextPollTime != nil {
+		in, out := &in.NextPollTime, &out.NextPollTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitStatus.
+func (in *RateLimitStatus) DeepCopy() *RateLimitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3ArtifactStore) DeepCopyInto(out *S3ArtifactStore) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3ArtifactStore.
+func (in *S3ArtifactStore) DeepCopy() *S3ArtifactStore {
+	if in == nil {
+		return nil
+	}
+	out := new(S3ArtifactStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -90,6 +719,22 @@ func (in *SecretReference) DeepCopy() *SecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackNotification) DeepCopyInto(out *SlackNotification) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackNotification.
+func (in *SlackNotification) DeepCopy() *SlackNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Task) DeepCopyInto(out *Task) {
 	*out = *in
@@ -117,6 +762,56 @@ func (in *Task) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskDefaults) DeepCopyInto(out *TaskDefaults) {
+	*out = *in
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(Credentials)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PodOverrides != nil {
+		in, out := &in.PodOverrides, &out.PodOverrides
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedTools != nil {
+		in, out := &in.AllowedTools, &out.AllowedTools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisallowedTools != nil {
+		in, out := &in.DisallowedTools, &out.DisallowedTools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(ArtifactsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskDefaults.
+func (in *TaskDefaults) DeepCopy() *TaskDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskList) DeepCopyInto(out *TaskList) {
 	*out = *in
@@ -149,6 +844,21 @@ func (in *TaskList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskReference) DeepCopyInto(out *TaskReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskReference.
+func (in *TaskReference) DeepCopy() *TaskReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskSpawner) DeepCopyInto(out *TaskSpawner) {
 	*out = *in
@@ -213,6 +923,23 @@ func (in *TaskSpawnerSpec) DeepCopyInto(out *TaskSpawnerSpec) {
 	*out = *in
 	in.When.DeepCopyInto(&out.When)
 	in.TaskTemplate.DeepCopyInto(&out.TaskTemplate)
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]Notification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OnComplete != nil {
+		in, out := &in.OnComplete, &out.OnComplete
+		*out = new(CompletionActions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = new(CompletionActions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSpawnerSpec.
@@ -232,6 +959,18 @@ func (in *TaskSpawnerStatus) DeepCopyInto(out *TaskSpawnerStatus) {
 		in, out := &in.LastDiscoveryTime, &out.LastDiscoveryTime
 		*out = (*in).DeepCopy()
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSpawnerStatus.
@@ -247,7 +986,11 @@ func (in *TaskSpawnerStatus) DeepCopy() *TaskSpawnerStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskSpec) DeepCopyInto(out *TaskSpec) {
 	*out = *in
-	out.Credentials = in.Credentials
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(Credentials)
+		**out = **in
+	}
 	if in.WorkspaceRef != nil {
 		in, out := &in.WorkspaceRef, &out.WorkspaceRef
 		*out = new(WorkspaceReference)
@@ -258,6 +1001,41 @@ func (in *TaskSpec) DeepCopyInto(out *TaskSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PodOverrides != nil {
+		in, out := &in.PodOverrides, &out.PodOverrides
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedTools != nil {
+		in, out := &in.AllowedTools, &out.AllowedTools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisallowedTools != nil {
+		in, out := &in.DisallowedTools, &out.DisallowedTools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContinueFrom != nil {
+		in, out := &in.ContinueFrom, &out.ContinueFrom
+		*out = new(TaskReference)
+		**out = **in
+	}
+	if in.HumanInput != nil {
+		in, out := &in.HumanInput, &out.HumanInput
+		*out = new(HumanInputPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(ArtifactsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSpec.
@@ -281,6 +1059,45 @@ func (in *TaskStatus) DeepCopyInto(out *TaskStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InputRequest != nil {
+		in, out := &in.InputRequest, &out.InputRequest
+		*out = new(InputRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]Artifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.TranscriptArchive != nil {
+		in, out := &in.TranscriptArchive, &out.TranscriptArchive
+		*out = new(TranscriptArchive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(TaskUsage)
+		**out = **in
+	}
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(TaskSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStatus.
@@ -296,12 +1113,21 @@ func (in *TaskStatus) DeepCopy() *TaskStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskTemplate) DeepCopyInto(out *TaskTemplate) {
 	*out = *in
-	out.Credentials = in.Credentials
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(Credentials)
+		**out = **in
+	}
 	if in.TTLSecondsAfterFinished != nil {
 		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
 		*out = new(int32)
 		**out = **in
 	}
+	if in.HumanInput != nil {
+		in, out := &in.HumanInput, &out.HumanInput
+		*out = new(HumanInputPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTemplate.
@@ -314,6 +1140,57 @@ func (in *TaskTemplate) DeepCopy() *TaskTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskUsage) DeepCopyInto(out *TaskUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskUsage.
+func (in *TaskUsage) DeepCopy() *TaskUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TranscriptArchive) DeepCopyInto(out *TranscriptArchive) {
+	*out = *in
+	in.Store.DeepCopyInto(&out.Store)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TranscriptArchive.
+func (in *TranscriptArchive) DeepCopy() *TranscriptArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(TranscriptArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookNotification) DeepCopyInto(out *WebhookNotification) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookNotification.
+func (in *WebhookNotification) DeepCopy() *WebhookNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *When) DeepCopyInto(out *When) {
 	*out = *in
@@ -322,6 +1199,31 @@ func (in *When) DeepCopyInto(out *When) {
 		*out = new(GitHubIssues)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GitHubComments != nil {
+		in, out := &in.GitHubComments, &out.GitHubComments
+		*out = new(GitHubComments)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitHubWorkflowRuns != nil {
+		in, out := &in.GitHubWorkflowRuns, &out.GitHubWorkflowRuns
+		*out = new(GitHubWorkflowRuns)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JiraIssues != nil {
+		in, out := &in.JiraIssues, &out.JiraIssues
+		*out = new(JiraIssues)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LinearIssues != nil {
+		in, out := &in.LinearIssues, &out.LinearIssues
+		*out = new(LinearIssues)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new When.
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(axonv1alpha1.AddToScheme(scheme))
+}
+
+const usage = `usage:
+  axon-agent mcp                  serve the axon MCP tools on stdio
+  axon-agent run [flags] -- <cmd> run the agent command
+  axon-agent upload [flags]       upload the outputs staged by "run"`
+
+// axon-agent is a helper that runs inside the agent's Pod. It serves the
+// axon MCP tools to the agent and wraps the agent's command in the agent's
+// container, and uploads the agent's outputs from a container of its own.
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "mcp":
+		err = runMCP()
+	case "run":
+		var code int
+		code, err = runAgent(os.Args[2:])
+		if err == nil {
+			os.Exit(code)
+		}
+	case "upload":
+		err = runUpload(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "axon-agent: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runMCP() error {
+	cl, key, err := newTaskClient()
+	if err != nil {
+		return err
+	}
+
+	requester := &agent.InputRequester{
+		Client: cl,
+		Task:   key,
+	}
+	server := &agent.MCPServer{
+		Name:    "axon",
+		Version: "v1alpha1",
+		Tools:   []agent.Tool{agent.AskHumanTool(requester)},
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+func runAgent(args []string) (int, error) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	captureDiff := fs.Bool("capture-diff", false, "store the changes made to the repository in the Task's diff ConfigMap")
+	collectArtifacts := fs.Bool("collect-artifacts", false, "stage the transcript, the diff and the files matching --artifact-path in the outbox")
+	outbox := fs.String("outbox", "", "directory to stage outputs in for \"axon-agent upload\"")
+	archiveTranscript := fs.Bool("archive-transcript", false, "stage the compressed output of the command in the outbox to be archived")
+	usageFile := fs.String("usage-file", "", "write the usage from the command's result event to this file as JSON")
+	var artifactPaths stringsFlag
+	fs.Var(&artifactPaths, "artifact-path", "file, directory or glob pattern to upload as an artifact (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return 2, nil
+	}
+
+	runner := &agent.Runner{
+		CaptureDiff: *captureDiff,
+		Outbox:      *outbox,
+		UsageFile:   *usageFile,
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	}
+	if *captureDiff {
+		cl, key, err := newTaskClient()
+		if err != nil {
+			return 1, err
+		}
+		runner.Client = cl
+		runner.Task = key
+	}
+	if *collectArtifacts {
+		if *outbox == "" {
+			return 1, fmt.Errorf("--collect-artifacts requires --outbox")
+		}
+		runner.Artifacts = &agent.ArtifactCollector{
+			Paths: artifactPaths,
+		}
+	}
+	if *archiveTranscript {
+		if *outbox == "" {
+			return 1, fmt.Errorf("--archive-transcript requires --outbox")
+		}
+		runner.ArchiveTranscript = true
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	return runner.Run(ctx, fs.Args())
+}
+
+func runUpload(args []string) error {
+	fs := flag.NewFlagSet("upload", flag.ContinueOnError)
+	outbox := fs.String("outbox", "", "directory the outputs are staged in")
+	uploadArtifacts := fs.Bool("artifacts", false, "upload the staged artifacts to the artifact store and record them in the Task's status")
+	uploadTranscript := fs.Bool("transcript", false, "upload the staged transcript to the transcript archive")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *outbox == "" {
+		return fmt.Errorf("--outbox must be set")
+	}
+
+	uploader := &agent.Uploader{
+		Outbox: *outbox,
+		Stderr: os.Stderr,
+	}
+	if *uploadArtifacts {
+		cl, key, err := newTaskClient()
+		if err != nil {
+			return err
+		}
+		uploader.Client = cl
+		uploader.Task = key
+		store, prefix, err := artifact.StoreFromEnv(os.Getenv, artifact.ArtifactsEnvPrefix)
+		if err != nil {
+			return fmt.Errorf("artifact store: %w", err)
+		}
+		uploader.Artifacts = &agent.ArtifactUpload{
+			Store:  store,
+			Prefix: prefix,
+		}
+	}
+	if *uploadTranscript {
+		store, key, err := artifact.StoreFromEnv(os.Getenv, artifact.TranscriptEnvPrefix)
+		if err != nil {
+			return fmt.Errorf("transcript archive: %w", err)
+		}
+		uploader.Transcript = &agent.TranscriptArchiver{
+			Store: store,
+			Key:   key,
+		}
+	}
+
+	// The uploader runs as a sidecar, which is restarted if it exits, so
+	// it waits to be stopped once it is done
+	ctx := ctrl.SetupSignalHandler()
+	if err := uploader.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "axon-agent: %v\n", err)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// stringsFlag is a flag that may be given several times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// newTaskClient returns a client and the key of the Task the agent runs for.
+func newTaskClient() (client.Client, types.NamespacedName, error) {
+	name := os.Getenv("AXON_TASK_NAME")
+	namespace := os.Getenv("AXON_TASK_NAMESPACE")
+	if name == "" || namespace == "" {
+		return nil, types.NamespacedName{}, fmt.Errorf("AXON_TASK_NAME and AXON_TASK_NAMESPACE must be set")
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, types.NamespacedName{}, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	cl, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, types.NamespacedName{}, fmt.Errorf("creating client: %w", err)
+	}
+	return cl, types.NamespacedName{Name: name, Namespace: namespace}, nil
+}
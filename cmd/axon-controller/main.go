@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 
@@ -9,11 +10,15 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
 	"github.com/axon-core/axon/internal/controller"
+	"github.com/axon-core/axon/internal/webhook"
 )
 
 var (
@@ -32,19 +37,30 @@ func main() {
 	var probeAddr string
 	var claudeCodeImage string
 	var claudeCodeImagePullPolicy string
+	var reportAgentUsage bool
 	var spawnerImage string
 	var spawnerImagePullPolicy string
+	var enableWebhooks bool
+	var webhookPort int
+	var webhookCertDir string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.StringVar(&claudeCodeImage, "claude-code-image", controller.ClaudeCodeImage, "The image to use for Claude Code agent containers.")
+	flag.StringVar(&claudeCodeImage, "claude-code-image", controller.ClaudeCodeImage, "The image to use for Claude Code agent containers. "+
+		"Tasks requiring approval, asking for human input, collecting artifacts or archiving transcripts need the axon-agent helper at /usr/local/bin/axon-agent in it.")
+	flag.BoolVar(&reportAgentUsage, "report-agent-usage", true, "Run every agent under the axon-agent helper to report its usage. "+
+		"Only applies to a custom --claude-code-image if set explicitly, since the image must include the helper.")
 	flag.StringVar(&claudeCodeImagePullPolicy, "claude-code-image-pull-policy", "", "The image pull policy for Claude Code agent containers (e.g., Always, Never, IfNotPresent).")
 	flag.StringVar(&spawnerImage, "spawner-image", controller.DefaultSpawnerImage, "The image to use for spawner Deployments.")
 	flag.StringVar(&spawnerImagePullPolicy, "spawner-image-pull-policy", "", "The image pull policy for spawner Deployments (e.g., Always, Never, IfNotPresent).")
 
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", true, "Serve the validating and defaulting admission webhooks.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server listens on.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory the webhook serving certificate is written to.")
+
 	opts := zap.Options{
 		Development: true,
 	}
@@ -53,24 +69,62 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	cfg := ctrl.GetConfigOrDie()
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "axon-controller-leader-election",
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			namespace = "axon-system"
+		}
+		// The Manager's cache is not started yet, so the certificate is
+		// provisioned with a direct client
+		c, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		if err := (&webhook.CertProvisioner{
+			Client:     c,
+			Namespace:  namespace,
+			SecretName: "axon-webhook-cert",
+			CertDir:    webhookCertDir,
+		}).Provision(context.Background()); err != nil {
+			setupLog.Error(err, "unable to provision webhook certificate")
+			os.Exit(1)
+		}
+		if err := webhook.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up webhooks")
+			os.Exit(1)
+		}
+	}
+
 	jobBuilder := controller.NewJobBuilder()
 	jobBuilder.ClaudeCodeImage = claudeCodeImage
 	jobBuilder.ClaudeCodeImagePullPolicy = corev1.PullPolicy(claudeCodeImagePullPolicy)
+	// Custom images may lack the helper and keep their entrypoint, unless
+	// usage reporting was asked for
+	jobBuilder.ReportUsage = reportAgentUsage && (claudeCodeImage == controller.ClaudeCodeImage || flagSet("report-agent-usage"))
 	if err = (&controller.TaskReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
 		JobBuilder: jobBuilder,
+		Recorder:   mgr.GetEventRecorder("axon-controller"),
+		APIReader:  mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Task")
 		os.Exit(1)
@@ -83,6 +137,7 @@ func main() {
 		Client:            mgr.GetClient(),
 		Scheme:            mgr.GetScheme(),
 		DeploymentBuilder: deploymentBuilder,
+		Recorder:          mgr.GetEventRecorder("axon-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TaskSpawner")
 		os.Exit(1)
@@ -103,3 +158,15 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// flagSet reports whether the flag with the given name was set on the
+// command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/source"
+)
+
+const (
+	// cacheConfigMapKey and indexConfigMapKey are the keys of the ConfigMap
+	// holding the cached GitHub API responses and the item index.
+	cacheConfigMapKey = "responses.json.gz"
+	indexConfigMapKey = "items.json.gz"
+
+	// commentsSinceConfigMapKey is the key of the ConfigMap holding where
+	// the discovery of commands in comments continues, in RFC 3339.
+	commentsSinceConfigMapKey = "comments-since"
+
+	// maxPersistedCacheBytes keeps the ConfigMap well below the 1MiB size
+	// limit of ConfigMaps. The item index is persisted in preference to
+	// the responses; what does not fit is only kept in memory.
+	maxPersistedCacheBytes = 900 * 1024
+
+	// cacheRetention is how long a GitHub API response that is not used is
+	// kept. The comments of an issue are only fetched again when the issue
+	// changed.
+	cacheRetention = 7 * 24 * time.Hour
+)
+
+// discoveryCache is the spawner's cache of GitHub API responses and index
+// of discovered items. It lives as long as the spawner, and is persisted in
+// a ConfigMap if the TaskSpawner sets persistCache. The position of the
+// discovery of commands in comments is always persisted, so that a restarted
+// spawner neither misses commands nor scans the history again.
+type discoveryCache struct {
+	responses *source.ResponseCache
+	index     *source.ItemIndex
+
+	// commentsSince is where the discovery of commands in comments
+	// continues, once it ran.
+	commentsSince time.Time
+	// workflowRuns holds the items of the failed workflow runs discovered
+	// before.
+	workflowRuns map[string]source.WorkItem
+
+	// loaded records whether the persisted cache was loaded.
+	loaded bool
+	// saved is the ConfigMap data as last persisted.
+	saved map[string][]byte
+}
+
+func newDiscoveryCache() *discoveryCache {
+	return &discoveryCache{
+		responses:    source.NewResponseCache(),
+		index:        source.NewItemIndex(),
+		workflowRuns: map[string]source.WorkItem{},
+	}
+}
+
+// persistsCache reports whether the TaskSpawner's cache is persisted.
+func persistsCache(ts *axonv1alpha1.TaskSpawner) bool {
+	if gh := ts.Spec.When.GitHubIssues; gh != nil && gh.PersistCache {
+		return true
+	}
+	return ts.Spec.When.GitHubComments != nil
+}
+
+// cacheConfigMapName returns the name of the ConfigMap the TaskSpawner's
+// cache is persisted in.
+func cacheConfigMapName(ts *axonv1alpha1.TaskSpawner) string {
+	return ts.Name + "-github-cache"
+}
+
+// load restores the persisted cache, once. A missing ConfigMap leaves the
+// cache empty, as does a corrupt key.
+func (c *discoveryCache) load(ctx context.Context, cl client.Client, ts *axonv1alpha1.TaskSpawner) error {
+	if c.loaded {
+		return nil
+	}
+	c.loaded = true
+
+	var cm corev1.ConfigMap
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: cacheConfigMapName(ts)}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("fetching cache ConfigMap: %w", err)
+	}
+	if data := cm.BinaryData[cacheConfigMapKey]; len(data) > 0 {
+		if err := c.responses.UnmarshalBinary(data); err != nil {
+			return err
+		}
+	}
+	if data := cm.BinaryData[indexConfigMapKey]; len(data) > 0 {
+		if err := c.index.UnmarshalBinary(data); err != nil {
+			return err
+		}
+	}
+	if data := cm.BinaryData[commentsSinceConfigMapKey]; len(data) > 0 {
+		since, err := time.Parse(time.RFC3339, string(data))
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", commentsSinceConfigMapKey, err)
+		}
+		c.commentsSince = since
+	}
+	c.saved = cm.BinaryData
+	return nil
+}
+
+// save persists the cache if it changed since it was last persisted. The
+// item index and the responses are only persisted if the TaskSpawner sets
+// persistCache. The ConfigMap is owned by the TaskSpawner, so it is deleted
+// with it.
+func (c *discoveryCache) save(ctx context.Context, cl client.Client, ts *axonv1alpha1.TaskSpawner) error {
+	data := map[string][]byte{}
+	if gh := ts.Spec.When.GitHubIssues; gh != nil && gh.PersistCache {
+		index, err := c.index.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if len(index) > maxPersistedCacheBytes {
+			return fmt.Errorf("item index of %d bytes exceeds the %d bytes a ConfigMap can hold", len(index), maxPersistedCacheBytes)
+		}
+		data[indexConfigMapKey] = index
+
+		responses, err := c.responses.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if len(index)+len(responses) <= maxPersistedCacheBytes {
+			data[cacheConfigMapKey] = responses
+		} else {
+			ctrl.Log.WithName("spawner").Info("Not persisting the GitHub API responses, which do not fit in the cache ConfigMap", "bytes", len(responses))
+		}
+	}
+	if !c.commentsSince.IsZero() {
+		data[commentsSinceConfigMapKey] = []byte(c.commentsSince.UTC().Format(time.RFC3339))
+	}
+
+	if len(data) == 0 || maps.EqualFunc(data, c.saved, bytes.Equal) {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cacheConfigMapName(ts),
+			Namespace: ts.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, cl, cm, func() error {
+		cm.Labels = map[string]string{"axon.io/taskspawner": ts.Name}
+		cm.Data = nil
+		cm.BinaryData = data
+		return controllerutil.SetOwnerReference(ts, cm, cl.Scheme())
+	}); err != nil {
+		return fmt.Errorf("saving cache ConfigMap: %w", err)
+	}
+	c.saved = data
+	return nil
+}
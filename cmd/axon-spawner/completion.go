@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/source"
+)
+
+const (
+	// completionActionsAnnotation tracks the completion actions of a
+	// spawned Task. It is set to completionActionsPending when the Task is
+	// created while the TaskSpawner has completion actions, and to
+	// completionActionsApplied once they were applied. The controller does
+	// not delete a pending Task when its TTL expires.
+	completionActionsAnnotation = "axon.io/completion-actions"
+	completionActionsPending    = "Pending"
+	completionActionsApplied    = "Applied"
+)
+
+// Reasons of the Events recorded for completion actions.
+const (
+	reasonCompletionActionsApplied = "CompletionActionsApplied"
+	reasonCompletionActionsFailed  = "CompletionActionsFailed"
+)
+
+// hasCompletionActions reports whether the TaskSpawner acts on the items of
+// finished Tasks.
+func hasCompletionActions(ts *axonv1alpha1.TaskSpawner) bool {
+	return ts.Spec.OnComplete != nil || ts.Spec.OnFailure != nil
+}
+
+// itemUpdater applies completion actions to the items Tasks were spawned
+// for.
+type itemUpdater interface {
+	// item names the item of the Task, like "#42", or reports false if the
+	// Task has no item to apply actions to.
+	item(task *axonv1alpha1.Task) (string, bool)
+	apply(ctx context.Context, actions *axonv1alpha1.CompletionActions, task *axonv1alpha1.Task) error
+}
+
+// completionItems returns the itemUpdater for the items of the source, if
+// completion actions can be applied to them: GitHub issues and pull
+// requests, and Linear issues.
+func completionItems(src source.Source) (itemUpdater, bool) {
+	switch s := src.(type) {
+	case *source.GitHubSource:
+		return githubItems{s}, true
+	case *source.GitHubCommentsSource:
+		return githubItems{s.GitHubSource}, true
+	case *source.LinearSource:
+		return linearItems{s}, true
+	}
+	return nil, false
+}
+
+// applyCompletionActions applies the TaskSpawner's onComplete or onFailure
+// actions to the items of the given Tasks that finished since they were
+// last applied. Actions that fail are retried in the next cycle.
+func applyCompletionActions(ctx context.Context, cl client.Client, recorder events.EventRecorder, items itemUpdater, ts *axonv1alpha1.TaskSpawner, tasks []axonv1alpha1.Task) {
+	log := ctrl.Log.WithName("spawner")
+
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Annotations[completionActionsAnnotation] != completionActionsPending {
+			continue
+		}
+
+		var actions *axonv1alpha1.CompletionActions
+		switch task.Status.Phase {
+		case axonv1alpha1.TaskPhaseSucceeded:
+			actions = ts.Spec.OnComplete
+		case axonv1alpha1.TaskPhaseFailed:
+			actions = ts.Spec.OnFailure
+		default:
+			continue
+		}
+
+		// Tasks without an item have nothing to apply the actions to
+		if item, ok := items.item(task); actions != nil && ok {
+			if err := items.apply(ctx, actions, task); err != nil {
+				log.Error(err, "applying completion actions", "task", task.Name, "item", item)
+				recorder.Eventf(ts, task, corev1.EventTypeWarning, reasonCompletionActionsFailed, "ApplyCompletionActions",
+					"Applying completion actions of Task %s to %s failed: %v", task.Name, item, err)
+				continue
+			}
+			log.Info("applied completion actions", "task", task.Name, "item", item, "phase", task.Status.Phase)
+			recorder.Eventf(ts, task, corev1.EventTypeNormal, reasonCompletionActionsApplied, "ApplyCompletionActions",
+				"Applied completion actions of %s Task %s to %s", task.Status.Phase, task.Name, item)
+		}
+
+		patch := client.MergeFrom(task.DeepCopy())
+		task.Annotations[completionActionsAnnotation] = completionActionsApplied
+		if err := cl.Patch(ctx, task, patch); err != nil {
+			log.Error(err, "recording applied completion actions", "task", task.Name)
+		}
+	}
+}
+
+// githubItems applies completion actions to the GitHub issues and pull
+// requests Tasks were spawned for.
+type githubItems struct {
+	gh *source.GitHubSource
+}
+
+func (g githubItems) item(task *axonv1alpha1.Task) (string, bool) {
+	number, err := strconv.Atoi(task.Annotations[sourceNumberAnnotation])
+	if err != nil {
+		return "", false
+	}
+	return "#" + strconv.Itoa(number), true
+}
+
+// apply applies the actions to the issue or pull request. The idempotent
+// label and assignee changes come first, so that retrying them after a
+// failure does not post the comment twice.
+func (g githubItems) apply(ctx context.Context, actions *axonv1alpha1.CompletionActions, task *axonv1alpha1.Task) error {
+	gh := g.gh
+	number, err := strconv.Atoi(task.Annotations[sourceNumberAnnotation])
+	if err != nil {
+		return err
+	}
+	for _, label := range actions.RemoveLabels {
+		if err := gh.RemoveLabel(ctx, number, label); err != nil {
+			return err
+		}
+	}
+	if len(actions.AddLabels) > 0 {
+		if err := gh.AddLabels(ctx, number, actions.AddLabels); err != nil {
+			return err
+		}
+	}
+	if len(actions.Assignees) > 0 {
+		if err := gh.AddAssignees(ctx, number, actions.Assignees); err != nil {
+			return err
+		}
+	}
+	if actions.Comment != "" {
+		body, err := source.RenderComment(actions.Comment, taskResult(task, number))
+		if err != nil {
+			return err
+		}
+		if err := gh.CreateComment(ctx, number, body); err != nil {
+			return err
+		}
+	}
+	if actions.Close {
+		if err := gh.Close(ctx, number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linearItems applies completion actions to the Linear issues Tasks were
+// spawned for. Issues are moved to the state before the comment is posted,
+// since moving them again is harmless.
+type linearItems struct {
+	linear *source.LinearSource
+}
+
+func (l linearItems) item(task *axonv1alpha1.Task) (string, bool) {
+	id := task.Annotations[sourceIDAnnotation]
+	return id, id != ""
+}
+
+func (l linearItems) apply(ctx context.Context, actions *axonv1alpha1.CompletionActions, task *axonv1alpha1.Task) error {
+	id := task.Annotations[sourceIDAnnotation]
+	if actions.State != "" {
+		if err := l.linear.SetState(ctx, id, actions.State); err != nil {
+			return err
+		}
+	}
+	if actions.Comment != "" {
+		body, err := source.RenderComment(actions.Comment, taskResult(task, 0))
+		if err != nil {
+			return err
+		}
+		if err := l.linear.CreateComment(ctx, id, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskResult describes the finished Task for the comment template.
+func taskResult(task *axonv1alpha1.Task, number int) source.TaskResult {
+	result := source.TaskResult{
+		Number:        number,
+		Task:          task.Name,
+		Namespace:     task.Namespace,
+		Phase:         string(task.Status.Phase),
+		Message:       task.Status.Message,
+		FailureReason: string(task.Status.FailureReason),
+	}
+	if u := task.Status.Usage; u != nil {
+		result.CostUSD = u.CostUSD
+		result.NumTurns = u.NumTurns
+	}
+	if start, end := task.Status.StartTime, task.Status.CompletionTime; start != nil && end != nil {
+		result.Duration = end.Sub(start.Time).Round(time.Second).String()
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/source"
+)
+
+const (
+	// sourceNumberAnnotation records the number of the GitHub issue or pull
+	// request a Task was spawned for. It is only set for Tasks of GitHub
+	// issues and comments sources.
+	sourceNumberAnnotation = "axon.io/source-number"
+
+	// sourceIDAnnotation records the ID of the item a Task was spawned
+	// for, like the identifier of a Linear issue.
+	sourceIDAnnotation = "axon.io/source-id"
+
+	// inputRequestPostedAnnotation records the ID of the last input request
+	// whose question was posted as a comment.
+	inputRequestPostedAnnotation = "axon.io/input-request-posted"
+
+	// answerCommand prefixes a comment that answers an agent's question.
+	answerCommand = "/answer"
+)
+
+// syncInputRequests relays the pending questions of the given Tasks to the
+// GitHub issues they were spawned for, and answers them from "/answer"
+// comments on those issues. Only the answers of the users, or of the
+// authors with the associations, are accepted, like commands in comments.
+func syncInputRequests(ctx context.Context, cl client.Client, gh *source.GitHubSource, users, associations []string, tasks []axonv1alpha1.Task) {
+	log := ctrl.Log.WithName("spawner")
+
+	for i := range tasks {
+		task := &tasks[i]
+		req := task.Status.InputRequest
+		if req == nil || req.AnsweredAt != nil {
+			continue
+		}
+		number, err := strconv.Atoi(task.Annotations[sourceNumberAnnotation])
+		if err != nil {
+			continue
+		}
+
+		if task.Annotations[inputRequestPostedAnnotation] != req.ID {
+			body := fmt.Sprintf("**The agent working on this needs input** (Task `%s`, question `%s`)\n\n%s\n\nReply with `%s <your answer>` to let it continue.",
+				task.Name, req.ID, quote(req.Question), answerCommand)
+			if err := gh.CreateComment(ctx, number, body); err != nil {
+				log.Error(err, "posting input request", "task", task.Name, "number", number)
+				continue
+			}
+			patch := client.MergeFrom(task.DeepCopy())
+			if task.Annotations == nil {
+				task.Annotations = map[string]string{}
+			}
+			task.Annotations[inputRequestPostedAnnotation] = req.ID
+			if err := cl.Patch(ctx, task, patch); err != nil {
+				log.Error(err, "recording posted input request", "task", task.Name)
+			}
+			log.Info("posted input request", "task", task.Name, "number", number, "request", req.ID)
+		}
+
+		comments, err := gh.ListComments(ctx, number, req.RequestedAt.Time)
+		if err != nil {
+			log.Error(err, "listing comments", "task", task.Name, "number", number)
+			continue
+		}
+		for _, c := range comments {
+			if c.CreatedAt.Before(req.RequestedAt.Time) {
+				continue
+			}
+			answer, ok := parseAnswerCommand(c.Body)
+			if !ok {
+				continue
+			}
+			if authorized, err := gh.AuthorizedComment(ctx, c, users, associations); err != nil {
+				log.Error(err, "checking the author of an answer", "task", task.Name, "author", c.Author)
+				break
+			} else if !authorized {
+				log.Info("ignoring the answer of an unauthorized user", "task", task.Name, "author", c.Author)
+				continue
+			}
+			if err := answerTask(ctx, cl, client.ObjectKeyFromObject(task), req.ID, answer, "github:"+c.Author); err != nil {
+				log.Error(err, "answering input request", "task", task.Name)
+			} else {
+				log.Info("answered input request from comment", "task", task.Name, "author", c.Author)
+			}
+			break
+		}
+	}
+}
+
+// answerTask records the answer to the Task's pending input request with
+// the given ID.
+func answerTask(ctx context.Context, cl client.Client, key types.NamespacedName, id, answer, answeredBy string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var task axonv1alpha1.Task
+		if err := cl.Get(ctx, key, &task); err != nil {
+			return err
+		}
+		req := task.Status.InputRequest
+		if req == nil || req.ID != id || req.AnsweredAt != nil {
+			return nil
+		}
+		now := metav1.Now()
+		req.Answer = answer
+		req.AnsweredBy = answeredBy
+		req.AnsweredAt = &now
+		return cl.Status().Update(ctx, &task)
+	})
+}
+
+// parseAnswerCommand returns the answer of a "/answer <text>" comment.
+func parseAnswerCommand(body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, answerCommand) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(body, answerCommand)
+	if rest == "" || (rest[0] != ' ' && rest[0] != '\t' && rest[0] != '\n' && rest[0] != '\r') {
+		return "", false
+	}
+	answer := strings.TrimSpace(rest)
+	return answer, answer != ""
+}
+
+// quote formats text as a Markdown block quote.
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = "> " + l
+	}
+	return strings.Join(lines, "\n")
+}
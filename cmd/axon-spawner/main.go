@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -24,6 +30,22 @@ import (
 
 var scheme = runtime.NewScheme()
 
+// Reasons of the Events recorded for the TaskSpawner.
+const (
+	reasonTaskCreated     = "TaskCreated"
+	reasonDiscoveryFailed = "DiscoveryFailed"
+	reasonRateLimited     = "RateLimited"
+)
+
+// commentsSinceOverlap is how long before the last discovery of a previous
+// spawner the discovery of commands in comments continues. Comments are
+// listed before the time of the discovery is recorded.
+const commentsSinceOverlap = 5 * time.Minute
+
+// suspendCheckInterval is how often the TaskSpawner is checked for being
+// suspended or resumed between cycles.
+const suspendCheckInterval = 10 * time.Second
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(axonv1alpha1.AddToScheme(scheme))
@@ -34,11 +56,13 @@ func main() {
 	var namespace string
 	var githubOwner string
 	var githubRepo string
+	var metricsAddr string
 
 	flag.StringVar(&name, "taskspawner-name", "", "Name of the TaskSpawner to manage")
 	flag.StringVar(&namespace, "taskspawner-namespace", "", "Namespace of the TaskSpawner")
 	flag.StringVar(&githubOwner, "github-owner", "", "GitHub repository owner")
 	flag.StringVar(&githubRepo, "github-repo", "", "GitHub repository name")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to. Set to 0 to disable it.")
 
 	opts := zap.Options{Development: true}
 	opts.BindFlags(flag.CommandLine)
@@ -65,13 +89,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Error(err, "unable to create clientset")
+		os.Exit(1)
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 	key := types.NamespacedName{Name: name, Namespace: namespace}
 
+	broadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: cs.EventsV1()})
+	broadcaster.StartRecordingToSink(ctx.Done())
+	defer broadcaster.Shutdown()
+	recorder := broadcaster.NewRecorder(scheme, "axon-spawner")
+
+	if metricsAddr != "0" {
+		go serveMetrics(ctx, metricsAddr)
+	}
+
 	log.Info("starting spawner", "taskspawner", key)
 
+	cache := newDiscoveryCache()
 	for {
-		if err := runCycle(ctx, cl, key, githubOwner, githubRepo); err != nil {
+		nextPoll, err := runCycle(ctx, cl, recorder, key, githubOwner, githubRepo, cache)
+		if err != nil {
 			log.Error(err, "discovery cycle failed")
 		}
 
@@ -84,29 +125,78 @@ func main() {
 		}
 
 		interval := parsePollInterval(ts.Spec.PollInterval)
+		if nextPoll > interval {
+			log.Info("delaying the next cycle to stay within the rate limit", "pollInterval", interval)
+			interval = nextPoll
+		}
 		log.Info("sleeping until next cycle", "interval", interval)
-		if done := sleepOrDone(ctx, interval); done {
+		if done := waitForNextCycle(ctx, cl, key, ts.Spec.Suspend, interval); done {
 			return
 		}
 	}
 }
 
-func runCycle(ctx context.Context, cl client.Client, key types.NamespacedName, githubOwner, githubRepo string) error {
+// runCycle discovers items and creates Tasks for them. It returns how long
+// to wait before the next cycle at least, to stay within the rate limit of
+// the source.
+func runCycle(ctx context.Context, cl client.Client, recorder events.EventRecorder, key types.NamespacedName, githubOwner, githubRepo string, cache *discoveryCache) (time.Duration, error) {
 	log := ctrl.Log.WithName("spawner")
 
 	var ts axonv1alpha1.TaskSpawner
 	if err := cl.Get(ctx, key, &ts); err != nil {
-		return fmt.Errorf("fetching TaskSpawner: %w", err)
+		return 0, fmt.Errorf("fetching TaskSpawner: %w", err)
+	}
+
+	if ts.Spec.Suspend {
+		log.Info("TaskSpawner is suspended, skipping discovery")
+		if !meta.IsStatusConditionTrue(ts.Status.Conditions, axonv1alpha1.TaskSpawnerConditionSuspended) {
+			ts.Status.Message = "Discovery suspended"
+			ts.SetCondition(axonv1alpha1.TaskSpawnerConditionSuspended, metav1.ConditionTrue, "Suspended", ts.Status.Message)
+			if err := cl.Status().Update(ctx, &ts); err != nil {
+				return 0, fmt.Errorf("updating TaskSpawner status: %w", err)
+			}
+		}
+		return 0, nil
 	}
 
-	src, err := buildSource(&ts, githubOwner, githubRepo)
+	if persistsCache(&ts) {
+		if err := cache.load(ctx, cl, &ts); err != nil {
+			log.Error(err, "loading the persisted GitHub API response cache")
+		}
+	}
+
+	src, err := buildSource(&ts, githubOwner, githubRepo, cache)
 	if err != nil {
-		return fmt.Errorf("building source: %w", err)
+		return 0, fmt.Errorf("building source: %w", err)
 	}
 
+	start := time.Now()
 	items, err := src.Discover(ctx)
+	discoveryDuration.WithLabelValues(ts.Namespace, ts.Name).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return fmt.Errorf("discovering items: %w", err)
+		discoveryErrors.WithLabelValues(ts.Namespace, ts.Name).Inc()
+		var nextPoll time.Duration
+		var rateLimited *source.RateLimitError
+		if errors.As(err, &rateLimited) {
+			recorder.Eventf(&ts, nil, corev1.EventTypeWarning, reasonRateLimited, "Discover", "%v", rateLimited)
+			ts.SetCondition(axonv1alpha1.TaskSpawnerConditionRateLimited, metav1.ConditionTrue, reasonRateLimited, rateLimited.Error())
+			nextPoll = max(parsePollInterval(ts.Spec.PollInterval), time.Until(rateLimited.Reset))
+			if gh, ok := githubSource(src); ok {
+				ts.Status.RateLimit = rateLimitStatus(gh, nextPoll)
+			}
+		} else {
+			recorder.Eventf(&ts, nil, corev1.EventTypeWarning, reasonDiscoveryFailed, "Discover", "Discovery failed: %v", err)
+			ts.SetCondition(axonv1alpha1.TaskSpawnerConditionSourceReachable, metav1.ConditionFalse, reasonDiscoveryFailed, fmt.Sprintf("Discovery failed: %v", err))
+		}
+		ts.ClearCondition(axonv1alpha1.TaskSpawnerConditionSuspended, "Resumed", "Discovery resumed")
+		if updateErr := cl.Status().Update(ctx, &ts); updateErr != nil {
+			log.Error(updateErr, "updating TaskSpawner status")
+		}
+		return nextPoll, fmt.Errorf("discovering items: %w", err)
+	}
+	discoveredItems.WithLabelValues(ts.Namespace, ts.Name).Set(float64(len(items)))
+	if cs, ok := src.(*source.GitHubCommentsSource); ok {
+		cache.commentsSince = cs.Since
 	}
 
 	log.Info("discovered items", "count", len(items))
@@ -118,7 +208,7 @@ func runCycle(ctx context.Context, cl client.Client, key types.NamespacedName, g
 		client.InNamespace(ts.Namespace),
 		client.MatchingLabels{"axon.io/taskspawner": ts.Name},
 	); err != nil {
-		return fmt.Errorf("listing existing Tasks: %w", err)
+		return 0, fmt.Errorf("listing existing Tasks: %w", err)
 	}
 
 	existingTasks := make(map[string]bool)
@@ -128,7 +218,7 @@ func runCycle(ctx context.Context, cl client.Client, key types.NamespacedName, g
 
 	var newItems []source.WorkItem
 	for _, item := range items {
-		taskName := fmt.Sprintf("%s-%s", ts.Name, item.ID)
+		taskName := ts.Name + "-" + source.NameSuffix(item.ID)
 		if !existingTasks[taskName] {
 			newItems = append(newItems, item)
 		}
@@ -136,7 +226,7 @@ func runCycle(ctx context.Context, cl client.Client, key types.NamespacedName, g
 
 	newTasksCreated := 0
 	for _, item := range newItems {
-		taskName := fmt.Sprintf("%s-%s", ts.Name, item.ID)
+		taskName := ts.Name + "-" + source.NameSuffix(item.ID)
 
 		prompt, err := source.RenderPrompt(ts.Spec.TaskTemplate.PromptTemplate, item)
 		if err != nil {
@@ -151,6 +241,7 @@ func runCycle(ctx context.Context, cl client.Client, key types.NamespacedName, g
 				Labels: map[string]string{
 					"axon.io/taskspawner": ts.Name,
 				},
+				Annotations: map[string]string{},
 			},
 			Spec: axonv1alpha1.TaskSpec{
 				Type:                    ts.Spec.TaskTemplate.Type,
@@ -158,77 +249,308 @@ func runCycle(ctx context.Context, cl client.Client, key types.NamespacedName, g
 				Credentials:             ts.Spec.TaskTemplate.Credentials,
 				Model:                   ts.Spec.TaskTemplate.Model,
 				TTLSecondsAfterFinished: ts.Spec.TaskTemplate.TTLSecondsAfterFinished,
+				HumanInput:              ts.Spec.TaskTemplate.HumanInput,
 			},
 		}
 
-		if gh := ts.Spec.When.GitHubIssues; gh != nil && gh.WorkspaceRef != nil {
-			task.Spec.WorkspaceRef = gh.WorkspaceRef
+		// Only GitHub issues and pull requests are commented on; workflow
+		// runs have no number, and the numbers of HTTP, Jira and Linear
+		// items are not GitHub's
+		if when := ts.Spec.When; item.Number > 0 && (when.GitHubIssues != nil || when.GitHubComments != nil) {
+			task.Annotations[sourceNumberAnnotation] = strconv.Itoa(item.Number)
+		}
+		task.Annotations[sourceIDAnnotation] = item.ID
+		task.Spec.WorkspaceRef = ts.Spec.When.WorkspaceRef()
+		// Tasks of sources that cannot act on their items are not kept
+		// waiting for actions that are never applied
+		if _, ok := completionItems(src); ok && hasCompletionActions(&ts) {
+			task.Annotations[completionActionsAnnotation] = completionActionsPending
 		}
 
 		if err := cl.Create(ctx, task); err != nil {
 			if apierrors.IsAlreadyExists(err) {
 				log.Info("Task already exists, skipping", "task", taskName)
+				acknowledge(ctx, src, item)
 			} else {
 				log.Error(err, "creating Task", "task", taskName)
 			}
 			continue
 		}
+		acknowledge(ctx, src, item)
 
 		log.Info("created Task", "task", taskName, "item", item.ID)
+		recorder.Eventf(&ts, task, corev1.EventTypeNormal, reasonTaskCreated, "CreateTask", "Created Task %s for %s", taskName, describeItem(item))
+		tasksCreated.WithLabelValues(ts.Namespace, ts.Name).Inc()
 		newTasksCreated++
 	}
 
+	if gh, ok := githubSource(src); ok && ts.Spec.TaskTemplate.HumanInput != nil {
+		// Answers are accepted from those whose commands are
+		var users, associations []string
+		if cs, ok := src.(*source.GitHubCommentsSource); ok {
+			users, associations = cs.Users, cs.AuthorAssociations
+		}
+		syncInputRequests(ctx, cl, gh, users, associations, existingTaskList.Items)
+	}
+	if items, ok := completionItems(src); ok {
+		applyCompletionActions(ctx, cl, recorder, items, &ts, existingTaskList.Items)
+	}
+
+	// Forget the responses of items that are gone
+	cache.responses.Prune(time.Now().Add(-cacheRetention))
+	if persistsCache(&ts) {
+		if err := cache.save(ctx, cl, &ts); err != nil {
+			log.Error(err, "persisting the GitHub API response cache")
+		}
+	}
+
+	// Poll as often as the rate limit allows, considering every request
+	// of this cycle
+	var nextPoll time.Duration
+	var rateLimit *axonv1alpha1.RateLimitStatus
+	if gh, ok := githubSource(src); ok {
+		if rl, ok := gh.RateLimit(); ok {
+			nextPoll = source.PaceInterval(parsePollInterval(ts.Spec.PollInterval), rl, gh.Requests(), time.Now())
+			rateLimit = rateLimitStatus(gh, nextPoll)
+		}
+	}
+
 	// Update status in a single batch
 	if err := cl.Get(ctx, key, &ts); err != nil {
-		return fmt.Errorf("re-fetching TaskSpawner for status update: %w", err)
+		return nextPoll, fmt.Errorf("re-fetching TaskSpawner for status update: %w", err)
 	}
 
 	now := metav1.Now()
-	ts.Status.Phase = axonv1alpha1.TaskSpawnerPhaseRunning
 	ts.Status.LastDiscoveryTime = &now
 	ts.Status.TotalDiscovered = len(items)
 	ts.Status.TotalTasksCreated += newTasksCreated
+	ts.Status.RateLimit = rateLimit
 	ts.Status.Message = fmt.Sprintf("Discovered %d items, created %d tasks total", ts.Status.TotalDiscovered, ts.Status.TotalTasksCreated)
+	ts.SetCondition(axonv1alpha1.TaskSpawnerConditionSourceReachable, metav1.ConditionTrue, "Discovered", ts.Status.Message)
+	ts.ClearCondition(axonv1alpha1.TaskSpawnerConditionRateLimited, "Discovered", "Discovery succeeded")
+	ts.ClearCondition(axonv1alpha1.TaskSpawnerConditionSuspended, "Resumed", "Discovery resumed")
 
 	if err := cl.Status().Update(ctx, &ts); err != nil {
-		return fmt.Errorf("updating TaskSpawner status: %w", err)
+		return nextPoll, fmt.Errorf("updating TaskSpawner status: %w", err)
 	}
 
-	return nil
+	return nextPoll, nil
+}
+
+// rateLimitStatus reports the state of the source's rate limit, or nil if
+// the source did not report one.
+func rateLimitStatus(gh *source.GitHubSource, nextPoll time.Duration) *axonv1alpha1.RateLimitStatus {
+	rl, ok := gh.RateLimit()
+	if !ok {
+		return nil
+	}
+	reset := metav1.NewTime(rl.Reset)
+	next := metav1.NewTime(time.Now().Add(nextPoll))
+	return &axonv1alpha1.RateLimitStatus{
+		Limit:            rl.Limit,
+		Remaining:        rl.Remaining,
+		ResetTime:        &reset,
+		LastPollRequests: gh.Requests(),
+		NextPollTime:     &next,
+	}
 }
 
-func buildSource(ts *axonv1alpha1.TaskSpawner, owner, repo string) (source.Source, error) {
+func buildSource(ts *axonv1alpha1.TaskSpawner, owner, repo string, cache *discoveryCache) (source.Source, error) {
 	if ts.Spec.When.GitHubIssues != nil {
 		gh := ts.Spec.When.GitHubIssues
-		return &source.GitHubSource{
-			Owner:         owner,
-			Repo:          repo,
-			Types:         gh.Types,
-			Labels:        gh.Labels,
-			ExcludeLabels: gh.ExcludeLabels,
-			State:         gh.State,
-			Token:         os.Getenv("GITHUB_TOKEN"),
+		src := &source.GitHubSource{
+			Owner:              owner,
+			Repo:               repo,
+			Types:              gh.Types,
+			Labels:             gh.Labels,
+			ExcludeLabels:      gh.ExcludeLabels,
+			State:              gh.State,
+			Assignee:           gh.Assignee,
+			Author:             gh.Author,
+			AuthorAssociations: gh.AuthorAssociations,
+			Milestone:          gh.Milestone,
+			Query:              gh.Query,
+			Token:              os.Getenv("GITHUB_TOKEN"),
+			Cache:              cache.responses,
+			Index:              cache.index,
+			GraphQL:            gh.API == "graphql",
+		}
+		if gh.CreatedWithin != nil {
+			src.CreatedWithin = gh.CreatedWithin.Duration
+		}
+		if gh.UpdatedWithin != nil {
+			src.UpdatedWithin = gh.UpdatedWithin.Duration
+		}
+		return src, nil
+	}
+
+	if ts.Spec.When.GitHubComments != nil {
+		gc := ts.Spec.When.GitHubComments
+		types := gc.Types
+		if len(types) == 0 {
+			types = []string{"issues", "pulls"}
+		}
+		since := cache.commentsSince
+		if last := ts.Status.LastDiscoveryTime; since.IsZero() && last != nil {
+			// Continue where a previous spawner left off; the commands it
+			// created Tasks for were acknowledged. Without either, the
+			// discovery starts now.
+			since = last.Add(-commentsSinceOverlap)
+		}
+		return &source.GitHubCommentsSource{
+			GitHubSource: &source.GitHubSource{
+				Owner: owner,
+				Repo:  repo,
+				Types: types,
+				Token: os.Getenv("GITHUB_TOKEN"),
+				Cache: cache.responses,
+			},
+			Command:            gc.Command,
+			AuthorAssociations: gc.AuthorAssociations,
+			Users:              gc.Users,
+			Since:              since,
+		}, nil
+	}
+
+	if ts.Spec.When.GitHubWorkflowRuns != nil {
+		wr := ts.Spec.When.GitHubWorkflowRuns
+		return &source.GitHubWorkflowRunsSource{
+			GitHubSource: &source.GitHubSource{
+				Owner: owner,
+				Repo:  repo,
+				Token: os.Getenv("GITHUB_TOKEN"),
+				Cache: cache.responses,
+			},
+			Workflows: wr.Workflows,
+			Branches:  wr.Branches,
+			Runs:      cache.workflowRuns,
+		}, nil
+	}
+
+	if ts.Spec.When.HTTP != nil {
+		h := ts.Spec.When.HTTP
+		src := &source.HTTPSource{
+			URL:       h.URL,
+			Headers:   http.Header{},
+			ItemsPath: h.ItemsPath,
+			Fields: source.HTTPFields{
+				ID:     h.Fields.ID,
+				Number: h.Fields.Number,
+				Title:  h.Fields.Title,
+				Body:   h.Fields.Body,
+				URL:    h.Fields.URL,
+				Labels: h.Fields.Labels,
+			},
+		}
+		for i, header := range h.Headers {
+			value := header.Value
+			if header.SecretKeyRef != nil {
+				value += os.Getenv(fmt.Sprintf("%s%d", source.HTTPHeaderEnvPrefix, i))
+			}
+			src.Headers.Add(header.Name, value)
+		}
+		if p := h.Pagination; p != nil {
+			src.Pagination = p.Type
+			src.PaginationPath = p.Path
+			src.PaginationParam = p.Param
+		}
+		return src, nil
+	}
+
+	if ts.Spec.When.JiraIssues != nil {
+		j := ts.Spec.When.JiraIssues
+		return &source.JiraSource{
+			BaseURL:    j.URL,
+			JQL:        j.JQL,
+			APIVersion: j.APIVersion,
+			User:       os.Getenv("JIRA_USER"),
+			Token:      os.Getenv("JIRA_TOKEN"),
+		}, nil
+	}
+
+	if ts.Spec.When.LinearIssues != nil {
+		l := ts.Spec.When.LinearIssues
+		return &source.LinearSource{
+			Team:     l.Team,
+			States:   l.States,
+			Labels:   l.Labels,
+			Assignee: l.Assignee,
+			APIKey:   os.Getenv("LINEAR_API_KEY"),
 		}, nil
 	}
 
 	return nil, fmt.Errorf("no source configured in TaskSpawner %s/%s", ts.Namespace, ts.Name)
 }
 
-func parsePollInterval(s string) time.Duration {
-	if s == "" {
-		return 5 * time.Minute
+// githubSource returns the GitHub source the items were discovered with,
+// for the requests made about them.
+func githubSource(src source.Source) (*source.GitHubSource, bool) {
+	switch s := src.(type) {
+	case *source.GitHubSource:
+		return s, true
+	case *source.GitHubCommentsSource:
+		return s.GitHubSource, true
+	case *source.GitHubWorkflowRunsSource:
+		return s.GitHubSource, true
+	}
+	return nil, false
+}
+
+// describeItem names the item in Events, like "Issue #42".
+func describeItem(item source.WorkItem) string {
+	if item.Number > 0 {
+		return fmt.Sprintf("%s #%d", item.Kind, item.Number)
+	}
+	return fmt.Sprintf("%s %s", item.Kind, item.ID)
+}
+
+// acknowledge tells the source that a Task exists for the item, if it needs
+// to be told.
+func acknowledge(ctx context.Context, src source.Source, item source.WorkItem) {
+	ack, ok := src.(source.Acknowledger)
+	if !ok {
+		return
+	}
+	if err := ack.Acknowledge(ctx, item); err != nil {
+		ctrl.Log.WithName("spawner").Error(err, "acknowledging item", "item", item.ID)
 	}
-	d, err := time.ParseDuration(s)
+}
+
+func parsePollInterval(s string) time.Duration {
+	d, err := source.ParsePollInterval(s)
 	if err != nil {
-		// Try parsing as plain number (seconds)
-		if n, err := strconv.Atoi(s); err == nil {
-			return time.Duration(n) * time.Second
-		}
-		return 5 * time.Minute
+		// The webhook rejects invalid intervals, but TaskSpawners created
+		// before it was installed may still have one
+		return source.DefaultPollInterval
 	}
 	return d
 }
 
+// waitForNextCycle sleeps for the interval, but returns early once the
+// TaskSpawner is suspended or resumed, so that takes effect promptly rather
+// than at the next poll. It reports whether the context is done.
+func waitForNextCycle(ctx context.Context, cl client.Client, key types.NamespacedName, suspended bool, interval time.Duration) bool {
+	deadline := time.Now().Add(interval)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false
+		}
+		if done := sleepOrDone(ctx, min(remaining, suspendCheckInterval)); done {
+			return true
+		}
+
+		var ts axonv1alpha1.TaskSpawner
+		if err := cl.Get(ctx, key, &ts); err != nil {
+			continue
+		}
+		if ts.Spec.Suspend != suspended {
+			ctrl.Log.WithName("spawner").Info("TaskSpawner suspension changed, starting the next cycle", "suspend", ts.Spec.Suspend)
+			return false
+		}
+	}
+}
+
 func sleepOrDone(ctx context.Context, d time.Duration) bool {
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/axon-core/axon/internal/source"
+)
+
+var (
+	discoveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "axon_spawner_discovery_duration_seconds",
+		Help:    "Time taken to discover work items from a TaskSpawner's source.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"namespace", "taskspawner"})
+
+	discoveryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "axon_spawner_discovery_errors_total",
+		Help: "Number of failed discoveries from a TaskSpawner's source.",
+	}, []string{"namespace", "taskspawner"})
+
+	discoveredItems = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "axon_spawner_discovered_items",
+		Help: "Number of work items found by a TaskSpawner's last discovery.",
+	}, []string{"namespace", "taskspawner"})
+
+	tasksCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "axon_spawner_tasks_created_total",
+		Help: "Number of Tasks created by a TaskSpawner.",
+	}, []string{"namespace", "taskspawner"})
+)
+
+// serveMetrics serves the spawner's metrics on addr until ctx is done.
+func serveMetrics(ctx context.Context, addr string) {
+	log := ctrl.Log.WithName("metrics")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		discoveryDuration, discoveryErrors, discoveredItems, tasksCreated,
+	)
+	registry.MustRegister(source.Collectors()...)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("serving metrics", "address", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error(err, "serving metrics")
+	}
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+const (
+	// TranscriptArtifact is the name of the artifact holding the agent's
+	// stream-json output.
+	TranscriptArtifact = "transcript.jsonl"
+
+	// DiffArtifact is the name of the artifact holding the agent's changes
+	// to the repository.
+	DiffArtifact = "diff.patch"
+
+	// filesArtifactDir is the directory of the artifacts collected from
+	// ArtifactCollector.Paths.
+	filesArtifactDir = "files"
+)
+
+// ArtifactCollector configures the artifacts staged when the agent exits.
+type ArtifactCollector struct {
+	// Paths lists files, directories or glob patterns to collect in
+	// addition to the transcript and the diff. Relative paths are resolved
+	// against the runner's RepoDir.
+	Paths []string
+}
+
+// collectArtifacts stages the transcript, the changes made since base and
+// the files matching the collector's paths in the outbox, for the uploader
+// to upload them. The diff is skipped if base is empty.
+func (r *Runner) collectArtifacts(ctx context.Context, transcript, base string) error {
+	store := &artifact.FileStore{Dir: filepath.Join(r.Outbox, outboxArtifactsDir)}
+
+	data, err := os.ReadFile(transcript)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	if err := stageArtifact(ctx, store, TranscriptArtifact, data); err != nil {
+		return err
+	}
+	staged := 1
+
+	if base != "" {
+		diff, err := r.diff(ctx, base)
+		if err != nil {
+			return err
+		}
+		if err := stageArtifact(ctx, store, DiffArtifact, diff); err != nil {
+			return err
+		}
+		staged++
+	}
+
+	files, err := r.matchArtifactPaths()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", f.path, err)
+		}
+		if err := stageArtifact(ctx, store, f.name, data); err != nil {
+			return err
+		}
+		staged++
+	}
+
+	fmt.Fprintf(r.Stderr, "axon-agent: staged %d artifacts\n", staged)
+	return nil
+}
+
+func stageArtifact(ctx context.Context, store artifact.Store, name string, data []byte) error {
+	if err := store.Put(ctx, name, bytes.NewReader(data), int64(len(data))); err != nil {
+		return fmt.Errorf("staging %s: %w", name, err)
+	}
+	return nil
+}
+
+type artifactFile struct {
+	// name is the name of the artifact, files/<path>
+	name string
+	path string
+}
+
+// matchArtifactPaths returns the regular files matching the collector's
+// paths. Directories are collected recursively.
+func (r *Runner) matchArtifactPaths() ([]artifactFile, error) {
+	root, err := filepath.Abs(r.RepoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []artifactFile
+	seen := make(map[string]bool)
+	add := func(p string) {
+		name := p
+		if rel, err := filepath.Rel(root, p); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+		name = path.Join(filesArtifactDir, filepath.ToSlash(name))
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, artifactFile{name: name, path: p})
+		}
+	}
+
+	for _, pattern := range r.Artifacts.Paths {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(root, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact path %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			fmt.Fprintf(r.Stderr, "axon-agent: no files match artifact path %s\n", pattern)
+		}
+
+		for _, m := range matches {
+			err := filepath.WalkDir(m, func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() && d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				if d.Type().IsRegular() {
+					add(p)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("collecting %s: %w", m, err)
+			}
+		}
+	}
+	return files, nil
+}
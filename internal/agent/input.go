@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+const (
+	// AskHumanToolName is the name of the MCP tool that asks a human for input.
+	AskHumanToolName = "ask_human"
+
+	// AnsweredByTimeout is recorded in status.inputRequest.answeredBy when a
+	// question is answered because its timeout elapsed.
+	AnsweredByTimeout = "timeout"
+
+	defaultPollInterval = 5 * time.Second
+)
+
+// InputRequester asks a human for input by recording a question in the
+// status of the Task the agent runs for, and waiting for it to be answered.
+type InputRequester struct {
+	Client       client.Client
+	Task         types.NamespacedName
+	PollInterval time.Duration
+}
+
+// Ask records the question in the Task's status and blocks until it is
+// answered or ctx is cancelled. It returns the answer and who gave it.
+func (r *InputRequester) Ask(ctx context.Context, question string) (string, string, error) {
+	id, err := newRequestID()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var task axonv1alpha1.Task
+		if err := r.Client.Get(ctx, r.Task, &task); err != nil {
+			return err
+		}
+		task.Status.InputRequest = &axonv1alpha1.InputRequest{
+			ID:          id,
+			Question:    question,
+			RequestedAt: metav1.Now(),
+		}
+		return r.Client.Status().Update(ctx, &task)
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("recording input request: %w", err)
+	}
+
+	interval := r.PollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var task axonv1alpha1.Task
+		if err := r.Client.Get(ctx, r.Task, &task); err != nil {
+			return "", "", fmt.Errorf("fetching Task: %w", err)
+		}
+		req := task.Status.InputRequest
+		if req == nil || req.ID != id {
+			return "", "", fmt.Errorf("input request %s was replaced before it was answered", id)
+		}
+		if req.AnsweredAt != nil {
+			return req.Answer, req.AnsweredBy, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// AskHumanTool returns the MCP tool that lets the agent ask a human for
+// input through the given requester.
+func AskHumanTool(r *InputRequester) Tool {
+	return Tool{
+		Name: AskHumanToolName,
+		Description: "Ask a human a question and wait for the answer. Use this when you need a decision " +
+			"or information that you cannot determine yourself, instead of guessing. " +
+			"The call blocks until a human answers, which may take a long time.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"question": map[string]interface{}{
+					"type":        "string",
+					"description": "The question to ask. Include the context and options the human needs to answer it.",
+				},
+			},
+			"required": []string{"question"},
+		},
+		Call: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var params struct {
+				Question string `json:"question"`
+			}
+			if err := json.Unmarshal(args, &params); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			question := strings.TrimSpace(params.Question)
+			if question == "" {
+				return "", fmt.Errorf("question is required")
+			}
+
+			answer, answeredBy, err := r.Ask(ctx, question)
+			if err != nil {
+				return "", err
+			}
+			return formatAnswer(answer, answeredBy), nil
+		},
+	}
+}
+
+// formatAnswer returns the text handed back to the agent for an answer.
+func formatAnswer(answer, answeredBy string) string {
+	if answeredBy == AnsweredByTimeout {
+		if answer == "" {
+			return "No answer was received before the timeout. Proceed using your best judgement."
+		}
+		return "No answer was received before the timeout. The default answer is: " + answer
+	}
+	return answer
+}
+
+func newRequestID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating request ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	if err := axonv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&axonv1alpha1.Task{}).
+		Build()
+}
+
+func TestAskHumanTool(t *testing.T) {
+	key := types.NamespacedName{Name: "my-task", Namespace: "default"}
+	cl := newFakeClient(t, &axonv1alpha1.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+	})
+
+	tool := AskHumanTool(&InputRequester{Client: cl, Task: key, PollInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Answer the question once it shows up in the Task's status
+	go func() {
+		for ctx.Err() == nil {
+			var task axonv1alpha1.Task
+			if err := cl.Get(ctx, key, &task); err == nil && task.Status.InputRequest != nil {
+				if task.Status.InputRequest.Question != "Which database?" {
+					t.Errorf("Question = %q, want %q", task.Status.InputRequest.Question, "Which database?")
+				}
+				now := metav1.Now()
+				task.Status.InputRequest.Answer = "Postgres"
+				task.Status.InputRequest.AnsweredBy = "cli"
+				task.Status.InputRequest.AnsweredAt = &now
+				if err := cl.Status().Update(ctx, &task); err == nil {
+					return
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	text, err := tool.Call(ctx, json.RawMessage(`{"question":"Which database?"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "Postgres" {
+		t.Errorf("answer = %q, want %q", text, "Postgres")
+	}
+}
+
+func TestAskHumanToolRequiresQuestion(t *testing.T) {
+	tool := AskHumanTool(&InputRequester{})
+	if _, err := tool.Call(context.Background(), json.RawMessage(`{"question":"  "}`)); err == nil {
+		t.Fatal("expected error for empty question")
+	}
+}
+
+func TestFormatAnswer(t *testing.T) {
+	tests := []struct {
+		name       string
+		answer     string
+		answeredBy string
+		want       string
+	}{
+		{name: "Human answer", answer: "yes", answeredBy: "cli", want: "yes"},
+		{name: "Timeout with default", answer: "no", answeredBy: AnsweredByTimeout, want: "No answer was received before the timeout. The default answer is: no"},
+		{name: "Timeout without default", answeredBy: AnsweredByTimeout, want: "No answer was received before the timeout. Proceed using your best judgement."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatAnswer(tt.answer, tt.answeredBy); got != tt.want {
+				t.Errorf("formatAnswer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
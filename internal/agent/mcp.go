@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// mcpProtocolVersion is the MCP protocol version the server speaks when
+	// the client does not ask for a specific one.
+	mcpProtocolVersion = "2024-11-05"
+
+	// maxMessageBytes limits the size of a single JSON-RPC message.
+	maxMessageBytes = 4 * 1024 * 1024
+)
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a tool exposed to the agent over MCP.
+type Tool struct {
+	// Name is the name of the tool.
+	Name string
+	// Description tells the agent what the tool does and when to use it.
+	Description string
+	// InputSchema is the JSON schema of the tool's arguments.
+	InputSchema map[string]interface{}
+	// Call runs the tool with the given arguments and returns the text
+	// returned to the agent.
+	Call func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// MCPServer is a minimal Model Context Protocol server that serves tools
+// over newline-delimited JSON-RPC on stdio.
+type MCPServer struct {
+	Name    string
+	Version string
+	Tools   []Tool
+
+	mu sync.Mutex
+	wg sync.WaitGroup
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *rpcError   `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []toolContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// Serve reads requests from r and writes responses to w until r is closed
+// or ctx is cancelled. Requests are handled concurrently so that a
+// long-running tool call does not block other requests.
+func (s *MCPServer) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+
+	defer s.wg.Wait()
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.write(w, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+
+		// Notifications have no ID and expect no response
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+			result, rpcErr := s.handle(ctx, &req)
+			if rpcErr != nil {
+				resp.Error = rpcErr
+			} else {
+				resp.Result = result
+			}
+			s.write(w, resp)
+		}()
+	}
+
+	return scanner.Err()
+}
+
+func (s *MCPServer) handle(ctx context.Context, req *rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = mcpProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    s.Name,
+				"version": s.Version,
+			},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		tools := make([]map[string]interface{}, 0, len(s.Tools))
+		for _, t := range s.Tools {
+			tools = append(tools, map[string]interface{}{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": t.InputSchema,
+			})
+		}
+		return map[string]interface{}{"tools": tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		for _, t := range s.Tools {
+			if t.Name != params.Name {
+				continue
+			}
+			text, err := t.Call(ctx, params.Arguments)
+			if err != nil {
+				return toolResult{Content: []toolContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+			}
+			return toolResult{Content: []toolContent{{Type: "text", Text: text}}}, nil
+		}
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", params.Name)}
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+func (s *MCPServer) write(w io.Writer, resp rpcResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = w.Write(append(data, '\n'))
+}
@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func serve(t *testing.T, s *MCPServer, input string) []map[string]interface{} {
+	t.Helper()
+
+	var out strings.Builder
+	if err := s.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var responses []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func newTestServer() *MCPServer {
+	return &MCPServer{
+		Name:    "axon",
+		Version: "test",
+		Tools: []Tool{
+			{
+				Name:        "echo",
+				Description: "Echo the text",
+				InputSchema: map[string]interface{}{"type": "object"},
+				Call: func(ctx context.Context, args json.RawMessage) (string, error) {
+					var params struct {
+						Text string `json:"text"`
+					}
+					if err := json.Unmarshal(args, &params); err != nil {
+						return "", err
+					}
+					if params.Text == "" {
+						return "", fmt.Errorf("text is required")
+					}
+					return params.Text, nil
+				},
+			},
+		},
+	}
+}
+
+func TestMCPServerInitialize(t *testing.T) {
+	responses := serve(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`+"\n"+
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response, got %d: %v", len(responses), responses)
+	}
+	result := responses[0]["result"].(map[string]interface{})
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want 2025-03-26", result["protocolVersion"])
+	}
+	if responses[0]["id"] != float64(1) {
+		t.Errorf("id = %v, want 1", responses[0]["id"])
+	}
+}
+
+func TestMCPServerToolsList(t *testing.T) {
+	responses := serve(t, newTestServer(), `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`+"\n")
+
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(responses))
+	}
+	tools := responses[0]["result"].(map[string]interface{})["tools"].([]interface{})
+	if len(tools) != 1 || tools[0].(map[string]interface{})["name"] != "echo" {
+		t.Errorf("unexpected tools: %v", tools)
+	}
+}
+
+func TestMCPServerToolsCall(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     string
+		wantText    string
+		wantIsError bool
+		wantErrCode float64
+	}{
+		{
+			name:     "Successful call",
+			request:  `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hello"}}}`,
+			wantText: "hello",
+		},
+		{
+			name:        "Tool error",
+			request:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+			wantText:    "text is required",
+			wantIsError: true,
+		},
+		{
+			name:        "Unknown tool",
+			request:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"nope","arguments":{}}}`,
+			wantErrCode: codeInvalidParams,
+		},
+		{
+			name:        "Unknown method",
+			request:     `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+			wantErrCode: codeMethodNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := serve(t, newTestServer(), tt.request+"\n")
+			if len(responses) != 1 {
+				t.Fatalf("expected 1 response, got %d", len(responses))
+			}
+			resp := responses[0]
+
+			if tt.wantErrCode != 0 {
+				rpcErr, ok := resp["error"].(map[string]interface{})
+				if !ok {
+					t.Fatalf("expected error response, got %v", resp)
+				}
+				if rpcErr["code"] != tt.wantErrCode {
+					t.Errorf("error code = %v, want %v", rpcErr["code"], tt.wantErrCode)
+				}
+				return
+			}
+
+			result := resp["result"].(map[string]interface{})
+			content := result["content"].([]interface{})
+			if text := content[0].(map[string]interface{})["text"]; text != tt.wantText {
+				t.Errorf("text = %v, want %v", text, tt.wantText)
+			}
+			isError, _ := result["isError"].(bool)
+			if isError != tt.wantIsError {
+				t.Errorf("isError = %v, want %v", isError, tt.wantIsError)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DiffKey is the ConfigMap key holding the agent's diff.
+	DiffKey = "diff.patch"
+
+	// BaseCommitKey is the ConfigMap key holding the commit the diff is
+	// taken against.
+	BaseCommitKey = "base"
+
+	// maxDiffBytes is the largest diff that fits in a ConfigMap, leaving
+	// room for the rest of the object.
+	maxDiffBytes = 1000 * 1024
+
+	// artifactUploadTimeout bounds how long uploading the artifacts may
+	// take once the agent has exited.
+	artifactUploadTimeout = 2 * time.Minute
+)
+
+// DiffConfigMapName returns the name of the ConfigMap the agent of the
+// given Task records its diff in.
+func DiffConfigMapName(taskName string) string {
+	return taskName + "-diff"
+}
+
+// ApprovalConfigMapName returns the name of the ConfigMap holding the
+// snapshot of the diff of the given Task that is reviewed and pushed. It
+// does not end in "-diff", so it is never the diff ConfigMap of another
+// Task.
+func ApprovalConfigMapName(taskName string) string {
+	return taskName + "-approval"
+}
+
+// DiffSHA256 returns the hex-encoded SHA-256 digest of the diff in the
+// ConfigMap.
+func DiffSHA256(cm *corev1.ConfigMap) string {
+	digest := sha256.Sum256(cm.BinaryData[DiffKey])
+	return hex.EncodeToString(digest[:])
+}
+
+// Runner runs the agent and, optionally, records the changes it made to the
+// repository.
+type Runner struct {
+	Client client.Client
+	Task   types.NamespacedName
+
+	// RepoDir is the git repository the agent works in.
+	RepoDir string
+
+	// CaptureDiff stores the agent's changes in the Task's diff ConfigMap
+	// once the agent exits successfully.
+	CaptureDiff bool
+
+	// Artifacts, if set, stages the agent's transcript, its changes and
+	// other files in the Outbox once the agent exits, whether or not it
+	// succeeded.
+	Artifacts *ArtifactCollector
+
+	// Outbox is the directory outputs are staged in. An Uploader in a
+	// container of its own uploads them, so the agent's container never
+	// holds the credentials of the stores. Run waits for it once the
+	// outputs are staged.
+	Outbox string
+
+	// ArchiveTranscript stages the agent's compressed output in the Outbox
+	// to be archived once the agent exits, whether or not it succeeded.
+	ArchiveTranscript bool
+
+	// UsageFile, if set, is the file the usage and outcome from the
+	// agent's result event are written to once the agent exits, as a
+	// Termination. The Job points it at the container's termination log
+	// so the controller can read it.
+	UsageFile string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run runs the command and returns its exit code.
+func (r *Runner) Run(ctx context.Context, argv []string) (int, error) {
+	if len(argv) == 0 {
+		return 1, fmt.Errorf("no command to run")
+	}
+
+	var base string
+	if r.CaptureDiff || r.Artifacts != nil {
+		out, err := r.git(ctx, "rev-parse", "HEAD")
+		switch {
+		case err == nil:
+			base = strings.TrimSpace(string(out))
+		case r.CaptureDiff:
+			return 1, fmt.Errorf("resolving base commit: %w", err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = r.Stdin
+	cmd.Stderr = r.Stderr
+	stdout := []io.Writer{r.Stdout}
+
+	// The deferred uploads run once the agent has exited, even if it
+	// failed or was stopped. Handing the outbox over to the uploader runs
+	// last, once everything is staged.
+	if r.Outbox != "" {
+		defer r.handOver()
+	}
+	if r.Artifacts != nil {
+		transcript, err := os.CreateTemp("", "transcript-*.jsonl")
+		if err != nil {
+			return 1, fmt.Errorf("creating transcript: %w", err)
+		}
+		defer os.Remove(transcript.Name())
+		defer transcript.Close()
+		stdout = append(stdout, transcript)
+
+		defer func() {
+			uploadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), artifactUploadTimeout)
+			defer cancel()
+			if err := r.collectArtifacts(uploadCtx, transcript.Name(), base); err != nil {
+				fmt.Fprintf(r.Stderr, "axon-agent: collecting artifacts: %v\n", err)
+			}
+		}()
+	}
+
+	if r.ArchiveTranscript {
+		archive, err := newTranscriptArchive(r.Outbox)
+		if err != nil {
+			return 1, err
+		}
+		stdout = append(stdout, archive)
+
+		defer func() {
+			if err := archive.close(); err != nil {
+				fmt.Fprintf(r.Stderr, "axon-agent: archiving transcript: %v\n", err)
+			}
+		}()
+	}
+
+	if r.UsageFile != "" {
+		usage := &usageRecorder{}
+		stdout = append(stdout, usage)
+		defer func() {
+			if err := usage.writeUsage(r.UsageFile); err != nil {
+				fmt.Fprintf(r.Stderr, "axon-agent: %v\n", err)
+			}
+		}()
+	}
+
+	cmd.Stdout = io.MultiWriter(stdout...)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, fmt.Errorf("running %s: %w", argv[0], err)
+	}
+
+	if r.CaptureDiff {
+		if err := r.captureDiff(ctx, base); err != nil {
+			return 1, err
+		}
+	}
+	return 0, nil
+}
+
+// captureDiff stores the changes made since base, including new files and
+// commits made by the agent, in the Task's diff ConfigMap.
+func (r *Runner) captureDiff(ctx context.Context, base string) error {
+	diff, err := r.diff(ctx, base)
+	if err != nil {
+		return err
+	}
+	if len(diff) > maxDiffBytes {
+		return fmt.Errorf("diff is %d bytes, larger than the %d bytes that can be stored", len(diff), maxDiffBytes)
+	}
+
+	// The controller creates the ConfigMap before the agent starts, so the
+	// agent cannot create ConfigMaps, only update its own
+	var cm corev1.ConfigMap
+	key := types.NamespacedName{Namespace: r.Task.Namespace, Name: DiffConfigMapName(r.Task.Name)}
+	if err := r.Client.Get(ctx, key, &cm); err != nil {
+		return fmt.Errorf("fetching diff ConfigMap: %w", err)
+	}
+	cm.Data = map[string]string{
+		BaseCommitKey: base,
+	}
+	cm.BinaryData = map[string][]byte{
+		DiffKey: diff,
+	}
+	if err := r.Client.Update(ctx, &cm); err != nil {
+		return fmt.Errorf("updating diff ConfigMap: %w", err)
+	}
+
+	fmt.Fprintf(r.Stderr, "axon-agent: recorded %d byte diff against %s in ConfigMap %s\n", len(diff), base, cm.Name)
+	return nil
+}
+
+// diff returns the changes made since base, including new files and commits
+// made by the agent.
+func (r *Runner) diff(ctx context.Context, base string) ([]byte, error) {
+	if _, err := r.git(ctx, "add", "--all"); err != nil {
+		return nil, fmt.Errorf("staging changes: %w", err)
+	}
+	diff, err := r.git(ctx, "diff", "--cached", "--binary", base)
+	if err != nil {
+		return nil, fmt.Errorf("computing diff: %w", err)
+	}
+	return diff, nil
+}
+
+func (r *Runner) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.RepoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
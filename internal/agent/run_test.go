@@ -0,0 +1,330 @@
+package agent
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "test"},
+		{"config", "user.email", "test@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestRunnerCaptureDiff(t *testing.T) {
+	dir := initRepo(t)
+	key := types.NamespacedName{Name: "my-task", Namespace: "default"}
+	cl := newFakeClient(t,
+		&axonv1alpha1.Task{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "uid-1"},
+		},
+		// Created by the controller
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-task-diff",
+				Namespace:       key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Task", Name: key.Name, UID: "uid-1"}},
+			},
+		},
+	)
+
+	r := &Runner{
+		Client:      cl,
+		Task:        key,
+		RepoDir:     dir,
+		CaptureDiff: true,
+		Stdout:      io.Discard,
+		Stderr:      io.Discard,
+	}
+
+	// The command stands in for the agent and creates a new file
+	code, err := r.Run(context.Background(), []string{"sh", "-c", "echo hello > " + filepath.Join(dir, "hello.txt")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+
+	var cm corev1.ConfigMap
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "my-task-diff", Namespace: "default"}, &cm); err != nil {
+		t.Fatalf("getting diff ConfigMap: %v", err)
+	}
+	diff := string(cm.BinaryData[DiffKey])
+	if !strings.Contains(diff, "hello.txt") || !strings.Contains(diff, "+hello") {
+		t.Errorf("unexpected diff: %q", diff)
+	}
+	if cm.Data[BaseCommitKey] == "" {
+		t.Error("expected base commit to be recorded")
+	}
+	if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].UID != "uid-1" {
+		t.Errorf("unexpected owner references: %+v", cm.OwnerReferences)
+	}
+}
+
+func TestRunnerExitCode(t *testing.T) {
+	dir := initRepo(t)
+	key := types.NamespacedName{Name: "my-task", Namespace: "default"}
+	cl := newFakeClient(t)
+
+	r := &Runner{
+		Client:      cl,
+		Task:        key,
+		RepoDir:     dir,
+		CaptureDiff: true,
+		Stdout:      io.Discard,
+		Stderr:      os.Stderr,
+	}
+
+	code, err := r.Run(context.Background(), []string{"sh", "-c", "exit 3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("exit code = %d, want 3", code)
+	}
+
+	// A failed agent's changes are not recorded
+	var cm corev1.ConfigMap
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "my-task-diff", Namespace: "default"}, &cm); err == nil {
+		t.Error("expected no diff ConfigMap for a failed run")
+	}
+}
+
+func TestRunnerArtifacts(t *testing.T) {
+	outboxPollInterval = 10 * time.Millisecond
+	dir := initRepo(t)
+	outbox := t.TempDir()
+	key := types.NamespacedName{Name: "my-task", Namespace: "default"}
+	cl := newFakeClient(t, &axonv1alpha1.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "uid-1"},
+	})
+	store := &artifact.FileStore{Dir: t.TempDir()}
+
+	r := &Runner{
+		RepoDir: dir,
+		Outbox:  outbox,
+		Artifacts: &ArtifactCollector{
+			Paths: []string{"reports", "missing.txt"},
+		},
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	u := &Uploader{
+		Client: cl,
+		Task:   key,
+		Outbox: outbox,
+		Artifacts: &ArtifactUpload{
+			Store:  store,
+			Prefix: "default/my-task/uid-1",
+		},
+		Stderr: io.Discard,
+	}
+	uploaded := make(chan error, 1)
+	go func() { uploaded <- u.Run(context.Background()) }()
+
+	// The command stands in for the agent: it writes its transcript to
+	// stdout, writes a report and fails
+	script := `echo '{"type":"result"}'; mkdir reports; echo done > reports/summary.md; exit 1`
+	code, err := r.Run(context.Background(), []string{"sh", "-c", "cd " + dir + " && " + script})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if err := <-uploaded; err != nil {
+		t.Fatalf("unexpected upload error: %v", err)
+	}
+
+	var task axonv1alpha1.Task
+	if err := cl.Get(context.Background(), key, &task); err != nil {
+		t.Fatalf("getting Task: %v", err)
+	}
+	got := map[string]string{}
+	for _, a := range task.Status.Artifacts {
+		got[a.Name] = a.Key
+	}
+	want := map[string]string{
+		TranscriptArtifact:         "default/my-task/uid-1/transcript.jsonl",
+		DiffArtifact:               "default/my-task/uid-1/diff.patch",
+		"files/reports/summary.md": "default/my-task/uid-1/files/reports/summary.md",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("artifacts = %v, want %v", got, want)
+	}
+	for name, key := range want {
+		if got[name] != key {
+			t.Errorf("artifact %s key = %q, want %q", name, got[name], key)
+		}
+	}
+
+	read := func(key string) string {
+		rc, err := store.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("reading %s: %v", key, err)
+		}
+		defer rc.Close()
+		data, _ := io.ReadAll(rc)
+		return string(data)
+	}
+	if transcript := read(want[TranscriptArtifact]); transcript != "{\"type\":\"result\"}\n" {
+		t.Errorf("transcript = %q", transcript)
+	}
+	if diff := read(want[DiffArtifact]); !strings.Contains(diff, "+done") {
+		t.Errorf("diff = %q, want the report", diff)
+	}
+}
+
+func TestRunnerTranscriptArchive(t *testing.T) {
+	outboxPollInterval = 10 * time.Millisecond
+	outbox := t.TempDir()
+	store := &artifact.FileStore{Dir: t.TempDir()}
+	r := &Runner{
+		Outbox:            outbox,
+		ArchiveTranscript: true,
+		Stdout:            io.Discard,
+		Stderr:            io.Discard,
+	}
+	u := &Uploader{
+		Outbox: outbox,
+		Transcript: &TranscriptArchiver{
+			Store: store,
+			Key:   "transcripts/default/my-task/uid-1.jsonl.gz",
+		},
+		Stderr: io.Discard,
+	}
+	uploaded := make(chan error, 1)
+	go func() { uploaded <- u.Run(context.Background()) }()
+
+	code, err := r.Run(context.Background(), []string{"sh", "-c", `echo '{"type":"system"}'; echo '{"type":"result"}'; exit 2`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 2 {
+		t.Fatalf("exit code = %d, want 2", code)
+	}
+	if err := <-uploaded; err != nil {
+		t.Fatalf("unexpected upload error: %v", err)
+	}
+
+	rc, err := store.Get(context.Background(), "transcripts/default/my-task/uid-1.jsonl.gz")
+	if err != nil {
+		t.Fatalf("reading archived transcript: %v", err)
+	}
+	defer rc.Close()
+	gz, err := gzip.NewReader(rc)
+	if err != nil {
+		t.Fatalf("archived transcript is not gzip-compressed: %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	if want := "{\"type\":\"system\"}\n{\"type\":\"result\"}\n"; string(data) != want {
+		t.Errorf("transcript = %q, want %q", data, want)
+	}
+}
+
+func TestRunnerUsageFile(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "termination-log")
+	r := &Runner{
+		UsageFile: usageFile,
+		Stdout:    io.Discard,
+		Stderr:    io.Discard,
+	}
+
+	// The result event is the last line and is not newline-terminated
+	code, err := r.Run(context.Background(), []string{"sh", "-c",
+		`echo '{"type":"assistant","message":{"content":[{"type":"text","text":"the \"result\" is"}]}}'; ` +
+			`printf '{"type":"result","result":"done","num_turns":4,"total_cost_usd":0.0125}'; exit 1`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+
+	data, err := os.ReadFile(usageFile)
+	if err != nil {
+		t.Fatalf("reading usage file: %v", err)
+	}
+	if want := `{"costUSD":"0.0125","numTurns":4}`; string(data) != want {
+		t.Errorf("usage = %s, want %s", data, want)
+	}
+}
+
+func TestRunnerUsageFileWithErrorResult(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "termination-log")
+	r := &Runner{
+		UsageFile: usageFile,
+		Stdout:    io.Discard,
+		Stderr:    io.Discard,
+	}
+
+	result := strings.Repeat("x", maxResultBytes) + "API Error: 529 overloaded"
+	script := fmt.Sprintf(`echo '{"type":"result","subtype":"error_during_execution","is_error":true,"result":"%s","num_turns":2,"total_cost_usd":0.5}'; exit 1`, result)
+	if _, err := r.Run(context.Background(), []string{"sh", "-c", script}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(usageFile)
+	if err != nil {
+		t.Fatalf("reading usage file: %v", err)
+	}
+	var got Termination
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("parsing usage file: %v", err)
+	}
+	if !got.IsError || got.Subtype != "error_during_execution" || got.NumTurns != 2 {
+		t.Errorf("termination = %+v", got)
+	}
+	if len(got.Result) != maxResultBytes || !strings.HasSuffix(got.Result, "529 overloaded") {
+		t.Errorf("expected the last %d bytes of the result, got %q", maxResultBytes, got.Result)
+	}
+}
+
+func TestRunnerUsageFileWithoutResult(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "termination-log")
+	r := &Runner{
+		UsageFile: usageFile,
+		Stdout:    io.Discard,
+		Stderr:    io.Discard,
+	}
+
+	if _, err := r.Run(context.Background(), []string{"sh", "-c", `echo '{"type":"system"}'`}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(usageFile); !os.IsNotExist(err) {
+		t.Errorf("expected no usage file, got err %v", err)
+	}
+}
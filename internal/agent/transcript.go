@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+// outboxTranscript is the file of the outbox the compressed transcript is
+// staged in.
+const outboxTranscript = "transcript.jsonl.gz"
+
+// TranscriptArchiver configures where the agent's transcript is archived.
+type TranscriptArchiver struct {
+	Store artifact.Store
+
+	// Key is the key the gzip-compressed transcript is stored at.
+	Key string
+}
+
+// transcriptArchive compresses the agent's output into the outbox.
+type transcriptArchive struct {
+	file *os.File
+	gz   *gzip.Writer
+}
+
+func newTranscriptArchive(outbox string) (*transcriptArchive, error) {
+	f, err := os.Create(filepath.Join(outbox, outboxTranscript))
+	if err != nil {
+		return nil, fmt.Errorf("creating transcript archive: %w", err)
+	}
+	return &transcriptArchive{file: f, gz: gzip.NewWriter(f)}, nil
+}
+
+func (a *transcriptArchive) Write(p []byte) (int, error) {
+	return a.gz.Write(p)
+}
+
+// close finishes compressing the transcript.
+func (a *transcriptArchive) close() error {
+	err := a.gz.Close()
+	if closeErr := a.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("compressing transcript: %w", err)
+	}
+	return nil
+}
+
+// uploadTranscript uploads the staged transcript.
+func (u *Uploader) uploadTranscript(ctx context.Context) error {
+	root, err := os.OpenRoot(u.Outbox)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	f, err := root.Open(outboxTranscript)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no transcript was staged")
+		}
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", outboxTranscript)
+	}
+	if err := u.Transcript.Store.Put(ctx, u.Transcript.Key, f, info.Size()); err != nil {
+		return fmt.Errorf("uploading transcript: %w", err)
+	}
+	return nil
+}
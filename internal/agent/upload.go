@@ -0,0 +1,216 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+const (
+	// outboxArtifactsDir is the directory of the outbox artifacts are
+	// staged in.
+	outboxArtifactsDir = "artifacts"
+
+	// stagedMarker is created in the outbox once the agent has exited and
+	// its outputs are staged.
+	stagedMarker = ".staged"
+
+	// uploadedMarker is created in the outbox once the outputs are
+	// uploaded.
+	uploadedMarker = ".uploaded"
+
+	// stagingGracePeriod is how long the uploader waits for the outputs to
+	// be staged once it is asked to stop, e.g. because the Pod is deleted
+	// while the agent is running.
+	stagingGracePeriod = 20 * time.Second
+)
+
+// outboxPollInterval is how often the outbox is checked for a marker.
+var outboxPollInterval = 500 * time.Millisecond
+
+// handOver marks the outputs in the outbox as staged and waits for the
+// uploader to upload them, so the Pod does not finish before they are
+// stored.
+func (r *Runner) handOver() {
+	if err := os.WriteFile(filepath.Join(r.Outbox, stagedMarker), nil, 0o644); err != nil {
+		fmt.Fprintf(r.Stderr, "axon-agent: staging outputs: %v\n", err)
+		return
+	}
+	if !waitForFile(context.Background(), filepath.Join(r.Outbox, uploadedMarker), artifactUploadTimeout) {
+		fmt.Fprintf(r.Stderr, "axon-agent: outputs were not uploaded within %s\n", artifactUploadTimeout)
+	}
+}
+
+// ArtifactUpload configures where the staged artifacts are uploaded.
+type ArtifactUpload struct {
+	Store artifact.Store
+
+	// Prefix is prepended to the key of every artifact.
+	Prefix string
+}
+
+// Uploader uploads the outputs a Runner staged in the outbox once the agent
+// has exited. It runs in a container of its own, which alone holds the
+// credentials of the stores.
+type Uploader struct {
+	Client client.Client
+	Task   types.NamespacedName
+
+	// Outbox is the directory the Runner stages the outputs in.
+	Outbox string
+
+	// Artifacts, if set, uploads the staged artifacts and records them in
+	// the Task's status.
+	Artifacts *ArtifactUpload
+
+	// Transcript, if set, archives the staged transcript.
+	Transcript *TranscriptArchiver
+
+	Stderr io.Writer
+}
+
+// Run waits for the outputs to be staged, uploads them, and marks them as
+// uploaded. If ctx is done before the outputs are staged, it waits a little
+// longer and then uploads whatever has been staged. Run returns at once if
+// the outputs were already uploaded, e.g. by a previous run of the
+// container.
+func (u *Uploader) Run(ctx context.Context) error {
+	uploaded := filepath.Join(u.Outbox, uploadedMarker)
+	if _, err := os.Stat(uploaded); err == nil {
+		return nil
+	}
+
+	staged := filepath.Join(u.Outbox, stagedMarker)
+	if !waitForFile(ctx, staged, 0) {
+		if !waitForFile(context.Background(), staged, stagingGracePeriod) {
+			fmt.Fprintln(u.Stderr, "axon-agent: outputs were not staged, uploading what there is")
+		}
+	}
+
+	uploadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), artifactUploadTimeout)
+	defer cancel()
+	var errs []error
+	if u.Artifacts != nil {
+		if err := u.uploadArtifacts(uploadCtx); err != nil {
+			errs = append(errs, fmt.Errorf("uploading artifacts: %w", err))
+		}
+	}
+	if u.Transcript != nil {
+		if err := u.uploadTranscript(uploadCtx); err != nil {
+			errs = append(errs, fmt.Errorf("archiving transcript: %w", err))
+		}
+	}
+
+	// The agent stops waiting once the outputs are marked as uploaded,
+	// even if some of them failed
+	if err := os.WriteFile(uploaded, nil, 0o644); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// uploadArtifacts uploads the staged artifacts and records them in the
+// Task's status.
+func (u *Uploader) uploadArtifacts(ctx context.Context) error {
+	// The agent can write to the outbox, so no symlink it planted may lead
+	// the uploader out of it
+	root, err := os.OpenRoot(filepath.Join(u.Outbox, outboxArtifactsDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer root.Close()
+
+	var artifacts []axonv1alpha1.Artifact
+	err = fs.WalkDir(root.FS(), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		a, err := u.uploadArtifact(ctx, root, name)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, a)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var task axonv1alpha1.Task
+		if err := u.Client.Get(ctx, u.Task, &task); err != nil {
+			return err
+		}
+		task.Status.Artifacts = artifacts
+		return u.Client.Status().Update(ctx, &task)
+	})
+	if err != nil {
+		return fmt.Errorf("recording artifacts: %w", err)
+	}
+
+	fmt.Fprintf(u.Stderr, "axon-agent: uploaded %d artifacts\n", len(artifacts))
+	return nil
+}
+
+func (u *Uploader) uploadArtifact(ctx context.Context, root *os.Root, name string) (axonv1alpha1.Artifact, error) {
+	f, err := root.Open(name)
+	if err != nil {
+		return axonv1alpha1.Artifact{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return axonv1alpha1.Artifact{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return axonv1alpha1.Artifact{}, fmt.Errorf("%s is not a regular file", name)
+	}
+
+	key := path.Join(u.Artifacts.Prefix, name)
+	if err := u.Artifacts.Store.Put(ctx, key, f, info.Size()); err != nil {
+		return axonv1alpha1.Artifact{}, fmt.Errorf("uploading %s: %w", name, err)
+	}
+	return axonv1alpha1.Artifact{Name: name, Key: key, Size: info.Size()}, nil
+}
+
+// waitForFile waits until the file exists and reports whether it does. It
+// gives up once ctx is done or, if timeout is positive, the timeout passes.
+func waitForFile(ctx context.Context, name string, timeout time.Duration) bool {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
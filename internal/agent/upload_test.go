@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+func TestUploaderSkipsSymlinks(t *testing.T) {
+	outboxPollInterval = 10 * time.Millisecond
+	outbox := t.TempDir()
+	secret := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	artifacts := filepath.Join(outbox, outboxArtifactsDir)
+	if err := os.MkdirAll(artifacts, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(artifacts, "report.md"), []byte("report"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Planted by the agent to make the uploader upload its credentials
+	if err := os.Symlink(secret, filepath.Join(artifacts, "environ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outbox, stagedMarker), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	key := types.NamespacedName{Name: "my-task", Namespace: "default"}
+	cl := newFakeClient(t, &axonv1alpha1.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+	})
+	store := &artifact.FileStore{Dir: t.TempDir()}
+	u := &Uploader{
+		Client:    cl,
+		Task:      key,
+		Outbox:    outbox,
+		Artifacts: &ArtifactUpload{Store: store},
+		Stderr:    io.Discard,
+	}
+	if err := u.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rc, err := store.Get(context.Background(), "report.md")
+	if err != nil {
+		t.Fatalf("expected report.md to be uploaded: %v", err)
+	}
+	rc.Close()
+	if _, err := store.Get(context.Background(), "environ"); err != artifact.ErrNotFound {
+		t.Errorf("expected the symlink not to be uploaded, got %v", err)
+	}
+	var task axonv1alpha1.Task
+	if err := cl.Get(context.Background(), key, &task); err != nil {
+		t.Fatalf("getting Task: %v", err)
+	}
+	if len(task.Status.Artifacts) != 1 {
+		t.Errorf("artifacts = %v, want only report.md", task.Status.Artifacts)
+	}
+	if _, err := os.Stat(filepath.Join(outbox, uploadedMarker)); err != nil {
+		t.Errorf("expected the outputs to be marked as uploaded: %v", err)
+	}
+}
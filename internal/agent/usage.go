@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+// maxUsageLineBytes bounds the output line that is buffered while looking
+// for the result event. Longer lines, e.g. large tool results, are skipped.
+const maxUsageLineBytes = 1024 * 1024
+
+// maxResultBytes bounds the end of an error result that is kept, so that
+// the termination message stays within the kubelet's 4096 byte limit.
+const maxResultBytes = 1024
+
+// Termination is what the axon-agent helper writes to the agent container's
+// termination message: the usage from the agent's result event and, if the
+// agent reported an error, what the error was.
+type Termination struct {
+	axonv1alpha1.TaskUsage
+
+	// IsError is set if the agent's result event reported an error.
+	IsError bool `json:"isError,omitempty"`
+
+	// Subtype is the subtype of an error result event, e.g.
+	// error_max_turns.
+	Subtype string `json:"subtype,omitempty"`
+
+	// Result is the end of an error result event's result.
+	Result string `json:"result,omitempty"`
+}
+
+// resultEvent is the part of the agent's stream-json result event that
+// holds its usage and outcome.
+type resultEvent struct {
+	Type         string  `json:"type"`
+	Subtype      string  `json:"subtype"`
+	IsError      bool    `json:"is_error"`
+	Result       string  `json:"result"`
+	NumTurns     int32   `json:"num_turns"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+}
+
+// usageRecorder scans the agent's stream-json output for its result event.
+type usageRecorder struct {
+	line        []byte
+	skip        bool
+	termination *Termination
+}
+
+func (u *usageRecorder) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			u.buffer(p)
+			break
+		}
+		u.buffer(p[:i])
+		u.parseLine()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (u *usageRecorder) buffer(p []byte) {
+	if u.skip {
+		return
+	}
+	if len(u.line)+len(p) > maxUsageLineBytes {
+		u.line = u.line[:0]
+		u.skip = true
+		return
+	}
+	u.line = append(u.line, p...)
+}
+
+func (u *usageRecorder) parseLine() {
+	line := u.line
+	u.line = u.line[:0]
+	if u.skip {
+		u.skip = false
+		return
+	}
+	if !bytes.Contains(line, []byte(`"result"`)) {
+		return
+	}
+	var event resultEvent
+	if err := json.Unmarshal(line, &event); err != nil || event.Type != "result" {
+		return
+	}
+	u.termination = &Termination{
+		TaskUsage: axonv1alpha1.TaskUsage{
+			CostUSD:  strconv.FormatFloat(event.TotalCostUSD, 'f', -1, 64),
+			NumTurns: event.NumTurns,
+		},
+	}
+	if event.IsError {
+		u.termination.IsError = true
+		u.termination.Subtype = event.Subtype
+		u.termination.Result = tail(event.Result, maxResultBytes)
+	}
+}
+
+// tail returns at most the last n bytes of s, without splitting a UTF-8
+// encoded rune.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	for len(s) > 0 && !utf8.RuneStart(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
+
+// writeUsage writes the termination built from the last result event to
+// path as JSON. Nothing is written if the agent did not emit a result
+// event.
+func (u *usageRecorder) writeUsage(path string) error {
+	if len(u.line) > 0 {
+		u.parseLine()
+	}
+	if u.termination == nil {
+		return nil
+	}
+	data, err := json.Marshal(u.termination)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package artifact
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+// Prefixes of the environment variables that configure the stores of the
+// axon-agent helper. They are set on the uploader container by the
+// controller.
+const (
+	// ArtifactsEnvPrefix configures the store of the Task's artifacts.
+	ArtifactsEnvPrefix = "AXON_ARTIFACT_"
+
+	// TranscriptEnvPrefix configures the transcript archive.
+	TranscriptEnvPrefix = "AXON_TRANSCRIPT_"
+)
+
+// Suffixes of the environment variables that configure a store.
+const (
+	// envKey is the key prefix, or the key, of what is stored.
+	envKey = "KEY"
+
+	// envDir is the directory of a FileStore.
+	envDir = "DIR"
+
+	envS3Endpoint        = "S3_ENDPOINT"
+	envS3Bucket          = "S3_BUCKET"
+	envS3Region          = "S3_REGION"
+	envS3AccessKeyID     = "S3_ACCESS_KEY_ID"
+	envS3SecretAccessKey = "S3_SECRET_ACCESS_KEY"
+)
+
+// Keys of the Secret referenced by an S3 store.
+const (
+	AccessKeyIDKey     = "AWS_ACCESS_KEY_ID"
+	SecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
+)
+
+// Env returns the environment variables that configure store, with the
+// given key, for StoreFromEnv. A PersistentVolumeClaim store must be
+// mounted at mountPath.
+func Env(envPrefix string, store *axonv1alpha1.ArtifactStore, key, mountPath string) ([]corev1.EnvVar, error) {
+	env := []corev1.EnvVar{{Name: envPrefix + envKey, Value: key}}
+
+	switch {
+	case store.S3 != nil:
+		s3 := store.S3
+		secretKeyRef := func(key string) *corev1.EnvVarSource {
+			return &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: s3.SecretRef.Name},
+					Key:                  key,
+				},
+			}
+		}
+		env = append(env,
+			corev1.EnvVar{Name: envPrefix + envS3Endpoint, Value: s3.Endpoint},
+			corev1.EnvVar{Name: envPrefix + envS3Bucket, Value: s3.Bucket},
+			corev1.EnvVar{Name: envPrefix + envS3Region, Value: s3.Region},
+			corev1.EnvVar{Name: envPrefix + envS3AccessKeyID, ValueFrom: secretKeyRef(AccessKeyIDKey)},
+			corev1.EnvVar{Name: envPrefix + envS3SecretAccessKey, ValueFrom: secretKeyRef(SecretAccessKeyKey)},
+		)
+	case store.PersistentVolumeClaim != nil:
+		env = append(env, corev1.EnvVar{Name: envPrefix + envDir, Value: mountPath})
+	default:
+		return nil, fmt.Errorf("store must set s3 or persistentVolumeClaim")
+	}
+	return env, nil
+}
+
+// StoreFromEnv returns the store and key configured by the environment
+// variables with the given prefix, as read by getenv.
+func StoreFromEnv(getenv func(string) string, envPrefix string) (Store, string, error) {
+	key := getenv(envPrefix + envKey)
+
+	if dir := getenv(envPrefix + envDir); dir != "" {
+		return &FileStore{Dir: dir}, key, nil
+	}
+
+	if endpoint := getenv(envPrefix + envS3Endpoint); endpoint != "" {
+		bucket := getenv(envPrefix + envS3Bucket)
+		if bucket == "" {
+			return nil, "", fmt.Errorf("%s must be set", envPrefix+envS3Bucket)
+		}
+		return &S3Store{
+			Endpoint:        endpoint,
+			Bucket:          bucket,
+			Region:          getenv(envPrefix + envS3Region),
+			AccessKeyID:     getenv(envPrefix + envS3AccessKeyID),
+			SecretAccessKey: getenv(envPrefix + envS3SecretAccessKey),
+		}, key, nil
+	}
+
+	return nil, "", fmt.Errorf("no store configured: set %s or %s", envPrefix+envDir, envPrefix+envS3Endpoint)
+}
+
+// StorePrefix returns the prefix of the store that every key is placed
+// under.
+func StorePrefix(store *axonv1alpha1.ArtifactStore) string {
+	switch {
+	case store.S3 != nil:
+		return store.S3.Prefix
+	case store.PersistentVolumeClaim != nil:
+		return store.PersistentVolumeClaim.Prefix
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Store.Get when no artifact is stored at a key.
+var ErrNotFound = errors.New("artifact not found")
+
+// Store stores artifacts under slash-separated keys.
+type Store interface {
+	// Put stores size bytes read from body at key, replacing any artifact
+	// already stored there.
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64) error
+
+	// Get returns the artifact stored at key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// List returns the artifacts whose keys start with prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Object describes a stored artifact.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// TaskPrefix returns the key prefix under which the artifacts of a Task are
+// stored. Keying by UID keeps the artifacts of a Task apart from those of a
+// later Task with the same name.
+func TaskPrefix(prefix, namespace, name, uid string) string {
+	return path.Join(prefix, "artifacts", namespace, name, uid)
+}
+
+// TranscriptDir returns the key prefix under which the transcripts of the
+// Tasks with the given name are archived.
+func TranscriptDir(prefix, namespace, name string) string {
+	return path.Join(prefix, "transcripts", namespace, name) + "/"
+}
+
+// TranscriptKey returns the key the compressed transcript of a Task is
+// archived at.
+func TranscriptKey(prefix, namespace, name, uid string) string {
+	return TranscriptDir(prefix, namespace, name) + uid + ".jsonl.gz"
+}
+
+// FileStore stores artifacts as files below a directory, such as the mount
+// path of a PersistentVolumeClaim.
+type FileStore struct {
+	Dir string
+}
+
+// Put implements Store.
+func (s *FileStore) Put(_ context.Context, key string, body io.ReadSeeker, _ int64) error {
+	dst := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a reader never sees a
+	// partially written artifact
+	f, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), dst)
+}
+
+// Get implements Store.
+func (s *FileStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// List implements Store.
+func (s *FileStore) List(_ context.Context, prefix string) ([]Object, error) {
+	// Only the directory holding the prefix needs to be walked
+	dir := s.path(prefix)
+	if !strings.HasSuffix(prefix, "/") {
+		dir = filepath.Dir(dir)
+	}
+
+	var objects []Object
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, strings.TrimPrefix(prefix, "/")) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+func (s *FileStore) path(key string) string {
+	// Clean the key as an absolute path so that it cannot escape Dir
+	return filepath.Join(s.Dir, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+// answeredByCLI is recorded in status.inputRequest.answeredBy for answers
+// given with "axon answer".
+const answeredByCLI = "cli"
+
+func newAnswerCommand(cfg *ClientConfig) *cobra.Command {
+	var requestID string
+
+	cmd := &cobra.Command{
+		Use:   "answer <task> <answer>",
+		Short: "Answer a question asked by a task's agent",
+		Long: `Answer the pending question of a task in the AwaitingInput phase.
+The agent receives the answer and continues running.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			key := client.ObjectKey{Name: args[0], Namespace: ns}
+			err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				var task axonv1alpha1.Task
+				if err := cl.Get(ctx, key, &task); err != nil {
+					return fmt.Errorf("getting task: %w", err)
+				}
+				if err := answerInputRequest(&task, requestID, args[1], answeredByCLI, metav1.Now()); err != nil {
+					return err
+				}
+				return cl.Status().Update(ctx, &task)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stdout, "task/%s answered\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&requestID, "request-id", "", "only answer the question with this ID")
+
+	cmd.ValidArgsFunction = completeTaskNames(cfg)
+
+	return cmd
+}
+
+// answerInputRequest records the answer to the Task's pending input request.
+// If requestID is set, the pending request must have that ID.
+func answerInputRequest(task *axonv1alpha1.Task, requestID, answer, answeredBy string, now metav1.Time) error {
+	req := task.Status.InputRequest
+	if req == nil || req.AnsweredAt != nil {
+		return fmt.Errorf("task %s has no pending question", task.Name)
+	}
+	if requestID != "" && req.ID != requestID {
+		return fmt.Errorf("task %s is waiting for an answer to question %s, not %s", task.Name, req.ID, requestID)
+	}
+	req.Answer = answer
+	req.AnsweredBy = answeredBy
+	req.AnsweredAt = &now
+	return nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func TestAnswerInputRequest(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+
+	tests := []struct {
+		name      string
+		request   *axonv1alpha1.InputRequest
+		requestID string
+		wantErr   bool
+	}{
+		{
+			name:    "No question",
+			wantErr: true,
+		},
+		{
+			name:    "Already answered",
+			request: &axonv1alpha1.InputRequest{ID: "a", Question: "Q", AnsweredAt: &now},
+			wantErr: true,
+		},
+		{
+			name:    "Pending question",
+			request: &axonv1alpha1.InputRequest{ID: "a", Question: "Q"},
+		},
+		{
+			name:      "Matching request ID",
+			request:   &axonv1alpha1.InputRequest{ID: "a", Question: "Q"},
+			requestID: "a",
+		},
+		{
+			name:      "Mismatched request ID",
+			request:   &axonv1alpha1.InputRequest{ID: "a", Question: "Q"},
+			requestID: "b",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-task"},
+				Status:     axonv1alpha1.TaskStatus{InputRequest: tt.request},
+			}
+
+			err := answerInputRequest(task, tt.requestID, "yes", answeredByCLI, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			req := task.Status.InputRequest
+			if req.Answer != "yes" || req.AnsweredBy != answeredByCLI || req.AnsweredAt == nil {
+				t.Errorf("unexpected input request: %+v", req)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+func newApproveCommand(cfg *ClientConfig) *cobra.Command {
+	var digest string
+
+	cmd := &cobra.Command{
+		Use:   "approve <task>",
+		Short: "Approve and push the changes of a task that requires approval",
+		Long: `Approve and push the changes of a task that requires approval.
+
+Only the changes last shown by "axon diff" are approved: the push fails if
+they differ from the changes the task recorded. Use --sha256 to approve
+changes reviewed elsewhere by their digest.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			var task axonv1alpha1.Task
+			if err := cl.Get(ctx, client.ObjectKey{Name: args[0], Namespace: ns}, &task); err != nil {
+				return fmt.Errorf("getting task: %w", err)
+			}
+			if !task.Spec.RequireApproval {
+				return fmt.Errorf("task %s does not require approval", args[0])
+			}
+
+			if digest == "" {
+				digest, err = readReviewedDigest(ns, args[0])
+				if err != nil {
+					return err
+				}
+				if digest == "" {
+					return fmt.Errorf("review the changes of task %s with 'axon diff %s' first", args[0], args[0])
+				}
+			}
+			cm, err := approvalDiff(ctx, cl, &task)
+			if err != nil {
+				return err
+			}
+			if current := agent.DiffSHA256(cm); current != digest {
+				return fmt.Errorf("the changes of task %s have digest %s, not the reviewed %s; review them again with 'axon diff %s'", args[0], current, digest, args[0])
+			}
+
+			if err := cl.Patch(ctx, &task, approvePatch(digest)); err != nil {
+				return fmt.Errorf("patching task: %w", err)
+			}
+			fmt.Fprintf(os.Stdout, "task/%s approved\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&digest, "sha256", "", "SHA-256 digest of the reviewed changes (defaults to the changes last shown by 'axon diff')")
+
+	cmd.ValidArgsFunction = completeTaskNames(cfg)
+
+	return cmd
+}
+
+func newDiffCommand(cfg *ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <task>",
+		Short: "Show the changes of a task that requires approval",
+		Long: `Show the changes of a task that requires approval.
+
+The diff is written to stdout and its digest to stderr. The digest is
+remembered as the one "axon approve" approves.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			var task axonv1alpha1.Task
+			if err := cl.Get(ctx, client.ObjectKey{Name: args[0], Namespace: ns}, &task); err != nil {
+				return fmt.Errorf("getting task: %w", err)
+			}
+			cm, err := approvalDiff(ctx, cl, &task)
+			if err != nil {
+				return err
+			}
+
+			if _, err := os.Stdout.Write(cm.BinaryData[agent.DiffKey]); err != nil {
+				return err
+			}
+			digest := agent.DiffSHA256(cm)
+			fmt.Fprintf(os.Stderr, "sha256: %s\n", digest)
+			return writeReviewedDigest(ns, args[0], digest)
+		},
+	}
+
+	cmd.ValidArgsFunction = completeTaskNames(cfg)
+
+	return cmd
+}
+
+// approvalDiff returns the snapshot of the changes of a task taken by the
+// controller for approval.
+func approvalDiff(ctx context.Context, cl client.Client, task *axonv1alpha1.Task) (*corev1.ConfigMap, error) {
+	var cm corev1.ConfigMap
+	key := client.ObjectKey{Name: agent.ApprovalConfigMapName(task.Name), Namespace: task.Namespace}
+	if err := cl.Get(ctx, key, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("task %s has no changes awaiting approval", task.Name)
+		}
+		return nil, fmt.Errorf("getting diff: %w", err)
+	}
+	if !metav1.IsControlledBy(&cm, task) {
+		return nil, fmt.Errorf("diff ConfigMap %s is not owned by task %s", cm.Name, task.Name)
+	}
+	return &cm, nil
+}
+
+// reviewedDigestPath returns the file recording the digest of the changes
+// of a task last shown by "axon diff" (~/.axon/reviewed/<namespace>/<task>).
+func reviewedDigestPath(namespace, task string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, ".axon", "reviewed", namespace, task), nil
+}
+
+// writeReviewedDigest records the digest of the changes of a task last
+// shown by "axon diff".
+func writeReviewedDigest(namespace, task, digest string) error {
+	path, err := reviewedDigestPath(namespace, task)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("recording reviewed digest: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(digest+"\n"), 0o600); err != nil {
+		return fmt.Errorf("recording reviewed digest: %w", err)
+	}
+	return nil
+}
+
+// readReviewedDigest returns the digest recorded by writeReviewedDigest, or
+// "" if the changes of the task have not been shown.
+func readReviewedDigest(namespace, task string) (string, error) {
+	path, err := reviewedDigestPath(namespace, task)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading reviewed digest: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// approvePatch returns a merge patch that sets spec.approved along with the
+// digest of the approved changes.
+func approvePatch(digest string) client.Patch {
+	data, _ := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"approved":           true,
+			"approvedDiffSHA256": digest,
+		},
+	})
+	return client.RawPatch(types.MergePatchType, data)
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestApprovePatch(t *testing.T) {
+	p := approvePatch("0a1b2c")
+	if p.Type() != types.MergePatchType {
+		t.Errorf("Type() = %q, want %q", p.Type(), types.MergePatchType)
+	}
+	data, err := p.Data(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"spec":{"approved":true,"approvedDiffSHA256":"0a1b2c"}}`; string(data) != want {
+		t.Errorf("Data() = %s, want %s", data, want)
+	}
+}
+
+func TestReviewedDigest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	digest, err := readReviewedDigest("default", "my-task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if digest != "" {
+		t.Errorf("expected no digest before the changes were shown, got %q", digest)
+	}
+
+	if err := writeReviewedDigest("default", "my-task", "0a1b2c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	digest, err = readReviewedDigest("default", "my-task")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if digest != "0a1b2c" {
+		t.Errorf("readReviewedDigest() = %q, want %q", digest, "0a1b2c")
+	}
+
+	if digest, _ := readReviewedDigest("other", "my-task"); digest != "" {
+		t.Errorf("expected no digest in another namespace, got %q", digest)
+	}
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+func newArtifactsCommand(cfg *ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "artifacts",
+		Short: "List and download the artifacts of a task",
+	}
+
+	cmd.AddCommand(
+		newArtifactsListCommand(cfg),
+		newArtifactsGetCommand(cfg),
+	)
+
+	return cmd
+}
+
+func newArtifactsListCommand(cfg *ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <task>",
+		Short: "List the artifacts of a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			var task axonv1alpha1.Task
+			if err := cl.Get(context.Background(), client.ObjectKey{Name: args[0], Namespace: ns}, &task); err != nil {
+				return fmt.Errorf("getting task: %w", err)
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tSIZE\tKEY")
+			for _, a := range task.Status.Artifacts {
+				fmt.Fprintf(tw, "%s\t%d\t%s\n", a.Name, a.Size, a.Key)
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.ValidArgsFunction = completeTaskNames(cfg)
+
+	return cmd
+}
+
+func newArtifactsGetCommand(cfg *ClientConfig) *cobra.Command {
+	var outputDir string
+	var endpoint string
+
+	cmd := &cobra.Command{
+		Use:   "get <task> [artifact...]",
+		Short: "Download the artifacts of a task",
+		Long: "Download the artifacts of a task, or only the named ones, into a directory.\n\n" +
+			"Artifacts in an S3 store are downloaded with the credentials from the store's Secret. " +
+			"Artifacts on a PersistentVolumeClaim are read by a short-lived Pod that mounts the claim.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			var task axonv1alpha1.Task
+			if err := cl.Get(ctx, client.ObjectKey{Name: args[0], Namespace: ns}, &task); err != nil {
+				return fmt.Errorf("getting task: %w", err)
+			}
+
+			artifacts, err := selectArtifacts(task.Status.Artifacts, args[1:])
+			if err != nil {
+				return fmt.Errorf("task %s: %w", task.Name, err)
+			}
+
+			spec := task.Spec.Artifacts
+			if task.Status.EffectiveSpec != nil {
+				spec = task.Status.EffectiveSpec.Artifacts
+			}
+			if spec == nil || spec.Store == nil {
+				return fmt.Errorf("task %s has no artifact store", task.Name)
+			}
+
+			if outputDir == "" {
+				outputDir = task.Name + "-artifacts"
+			}
+
+			switch {
+			case spec.Store.S3 != nil:
+				store, err := newS3Store(ctx, cl, ns, spec.Store.S3, endpoint)
+				if err != nil {
+					return err
+				}
+				for _, a := range artifacts {
+					if err := downloadArtifact(ctx, store, a, outputDir); err != nil {
+						return err
+					}
+				}
+			case spec.Store.PersistentVolumeClaim != nil:
+				cs, _, err := cfg.NewClientset()
+				if err != nil {
+					return err
+				}
+				if err := readArtifactsFromClaim(ctx, cs, &task, spec.Store.PersistentVolumeClaim.ClaimName, artifacts, outputDir); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("task %s has no artifact store", task.Name)
+			}
+
+			for _, a := range artifacts {
+				fmt.Fprintln(os.Stdout, artifactPath(outputDir, a.Name))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "directory to download the artifacts into (default <task>-artifacts)")
+	cmd.Flags().StringVar(&endpoint, "s3-endpoint", "", "S3 endpoint to download from, if the store's endpoint is not reachable from here")
+
+	cmd.ValidArgsFunction = completeTaskNames(cfg)
+
+	return cmd
+}
+
+// selectArtifacts returns the artifacts with the given names, or all
+// artifacts if no names are given.
+func selectArtifacts(artifacts []axonv1alpha1.Artifact, names []string) ([]axonv1alpha1.Artifact, error) {
+	if len(artifacts) == 0 {
+		return nil, errors.New("no artifacts have been uploaded")
+	}
+	if len(names) == 0 {
+		return artifacts, nil
+	}
+
+	byName := make(map[string]axonv1alpha1.Artifact, len(artifacts))
+	for _, a := range artifacts {
+		byName[a.Name] = a
+	}
+	selected := make([]axonv1alpha1.Artifact, 0, len(names))
+	for _, name := range names {
+		a, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no artifact named %q", name)
+		}
+		selected = append(selected, a)
+	}
+	return selected, nil
+}
+
+// artifactPath returns the local path of the named artifact within dir.
+// Names are cleaned so that they cannot escape dir.
+func artifactPath(dir, name string) string {
+	return filepath.Join(dir, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+func writeArtifact(dir, name string, r io.Reader) error {
+	dst := artifactPath(dir, name)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", dst, err)
+	}
+	return f.Close()
+}
+
+// openStore returns the store, reading a PersistentVolumeClaim through
+// reader Pods. endpoint, if set, overrides the endpoint of an S3 store.
+func openStore(ctx context.Context, cfg *ClientConfig, cl client.Client, namespace string, store *axonv1alpha1.ArtifactStore, endpoint string) (artifact.Store, error) {
+	switch {
+	case store.S3 != nil:
+		return newS3Store(ctx, cl, namespace, store.S3, endpoint)
+	case store.PersistentVolumeClaim != nil:
+		cs, _, err := cfg.NewClientset()
+		if err != nil {
+			return nil, err
+		}
+		return &claimStore{reader: &claimReader{
+			Clientset: cs,
+			Namespace: namespace,
+			ClaimName: store.PersistentVolumeClaim.ClaimName,
+		}}, nil
+	default:
+		return nil, errors.New("store must set s3 or persistentVolumeClaim")
+	}
+}
+
+func newS3Store(ctx context.Context, cl client.Client, namespace string, s3 *axonv1alpha1.S3ArtifactStore, endpoint string) (*artifact.S3Store, error) {
+	var secret corev1.Secret
+	if err := cl.Get(ctx, client.ObjectKey{Name: s3.SecretRef.Name, Namespace: namespace}, &secret); err != nil {
+		return nil, fmt.Errorf("getting artifact store credentials: %w", err)
+	}
+	if endpoint == "" {
+		endpoint = s3.Endpoint
+	}
+	return &artifact.S3Store{
+		Endpoint:        endpoint,
+		Bucket:          s3.Bucket,
+		Region:          s3.Region,
+		AccessKeyID:     string(secret.Data[artifact.AccessKeyIDKey]),
+		SecretAccessKey: string(secret.Data[artifact.SecretAccessKeyKey]),
+	}, nil
+}
+
+func downloadArtifact(ctx context.Context, store artifact.Store, a axonv1alpha1.Artifact, dir string) error {
+	rc, err := store.Get(ctx, a.Key)
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", a.Name, err)
+	}
+	defer rc.Close()
+	return writeArtifact(dir, a.Name, rc)
+}
+
+// readArtifactsFromClaim reads the artifacts from the claim as a tar
+// archive written by a reader Pod, and extracts the archive into dir.
+func readArtifactsFromClaim(ctx context.Context, cs kubernetes.Interface, task *axonv1alpha1.Task, claimName string, artifacts []axonv1alpha1.Artifact, dir string) error {
+	names := make(map[string]string, len(artifacts))
+	keys := make([]string, 0, len(artifacts))
+	for _, a := range artifacts {
+		key := path.Clean("/" + a.Key)[1:]
+		names[key] = a.Name
+		keys = append(keys, key)
+	}
+
+	reader := &claimReader{Clientset: cs, Namespace: task.Namespace, ClaimName: claimName}
+	out, err := reader.run(ctx, `tar cf - "$@" | base64`, keys...)
+	if err != nil {
+		return err
+	}
+	return extractArtifacts(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(out)), names, dir)
+}
+
+// extractArtifacts writes the files of the tar archive whose paths are keys
+// of names to dir, under the corresponding artifact name.
+func extractArtifacts(r io.Reader, names map[string]string, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading artifacts: %w", err)
+		}
+		name, ok := names[hdr.Name]
+		if !ok || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := writeArtifact(dir, name, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func TestSelectArtifacts(t *testing.T) {
+	artifacts := []axonv1alpha1.Artifact{
+		{Name: "transcript.jsonl", Key: "ns/task/uid/transcript.jsonl"},
+		{Name: "diff.patch", Key: "ns/task/uid/diff.patch"},
+	}
+
+	got, err := selectArtifacts(artifacts, nil)
+	if err != nil || len(got) != 2 {
+		t.Errorf("selectArtifacts(all) = %v, %v; want both artifacts", got, err)
+	}
+
+	got, err = selectArtifacts(artifacts, []string{"diff.patch"})
+	if err != nil || len(got) != 1 || got[0].Name != "diff.patch" {
+		t.Errorf("selectArtifacts(diff.patch) = %v, %v; want diff.patch", got, err)
+	}
+
+	if _, err := selectArtifacts(artifacts, []string{"missing"}); err == nil {
+		t.Error("expected error for an unknown artifact")
+	}
+	if _, err := selectArtifacts(nil, nil); err == nil {
+		t.Error("expected error for a task without artifacts")
+	}
+}
+
+func TestArtifactPath(t *testing.T) {
+	if got, want := artifactPath("out", "files/reports/summary.md"), filepath.Join("out", "files", "reports", "summary.md"); got != want {
+		t.Errorf("artifactPath = %q, want %q", got, want)
+	}
+	if got, want := artifactPath("out", "../../etc/passwd"), filepath.Join("out", "etc", "passwd"); got != want {
+		t.Errorf("artifactPath = %q, want %q", got, want)
+	}
+}
+
+func TestExtractArtifacts(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for name, body := range map[string]string{
+		"ns/task/uid/diff.patch":      "+hello\n",
+		"ns/task/uid/transcript.json": "not requested",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// base64 wraps its output, like the reader Pod does
+	encoded := base64.StdEncoding.EncodeToString(archive.Bytes())
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded + "\n")
+
+	dir := t.TempDir()
+	names := map[string]string{"ns/task/uid/diff.patch": "diff.patch"}
+	if err := extractArtifacts(base64.NewDecoder(base64.StdEncoding, strings.NewReader(wrapped.String())), names, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "diff.patch"))
+	if err != nil {
+		t.Fatalf("reading extracted artifact: %v", err)
+	}
+	if string(data) != "+hello\n" {
+		t.Errorf("diff.patch = %q, want %q", data, "+hello\n")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "transcript.json")); err == nil {
+		t.Error("expected artifacts that were not requested to be skipped")
+	}
+}
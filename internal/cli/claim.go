@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+const (
+	// claimReaderImage is the image of the Pods that read artifacts and
+	// transcripts from a PersistentVolumeClaim.
+	claimReaderImage = "busybox:1.37"
+
+	claimReaderMountPath = "/store"
+
+	claimReaderTimeout = 2 * time.Minute
+
+	// claimReaderNotFound is the exit code of a reader Pod that did not
+	// find what it was asked to read.
+	claimReaderNotFound = 3
+)
+
+// claimReader reads from a PersistentVolumeClaim by running short-lived
+// Pods that mount the claim and write what they read to their log.
+type claimReader struct {
+	Clientset kubernetes.Interface
+	Namespace string
+	ClaimName string
+}
+
+// errClaimReaderExit is returned by claimReader.run when the script exits
+// with a non-zero code.
+type errClaimReaderExit struct {
+	code int32
+}
+
+func (e *errClaimReaderExit) Error() string {
+	return fmt.Sprintf("reader Pod exited with code %d", e.code)
+}
+
+// run runs the shell script with the given arguments in a Pod whose working
+// directory is the root of the claim, and returns the Pod's log.
+func (r *claimReader) run(ctx context.Context, script string, args ...string) ([]byte, error) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "axon-reader-",
+			Namespace:    r.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "axon",
+				"app.kubernetes.io/component":  "reader",
+				"app.kubernetes.io/managed-by": "axon-cli",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:       "reader",
+				Image:      claimReaderImage,
+				Command:    append([]string{"sh", "-c", script, "--"}, args...),
+				WorkingDir: claimReaderMountPath,
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "store",
+					MountPath: claimReaderMountPath,
+					ReadOnly:  true,
+				}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "store",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: r.ClaimName,
+						ReadOnly:  true,
+					},
+				},
+			}},
+		},
+	}
+
+	pods := r.Clientset.CoreV1().Pods(r.Namespace)
+	pod, err := pods.Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("creating reader Pod: %w", err)
+	}
+	defer func() {
+		_ = pods.Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, claimReaderTimeout)
+	defer cancel()
+	for pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		select {
+		case <-waitCtx.Done():
+			return nil, fmt.Errorf("waiting for reader Pod %s: %w", pod.Name, waitCtx.Err())
+		case <-time.After(time.Second):
+		}
+		if pod, err = pods.Get(ctx, pod.Name, metav1.GetOptions{}); err != nil {
+			return nil, fmt.Errorf("getting reader Pod: %w", err)
+		}
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		for _, s := range pod.Status.ContainerStatuses {
+			if t := s.State.Terminated; t != nil {
+				return nil, &errClaimReaderExit{code: t.ExitCode}
+			}
+		}
+		return nil, fmt.Errorf("reader Pod %s failed", pod.Name)
+	}
+
+	stream, err := pods.GetLogs(pod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading reader Pod logs: %w", err)
+	}
+	defer stream.Close()
+	return io.ReadAll(stream)
+}
+
+// claimStore is a read-only artifact.Store backed by a claimReader.
+type claimStore struct {
+	reader *claimReader
+}
+
+// Put implements artifact.Store.
+func (s *claimStore) Put(context.Context, string, io.ReadSeeker, int64) error {
+	return fmt.Errorf("claim %s is read-only", s.reader.ClaimName)
+}
+
+// Get implements artifact.Store.
+func (s *claimStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.reader.run(ctx, fmt.Sprintf(`[ -f "$1" ] || exit %d; base64 "$1"`, claimReaderNotFound), cleanKey(key))
+	if err != nil {
+		var exit *errClaimReaderExit
+		if errors.As(err, &exit) && exit.code == claimReaderNotFound {
+			return nil, artifact.ErrNotFound
+		}
+		return nil, err
+	}
+	return io.NopCloser(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(out))), nil
+}
+
+// List implements artifact.Store.
+func (s *claimStore) List(ctx context.Context, prefix string) ([]artifact.Object, error) {
+	prefix = cleanKey(prefix)
+	dir := strings.TrimSuffix(prefix, "/")
+	if !strings.HasSuffix(prefix, "/") {
+		dir = path.Dir(prefix)
+	}
+
+	out, err := s.reader.run(ctx, `[ -d "$1" ] || exit 0; find "$1" -type f -exec stat -c '%s %Y %n' {} +`, dir)
+	if err != nil {
+		return nil, err
+	}
+	return parseClaimListing(out, prefix)
+}
+
+// parseClaimListing parses the "<size> <mtime> <path>" lines written by
+// stat, keeping the files whose paths start with prefix.
+func parseClaimListing(out []byte, prefix string) ([]artifact.Object, error) {
+	var objects []artifact.Object
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing listing: %w", err)
+		}
+		mtime, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing listing: %w", err)
+		}
+		key := path.Clean(fields[2])
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		objects = append(objects, artifact.Object{Key: key, Size: size, LastModified: time.Unix(mtime, 0)})
+	}
+	return objects, scanner.Err()
+}
+
+// cleanKey returns key relative to the root of the claim, keeping a
+// trailing slash.
+func cleanKey(key string) string {
+	cleaned := strings.TrimPrefix(path.Clean("/"+key), "/")
+	if strings.HasSuffix(key, "/") && cleaned != "" {
+		cleaned += "/"
+	}
+	return cleaned
+}
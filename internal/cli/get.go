@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
@@ -125,6 +126,14 @@ func newGetTaskCommand(cfg *ClientConfig) *cobra.Command {
 					return printJSON(os.Stdout, task)
 				default:
 					printTaskDetail(os.Stdout, task)
+					// Events are best effort, the user may not be allowed
+					// to list them
+					var events corev1.EventList
+					if err := cl.List(ctx, &events, client.InNamespace(ns), client.MatchingFields{
+						"involvedObject.uid": string(task.UID),
+					}); err == nil {
+						printEvents(os.Stdout, events.Items)
+					}
 					return nil
 				}
 			}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -37,9 +39,25 @@ func newLogsCommand(cfg *ClientConfig) *cobra.Command {
 			ctx := context.Background()
 			task := &axonv1alpha1.Task{}
 			if err := cl.Get(ctx, client.ObjectKey{Name: args[0], Namespace: ns}, task); err != nil {
+				if apierrors.IsNotFound(err) {
+					archiveErr := printDeletedTaskTranscript(ctx, cfg, cl, ns, args[0])
+					if !errors.Is(archiveErr, errNoArchivedTranscript) {
+						return archiveErr
+					}
+				}
 				return fmt.Errorf("getting task: %w", err)
 			}
 
+			// The archive outlives the Pod, so prefer it once the Task has
+			// finished and fall back to the Pod if nothing was archived
+			finished := task.Status.Phase == axonv1alpha1.TaskPhaseSucceeded || task.Status.Phase == axonv1alpha1.TaskPhaseFailed
+			if archive := task.Status.TranscriptArchive; archive != nil && finished {
+				err := printArchivedTranscript(ctx, cfg, cl, ns, &archive.Store, archive.Key)
+				if !errors.Is(err, errNoArchivedTranscript) {
+					return err
+				}
+			}
+
 			if task.Status.PodName == "" {
 				if !follow {
 					return fmt.Errorf("task %q has no pod yet", args[0])
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"text/tabwriter"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
 	"sigs.k8s.io/yaml"
 
@@ -29,17 +32,58 @@ func printTaskDetail(w io.Writer, t *axonv1alpha1.Task) {
 	printField(w, "Type", t.Spec.Type)
 	printField(w, "Phase", string(t.Status.Phase))
 	printField(w, "Prompt", t.Spec.Prompt)
-	printField(w, "Secret", t.Spec.Credentials.SecretRef.Name)
-	printField(w, "Credential Type", string(t.Spec.Credentials.Type))
-	if t.Spec.Model != "" {
-		printField(w, "Model", t.Spec.Model)
+	spec := &t.Spec
+	if t.Status.EffectiveSpec != nil {
+		spec = t.Status.EffectiveSpec
+	}
+	if spec.Credentials != nil {
+		printField(w, "Secret", spec.Credentials.SecretRef.Name)
+		printField(w, "Credential Type", string(spec.Credentials.Type))
+	}
+	if spec.Model != "" {
+		printField(w, "Model", spec.Model)
 	}
 	if t.Spec.WorkspaceRef != nil {
 		printField(w, "Workspace", t.Spec.WorkspaceRef.Name)
 	}
+	if t.Spec.ContinueFrom != nil {
+		printField(w, "Continue From", t.Spec.ContinueFrom.Name)
+	}
+	if t.Status.SessionClaimName != "" {
+		printField(w, "Session", t.Status.SessionClaimName)
+	}
+	if req := t.Status.InputRequest; req != nil {
+		printField(w, "Question", req.Question)
+		if req.AnsweredAt != nil {
+			printField(w, "Answer", fmt.Sprintf("%s (by %s)", req.Answer, req.AnsweredBy))
+		}
+	}
 	if t.Status.JobName != "" {
 		printField(w, "Job", t.Status.JobName)
 	}
+	if t.Spec.RequireApproval {
+		approved := "No"
+		if t.Spec.Approved {
+			approved = "Yes"
+		}
+		printField(w, "Approved", approved)
+	}
+	if t.Status.DiffConfigMapName != "" {
+		printField(w, "Diff", t.Status.DiffConfigMapName)
+	}
+	if t.Status.PushJobName != "" {
+		printField(w, "Push Job", t.Status.PushJobName)
+	}
+	if a := t.Status.TranscriptArchive; a != nil {
+		printField(w, "Transcript", a.Key)
+	}
+	if n := len(t.Status.Artifacts); n > 0 {
+		printField(w, "Artifacts", fmt.Sprintf("%d", n))
+	}
+	if u := t.Status.Usage; u != nil {
+		printField(w, "Cost", "$"+u.CostUSD)
+		printField(w, "Turns", fmt.Sprintf("%d", u.NumTurns))
+	}
 	if t.Status.PodName != "" {
 		printField(w, "Pod", t.Status.PodName)
 	}
@@ -49,9 +93,13 @@ func printTaskDetail(w io.Writer, t *axonv1alpha1.Task) {
 	if t.Status.CompletionTime != nil {
 		printField(w, "Completion Time", t.Status.CompletionTime.Time.Format(time.RFC3339))
 	}
+	if t.Status.FailureReason != "" {
+		printField(w, "Failure Reason", string(t.Status.FailureReason))
+	}
 	if t.Status.Message != "" {
 		printField(w, "Message", t.Status.Message)
 	}
+	printConditions(w, t.Status.Conditions)
 }
 
 func printTaskSpawnerTable(w io.Writer, spawners []axonv1alpha1.TaskSpawner) {
@@ -60,8 +108,8 @@ func printTaskSpawnerTable(w io.Writer, spawners []axonv1alpha1.TaskSpawner) {
 	for _, s := range spawners {
 		age := duration.HumanDuration(time.Since(s.CreationTimestamp.Time))
 		source := ""
-		if s.Spec.When.GitHubIssues != nil && s.Spec.When.GitHubIssues.WorkspaceRef != nil {
-			source = s.Spec.When.GitHubIssues.WorkspaceRef.Name
+		if ref := s.Spec.When.WorkspaceRef(); ref != nil {
+			source = ref.Name
 		}
 		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n",
 			s.Name, source, s.Status.Phase,
@@ -90,11 +138,71 @@ func printTaskSpawnerDetail(w io.Writer, ts *axonv1alpha1.TaskSpawner) {
 			printField(w, "Labels", fmt.Sprintf("%v", gh.Labels))
 		}
 	}
+	if ts.Spec.When.GitHubComments != nil {
+		gc := ts.Spec.When.GitHubComments
+		printField(w, "Source", "GitHub Comments")
+		if gc.WorkspaceRef != nil {
+			printField(w, "Workspace", gc.WorkspaceRef.Name)
+		}
+		if gc.Command != "" {
+			printField(w, "Command", gc.Command)
+		}
+		if len(gc.Types) > 0 {
+			printField(w, "Types", fmt.Sprintf("%v", gc.Types))
+		}
+	}
+	if ts.Spec.When.GitHubWorkflowRuns != nil {
+		wr := ts.Spec.When.GitHubWorkflowRuns
+		printField(w, "Source", "GitHub Workflow Runs")
+		if wr.WorkspaceRef != nil {
+			printField(w, "Workspace", wr.WorkspaceRef.Name)
+		}
+		if len(wr.Workflows) > 0 {
+			printField(w, "Workflows", fmt.Sprintf("%v", wr.Workflows))
+		}
+		if len(wr.Branches) > 0 {
+			printField(w, "Branches", fmt.Sprintf("%v", wr.Branches))
+		}
+	}
+	if ts.Spec.When.HTTP != nil {
+		h := ts.Spec.When.HTTP
+		printField(w, "Source", "HTTP")
+		printField(w, "URL", h.URL)
+		if h.WorkspaceRef != nil {
+			printField(w, "Workspace", h.WorkspaceRef.Name)
+		}
+	}
+	if ts.Spec.When.JiraIssues != nil {
+		j := ts.Spec.When.JiraIssues
+		printField(w, "Source", "Jira Issues")
+		printField(w, "URL", j.URL)
+		printField(w, "JQL", j.JQL)
+		if j.WorkspaceRef != nil {
+			printField(w, "Workspace", j.WorkspaceRef.Name)
+		}
+	}
+	if ts.Spec.When.LinearIssues != nil {
+		l := ts.Spec.When.LinearIssues
+		printField(w, "Source", "Linear Issues")
+		printField(w, "Team", l.Team)
+		if len(l.States) > 0 {
+			printField(w, "States", fmt.Sprintf("%v", l.States))
+		}
+		if len(l.Labels) > 0 {
+			printField(w, "Labels", fmt.Sprintf("%v", l.Labels))
+		}
+		if l.WorkspaceRef != nil {
+			printField(w, "Workspace", l.WorkspaceRef.Name)
+		}
+	}
 	printField(w, "Task Type", ts.Spec.TaskTemplate.Type)
 	if ts.Spec.TaskTemplate.Model != "" {
 		printField(w, "Model", ts.Spec.TaskTemplate.Model)
 	}
 	printField(w, "Poll Interval", ts.Spec.PollInterval)
+	if ts.Spec.Suspend {
+		printField(w, "Suspended", "true")
+	}
 	if ts.Status.DeploymentName != "" {
 		printField(w, "Deployment", ts.Status.DeploymentName)
 	}
@@ -106,6 +214,69 @@ func printTaskSpawnerDetail(w io.Writer, ts *axonv1alpha1.TaskSpawner) {
 	if ts.Status.Message != "" {
 		printField(w, "Message", ts.Status.Message)
 	}
+	printConditions(w, ts.Status.Conditions)
+}
+
+// printConditions prints the status conditions of a resource.
+func printConditions(w io.Writer, conditions []metav1.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Conditions:")
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+	for _, c := range conditions {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+	}
+	tw.Flush()
+}
+
+// maxEvents is the number of most recent Events shown in detail output.
+const maxEvents = 10
+
+// printEvents prints the most recent of the given Events, oldest first.
+func printEvents(w io.Writer, events []corev1.Event) {
+	if len(events) == 0 {
+		return
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(&events[i]).Before(eventTime(&events[j]))
+	})
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+
+	fmt.Fprintln(w, "Events:")
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "  LAST SEEN\tTYPE\tREASON\tMESSAGE")
+	for i := range events {
+		e := &events[i]
+		count := e.Count
+		if e.Series != nil && e.Series.Count > count {
+			count = e.Series.Count
+		}
+		message := e.Message
+		if count > 1 {
+			message = fmt.Sprintf("%s (x%d)", message, count)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", duration.HumanDuration(time.Since(eventTime(e))), e.Type, e.Reason, message)
+	}
+	tw.Flush()
+}
+
+// eventTime returns when the Event was last seen. Events recorded through
+// the events.k8s.io API only set the event time or series.
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case e.Series != nil:
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
 }
 
 func printField(w io.Writer, label, value string) {
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrintEvents(t *testing.T) {
+	now := time.Now()
+	var events []corev1.Event
+	for i := 0; i < maxEvents+2; i++ {
+		events = append(events, corev1.Event{
+			Type:      corev1.EventTypeNormal,
+			Reason:    fmt.Sprintf("Reason%02d", i),
+			Message:   "message",
+			EventTime: metav1.NewMicroTime(now.Add(-time.Duration(i) * time.Minute)),
+		})
+	}
+	events[0].Series = &corev1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(now)}
+
+	var buf bytes.Buffer
+	printEvents(&buf, events)
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "Events:\n") {
+		t.Errorf("expected Events header, got:\n%s", out)
+	}
+	// The two oldest events are dropped
+	for _, reason := range []string{"Reason10", "Reason11"} {
+		if strings.Contains(out, reason) {
+			t.Errorf("expected %s to be dropped, got:\n%s", reason, out)
+		}
+	}
+	if strings.Index(out, "Reason09") > strings.Index(out, "Reason00") {
+		t.Errorf("expected events oldest first, got:\n%s", out)
+	}
+	if !strings.Contains(out, "message (x3)") {
+		t.Errorf("expected the series count, got:\n%s", out)
+	}
+
+	buf.Reset()
+	printEvents(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without events, got %q", buf.String())
+	}
+}
+
+func TestPrintConditions(t *testing.T) {
+	var buf bytes.Buffer
+	printConditions(&buf, []metav1.Condition{{
+		Type:    "Succeeded",
+		Status:  metav1.ConditionFalse,
+		Reason:  "WorkspaceNotFound",
+		Message: `Workspace "missing" not found`,
+	}})
+	out := buf.String()
+	if !strings.HasPrefix(out, "Conditions:\n") {
+		t.Errorf("expected Conditions header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Succeeded") || !strings.Contains(out, "WorkspaceNotFound") {
+		t.Errorf("expected the condition, got:\n%s", out)
+	}
+
+	buf.Reset()
+	printConditions(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without conditions, got %q", buf.String())
+	}
+}
@@ -43,6 +43,12 @@ func NewRootCommand() *cobra.Command {
 		newGetCommand(cfg),
 		newLogsCommand(cfg),
 		newDeleteCommand(cfg),
+		newSuspendCommand(cfg),
+		newResumeCommand(cfg),
+		newAnswerCommand(cfg),
+		newApproveCommand(cfg),
+		newDiffCommand(cfg),
+		newArtifactsCommand(cfg),
 		newInitCommand(cfg),
 		newInstallCommand(cfg),
 		newUninstallCommand(cfg),
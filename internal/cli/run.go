@@ -18,14 +18,22 @@ import (
 
 func newRunCommand(cfg *ClientConfig) *cobra.Command {
 	var (
-		prompt         string
-		agentType      string
-		secret         string
-		credentialType string
-		model          string
-		name           string
-		watch          bool
-		workspace      string
+		prompt           string
+		agentType        string
+		secret           string
+		credentialType   string
+		model            string
+		name             string
+		watch            bool
+		workspace        string
+		persistSession   bool
+		continueFrom     string
+		humanInput       bool
+		inputTimeout     time.Duration
+		defaultAnswer    string
+		approval         bool
+		collectArtifacts bool
+		artifactPaths    []string
 	)
 
 	cmd := &cobra.Command{
@@ -67,17 +75,28 @@ func newRunCommand(cfg *ClientConfig) *cobra.Command {
 				}
 			}
 
-			if secret == "" {
-				return fmt.Errorf("no credentials configured (set oauthToken/apiKey in config file, or use --secret flag)")
-			}
-
 			cl, ns, err := cfg.NewClient()
 			if err != nil {
 				return err
 			}
 
+			// Continue on the workspace of the previous Task unless a
+			// workspace is given explicitly.
+			if continueFrom != "" {
+				prev := &axonv1alpha1.Task{}
+				if err := cl.Get(context.Background(), client.ObjectKey{Name: continueFrom, Namespace: ns}, prev); err != nil {
+					return fmt.Errorf("getting task to continue from: %w", err)
+				}
+				if !cmd.Flags().Changed("workspace") {
+					workspace = ""
+					if prev.Spec.WorkspaceRef != nil {
+						workspace = prev.Spec.WorkspaceRef.Name
+					}
+				}
+			}
+
 			// Auto-create Workspace CR from inline config if no --workspace flag.
-			if workspace == "" && cfg.Config != nil && cfg.Config.Workspace.Repo != "" {
+			if workspace == "" && continueFrom == "" && cfg.Config != nil && cfg.Config.Workspace.Repo != "" {
 				wsCfg := cfg.Config.Workspace
 				wsName := "axon-workspace"
 				ws := &axonv1alpha1.Workspace{
@@ -125,18 +144,46 @@ func newRunCommand(cfg *ClientConfig) *cobra.Command {
 					Namespace: ns,
 				},
 				Spec: axonv1alpha1.TaskSpec{
-					Type:   agentType,
-					Prompt: prompt,
-					Credentials: axonv1alpha1.Credentials{
-						Type: axonv1alpha1.CredentialType(credentialType),
-						SecretRef: axonv1alpha1.SecretReference{
-							Name: secret,
-						},
-					},
-					Model: model,
+					Type:            agentType,
+					Prompt:          prompt,
+					Model:           model,
+					PersistSession:  persistSession,
+					RequireApproval: approval,
 				},
 			}
 
+			if continueFrom != "" {
+				task.Spec.ContinueFrom = &axonv1alpha1.TaskReference{
+					Name: continueFrom,
+				}
+			}
+
+			if collectArtifacts || len(artifactPaths) > 0 {
+				task.Spec.Artifacts = &axonv1alpha1.ArtifactsSpec{
+					Paths: artifactPaths,
+				}
+			}
+
+			if humanInput || cmd.Flags().Changed("input-timeout") || cmd.Flags().Changed("default-answer") {
+				task.Spec.HumanInput = &axonv1alpha1.HumanInputPolicy{
+					DefaultAnswer: defaultAnswer,
+				}
+				if inputTimeout > 0 {
+					task.Spec.HumanInput.Timeout = &metav1.Duration{Duration: inputTimeout}
+				}
+			}
+
+			// Without an explicit secret, the Task relies on the default
+			// credentials from the namespace's AxonConfig.
+			if secret != "" {
+				task.Spec.Credentials = &axonv1alpha1.Credentials{
+					Type: axonv1alpha1.CredentialType(credentialType),
+					SecretRef: axonv1alpha1.SecretReference{
+						Name: secret,
+					},
+				}
+			}
+
 			if workspace != "" {
 				task.Spec.WorkspaceRef = &axonv1alpha1.WorkspaceReference{
 					Name: workspace,
@@ -158,15 +205,24 @@ func newRunCommand(cfg *ClientConfig) *cobra.Command {
 
 	cmd.Flags().StringVarP(&prompt, "prompt", "p", "", "task prompt (required)")
 	cmd.Flags().StringVarP(&agentType, "type", "t", "claude-code", "agent type")
-	cmd.Flags().StringVar(&secret, "secret", "", "secret name with credentials (overrides oauthToken/apiKey in config)")
+	cmd.Flags().StringVar(&secret, "secret", "", "secret name with credentials (overrides oauthToken/apiKey in config; defaults to the namespace's AxonConfig)")
 	cmd.Flags().StringVar(&credentialType, "credential-type", "api-key", "credential type (api-key or oauth)")
 	cmd.Flags().StringVar(&model, "model", "", "model override")
 	cmd.Flags().StringVar(&name, "name", "", "task name (auto-generated if omitted)")
 	cmd.Flags().StringVar(&workspace, "workspace", "", "name of Workspace resource to use")
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch task status after creation")
+	cmd.Flags().BoolVar(&persistSession, "persist-session", false, "keep the agent's session so a later task can continue it with --continue-from")
+	cmd.Flags().StringVar(&continueFrom, "continue-from", "", "name of a finished task whose agent session to continue")
+	cmd.Flags().BoolVar(&approval, "require-approval", false, "hold the agent's changes until 'axon approve' pushes them (requires --workspace)")
+	cmd.Flags().BoolVar(&collectArtifacts, "artifacts", false, "upload the agent's transcript and diff to the artifact store of the namespace's AxonConfig")
+	cmd.Flags().StringArrayVar(&artifactPaths, "artifact-path", nil, "file, directory or glob pattern to upload as an artifact (repeatable; implies --artifacts)")
+	cmd.Flags().BoolVar(&humanInput, "human-input", false, "let the agent ask questions; answer them with 'axon answer'")
+	cmd.Flags().DurationVar(&inputTimeout, "input-timeout", 0, "how long a question may stay unanswered before the default answer is used (implies --human-input)")
+	cmd.Flags().StringVar(&defaultAnswer, "default-answer", "", "answer given to the agent when --input-timeout elapses (implies --human-input)")
 
 	cmd.MarkFlagRequired("prompt")
 
+	_ = cmd.RegisterFlagCompletionFunc("continue-from", completeTaskNames(cfg))
 	_ = cmd.RegisterFlagCompletionFunc("credential-type", cobra.FixedCompletions([]string{"api-key", "oauth"}, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
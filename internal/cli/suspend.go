@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func newSuspendCommand(cfg *ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suspend",
+		Short: "Suspend resources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Help()
+			return fmt.Errorf("must specify a resource type")
+		},
+	}
+
+	cmd.AddCommand(newSetSuspendTaskCommand(cfg, true))
+	cmd.AddCommand(newSetSuspendTaskSpawnerCommand(cfg, true))
+
+	return cmd
+}
+
+func newResumeCommand(cfg *ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume suspended resources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Help()
+			return fmt.Errorf("must specify a resource type")
+		},
+	}
+
+	cmd.AddCommand(newSetSuspendTaskCommand(cfg, false))
+	cmd.AddCommand(newSetSuspendTaskSpawnerCommand(cfg, false))
+
+	return cmd
+}
+
+func newSetSuspendTaskCommand(cfg *ClientConfig, suspend bool) *cobra.Command {
+	short := "Stop a task's agent without deleting the task"
+	if !suspend {
+		short = "Start a new run of a suspended task"
+	}
+
+	cmd := &cobra.Command{
+		Use:     "task <name>",
+		Aliases: []string{"tasks"},
+		Short:   short,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      args[0],
+					Namespace: ns,
+				},
+			}
+			if err := cl.Patch(context.Background(), task, suspendPatch(suspend)); err != nil {
+				return fmt.Errorf("patching task: %w", err)
+			}
+			fmt.Fprintf(os.Stdout, "task/%s %s\n", args[0], suspendVerb(suspend))
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = completeTaskNames(cfg)
+
+	return cmd
+}
+
+func newSetSuspendTaskSpawnerCommand(cfg *ClientConfig, suspend bool) *cobra.Command {
+	short := "Pause discovery of a task spawner"
+	if !suspend {
+		short = "Resume discovery of a task spawner"
+	}
+
+	cmd := &cobra.Command{
+		Use:     "taskspawner <name>",
+		Aliases: []string{"taskspawners", "ts"},
+		Short:   short,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cl, ns, err := cfg.NewClient()
+			if err != nil {
+				return err
+			}
+
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      args[0],
+					Namespace: ns,
+				},
+			}
+			if err := cl.Patch(context.Background(), ts, suspendPatch(suspend)); err != nil {
+				return fmt.Errorf("patching task spawner: %w", err)
+			}
+			fmt.Fprintf(os.Stdout, "taskspawner/%s %s\n", args[0], suspendVerb(suspend))
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = completeTaskSpawnerNames(cfg)
+
+	return cmd
+}
+
+// suspendPatch returns a merge patch that sets spec.suspend.
+func suspendPatch(suspend bool) client.Patch {
+	return client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)))
+}
+
+func suspendVerb(suspend bool) string {
+	if suspend {
+		return "suspended"
+	}
+	return "resumed"
+}
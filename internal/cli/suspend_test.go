@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSuspendPatch(t *testing.T) {
+	tests := []struct {
+		suspend bool
+		want    string
+	}{
+		{suspend: true, want: `{"spec":{"suspend":true}}`},
+		{suspend: false, want: `{"spec":{"suspend":false}}`},
+	}
+
+	for _, tt := range tests {
+		p := suspendPatch(tt.suspend)
+		if p.Type() != types.MergePatchType {
+			t.Errorf("Type() = %q, want %q", p.Type(), types.MergePatchType)
+		}
+		data, err := p.Data(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("Data() = %s, want %s", data, tt.want)
+		}
+	}
+}
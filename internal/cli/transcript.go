@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+// errNoArchivedTranscript is returned when a Task's transcript has not been
+// archived.
+var errNoArchivedTranscript = errors.New("no archived transcript")
+
+// printArchivedTranscript prints the transcript archived at key.
+func printArchivedTranscript(ctx context.Context, cfg *ClientConfig, cl client.Client, namespace string, store *axonv1alpha1.ArtifactStore, key string) error {
+	s, err := openStore(ctx, cfg, cl, namespace, store, "")
+	if err != nil {
+		return err
+	}
+
+	rc, err := s.Get(ctx, key)
+	if errors.Is(err, artifact.ErrNotFound) {
+		return errNoArchivedTranscript
+	}
+	if err != nil {
+		return fmt.Errorf("reading archived transcript: %w", err)
+	}
+	defer rc.Close()
+
+	gz, err := gzip.NewReader(rc)
+	if err != nil {
+		return fmt.Errorf("decompressing archived transcript: %w", err)
+	}
+	defer gz.Close()
+	return ParseAndFormatLogs(gz, os.Stdout, os.Stderr)
+}
+
+// printDeletedTaskTranscript prints the most recently archived transcript of
+// a deleted Task, looked up in the transcript archive of the namespace's
+// AxonConfigs.
+func printDeletedTaskTranscript(ctx context.Context, cfg *ClientConfig, cl client.Client, namespace, name string) error {
+	var configs axonv1alpha1.AxonConfigList
+	if err := cl.List(ctx, &configs, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("listing AxonConfigs: %w", err)
+	}
+	sort.Slice(configs.Items, func(i, j int) bool {
+		return configs.Items[i].Name < configs.Items[j].Name
+	})
+
+	var store *axonv1alpha1.ArtifactStore
+	for _, c := range configs.Items {
+		if c.Spec.TranscriptArchive != nil {
+			store = c.Spec.TranscriptArchive
+			break
+		}
+	}
+	if store == nil {
+		return errNoArchivedTranscript
+	}
+
+	s, err := openStore(ctx, cfg, cl, namespace, store, "")
+	if err != nil {
+		return err
+	}
+	objects, err := s.List(ctx, artifact.TranscriptDir(artifact.StorePrefix(store), namespace, name))
+	if err != nil {
+		return fmt.Errorf("listing archived transcripts: %w", err)
+	}
+	key, ok := latestObject(objects)
+	if !ok {
+		return errNoArchivedTranscript
+	}
+
+	fmt.Fprintf(os.Stderr, "Task %q not found, showing its archived transcript %s\n", name, key)
+	return printArchivedTranscript(ctx, cfg, cl, namespace, store, key)
+}
+
+// latestObject returns the key of the most recently modified object.
+func latestObject(objects []artifact.Object) (string, bool) {
+	if len(objects) == 0 {
+		return "", false
+	}
+	latest := objects[0]
+	for _, o := range objects[1:] {
+		if o.LastModified.After(latest.LastModified) {
+			latest = o
+		}
+	}
+	return latest.Key, true
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+func TestLatestObject(t *testing.T) {
+	now := time.Now()
+	objects := []artifact.Object{
+		{Key: "transcripts/default/my-task/a.jsonl.gz", LastModified: now.Add(-time.Hour)},
+		{Key: "transcripts/default/my-task/b.jsonl.gz", LastModified: now},
+		{Key: "transcripts/default/my-task/c.jsonl.gz", LastModified: now.Add(-2 * time.Hour)},
+	}
+
+	key, ok := latestObject(objects)
+	if !ok || key != "transcripts/default/my-task/b.jsonl.gz" {
+		t.Errorf("latestObject = %q, %v; want the most recent transcript", key, ok)
+	}
+	if _, ok := latestObject(nil); ok {
+		t.Error("expected no object for an empty listing")
+	}
+}
+
+func TestParseClaimListing(t *testing.T) {
+	out := []byte("120 1767322800 transcripts/default/my-task/uid-1.jsonl.gz\n" +
+		"80 1767326400 transcripts/default/my-task-2/uid-2.jsonl.gz\n" +
+		"garbage\n")
+
+	objects, err := parseClaimListing(out, "transcripts/default/my-task/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("objects = %+v, want only the transcript of my-task", objects)
+	}
+	o := objects[0]
+	if o.Key != "transcripts/default/my-task/uid-1.jsonl.gz" || o.Size != 120 || o.LastModified.Unix() != 1767322800 {
+		t.Errorf("unexpected object: %+v", o)
+	}
+}
+
+func TestCleanKey(t *testing.T) {
+	tests := map[string]string{
+		"transcripts/default/my-task/":    "transcripts/default/my-task/",
+		"/artifacts/default/x/diff.patch": "artifacts/default/x/diff.patch",
+		"../../etc/passwd":                "etc/passwd",
+	}
+	for key, want := range tests {
+		if got := cleanKey(key); got != want {
+			t.Errorf("cleanKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
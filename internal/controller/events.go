@@ -0,0 +1,35 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Reasons of the Events recorded for Tasks and TaskSpawners.
+const (
+	reasonWorkspaceNotFound  = "WorkspaceNotFound"
+	reasonMissingCredentials = "MissingCredentials"
+	reasonJobBuildFailed     = "JobBuildFailed"
+	reasonJobCreated         = "JobCreated"
+	reasonSucceeded          = "Succeeded"
+	reasonFailed             = "Failed"
+	reasonOOMKilled          = "OOMKilled"
+	reasonDeploymentCreated  = "DeploymentCreated"
+	reasonNotificationSent   = "NotificationSent"
+	reasonNotificationFailed = "NotificationFailed"
+)
+
+// oomKilledContainer returns the name of the first container of the Pod
+// that was killed for running out of memory.
+func oomKilledContainer(pod *corev1.Pod) (string, bool) {
+	if pod == nil {
+		return "", false
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			if t := s.State.Terminated; t != nil && t.Reason == "OOMKilled" {
+				return s.Name, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestOOMKilledContainer(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  "claude-code",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+	}}}}
+	if _, ok := oomKilledContainer(pod); ok {
+		t.Error("expected no OOMKilled container")
+	}
+
+	pod.Status.ContainerStatuses[0].State.Terminated.Reason = "OOMKilled"
+	if name, ok := oomKilledContainer(pod); !ok || name != "claude-code" {
+		t.Errorf("oomKilledContainer = %q, %v, want claude-code, true", name, ok)
+	}
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+// imagePullReasons are the waiting reasons of a container whose image
+// cannot be pulled.
+var imagePullReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// gitAuthErrors are printed by git when it cannot authenticate to the
+// remote.
+var gitAuthErrors = []string{
+	"Authentication failed",
+	"could not read Username",
+	"Invalid username or password",
+	"terminal prompts disabled",
+}
+
+// stuckPod returns why the Pod cannot start, if it is stuck because it
+// cannot be scheduled or one of its containers cannot be created.
+func stuckPod(pod *corev1.Pod) (axonv1alpha1.TaskFailureReason, string, bool) {
+	if pod == nil || pod.Status.Phase != corev1.PodPending {
+		return "", "", false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+			return axonv1alpha1.TaskFailureUnschedulable, fmt.Sprintf("Pod %s cannot be scheduled: %s", pod.Name, c.Message), true
+		}
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			w := s.State.Waiting
+			switch {
+			case w == nil:
+			case imagePullReasons[w.Reason]:
+				return axonv1alpha1.TaskFailureImagePullFailed, fmt.Sprintf("Cannot pull image %s of container %s: %s", s.Image, s.Name, w.Message), true
+			case w.Reason == "CreateContainerConfigError":
+				return axonv1alpha1.TaskFailureContainerConfigError, fmt.Sprintf("Cannot create container %s: %s", s.Name, w.Message), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// diagnoseFailure classifies why the Job of a Task failed from the state
+// of its Pod, and describes the failure.
+func diagnoseFailure(job *batchv1.Job, pod *corev1.Pod) (axonv1alpha1.TaskFailureReason, string) {
+	if reason, message, ok := stuckPod(pod); ok {
+		return reason, message
+	}
+	if name, ok := oomKilledContainer(pod); ok {
+		return axonv1alpha1.TaskFailureOOMKilled, fmt.Sprintf("Container %s was killed for exceeding its memory limit", name)
+	}
+	if pod != nil {
+		for _, s := range pod.Status.InitContainerStatuses {
+			t := s.State.Terminated
+			if t == nil || t.ExitCode == 0 {
+				continue
+			}
+			if s.Name == "git-clone" {
+				return gitCloneFailure(t)
+			}
+			return axonv1alpha1.TaskFailureUnknown, withOutput(fmt.Sprintf("Init container %s exited with code %d", s.Name, t.ExitCode), t.Message)
+		}
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue && c.Reason == batchv1.JobReasonDeadlineExceeded {
+			return axonv1alpha1.TaskFailureDeadlineExceeded, "Task exceeded its active deadline"
+		}
+	}
+	if pod != nil && pod.Status.Reason == "Evicted" {
+		return axonv1alpha1.TaskFailureEvicted, fmt.Sprintf("Pod %s was evicted: %s", pod.Name, pod.Status.Message)
+	}
+	if t := agentTermination(pod); t != nil && t.IsError {
+		message := "Agent reported an error"
+		if t.Subtype != "" {
+			message = fmt.Sprintf("%s (%s)", message, t.Subtype)
+		}
+		return axonv1alpha1.TaskFailureAgentError, withOutput(message, t.Result)
+	}
+	if pod != nil {
+		for _, s := range pod.Status.ContainerStatuses {
+			if s.Name == "claude-code" && s.State.Terminated != nil && s.State.Terminated.ExitCode != 0 {
+				return axonv1alpha1.TaskFailureAgentError, fmt.Sprintf("Agent exited with code %d", s.State.Terminated.ExitCode)
+			}
+		}
+	}
+	return axonv1alpha1.TaskFailureUnknown, "Task failed"
+}
+
+// gitCloneFailure classifies the failure of the git-clone init container
+// from the end of its output, which the kubelet puts in its termination
+// message.
+func gitCloneFailure(t *corev1.ContainerStateTerminated) (axonv1alpha1.TaskFailureReason, string) {
+	for _, e := range gitAuthErrors {
+		if strings.Contains(t.Message, e) {
+			return axonv1alpha1.TaskFailureGitAuthenticationFailed,
+				withOutput("git clone failed to authenticate, check the Workspace's secretRef", lastLine(t.Message))
+		}
+	}
+	return axonv1alpha1.TaskFailureGitCloneFailed,
+		withOutput(fmt.Sprintf("git clone exited with code %d", t.ExitCode), lastLine(t.Message))
+}
+
+// agentTermination returns what the axon-agent helper wrote to the
+// termination message of the agent container, if it has terminated.
+func agentTermination(pod *corev1.Pod) *agent.Termination {
+	if pod == nil {
+		return nil
+	}
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.Name != "claude-code" || s.State.Terminated == nil {
+			continue
+		}
+		var t agent.Termination
+		if err := json.Unmarshal([]byte(s.State.Terminated.Message), &t); err != nil {
+			return nil
+		}
+		return &t
+	}
+	return nil
+}
+
+// withOutput appends the output of a failed container to message.
+func withOutput(message, output string) string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return message
+	}
+	return message + ": " + output
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func TestStuckPod(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantReason axonv1alpha1.TaskFailureReason
+		wantStuck  bool
+	}{
+		{
+			name: "No Pod",
+		},
+		{
+			name: "Running",
+			pod:  &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		},
+		{
+			name: "Pulling image",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "claude-code",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+				}},
+			}},
+		},
+		{
+			name: "Unschedulable",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available: 3 Insufficient memory.",
+				}},
+			}},
+			wantReason: axonv1alpha1.TaskFailureUnschedulable,
+			wantStuck:  true,
+		},
+		{
+			name: "Init container image pull back-off",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "git-clone",
+					Image: "alpine/git:nope",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+				}},
+			}},
+			wantReason: axonv1alpha1.TaskFailureImagePullFailed,
+			wantStuck:  true,
+		},
+		{
+			name: "Missing Secret key",
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "claude-code",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CreateContainerConfigError"}},
+				}},
+			}},
+			wantReason: axonv1alpha1.TaskFailureContainerConfigError,
+			wantStuck:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, message, stuck := stuckPod(tt.pod)
+			if stuck != tt.wantStuck || reason != tt.wantReason {
+				t.Errorf("stuckPod = %q, %q, %v, want %q, %v", reason, message, stuck, tt.wantReason, tt.wantStuck)
+			}
+		})
+	}
+}
+
+func TestDiagnoseFailure(t *testing.T) {
+	terminated := func(name string, exitCode int32, reason, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name: name,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: exitCode,
+				Reason:   reason,
+				Message:  message,
+			}},
+		}
+	}
+	failedJob := &batchv1.Job{}
+
+	tests := []struct {
+		name        string
+		job         *batchv1.Job
+		pod         *corev1.Pod
+		wantReason  axonv1alpha1.TaskFailureReason
+		wantMessage string
+	}{
+		{
+			name:        "No Pod",
+			job:         failedJob,
+			wantReason:  axonv1alpha1.TaskFailureUnknown,
+			wantMessage: "Task failed",
+		},
+		{
+			name: "Git authentication failed",
+			job:  failedJob,
+			pod: &corev1.Pod{Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{
+				terminated("git-clone", 128, "Error", "Cloning into '/workspace/repo'...\n"+
+					"remote: Invalid username or password.\n"+
+					"fatal: Authentication failed for 'https://github.com/org/repo.git/'\n"),
+			}}},
+			wantReason:  axonv1alpha1.TaskFailureGitAuthenticationFailed,
+			wantMessage: "fatal: Authentication failed for 'https://github.com/org/repo.git/'",
+		},
+		{
+			name: "Git clone failed",
+			job:  failedJob,
+			pod: &corev1.Pod{Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{
+				terminated("git-clone", 128, "Error", "fatal: Remote branch nope not found in upstream origin\n"),
+			}}},
+			wantReason:  axonv1alpha1.TaskFailureGitCloneFailed,
+			wantMessage: "git clone exited with code 128: fatal: Remote branch nope not found",
+		},
+		{
+			name: "OOMKilled",
+			job:  failedJob,
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("claude-code", 137, "OOMKilled", ""),
+			}}},
+			wantReason:  axonv1alpha1.TaskFailureOOMKilled,
+			wantMessage: "Container claude-code was killed",
+		},
+		{
+			name: "Deadline exceeded",
+			job: &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+				Type:   batchv1.JobFailed,
+				Status: corev1.ConditionTrue,
+				Reason: batchv1.JobReasonDeadlineExceeded,
+			}}}},
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("claude-code", 137, "Error", ""),
+			}}},
+			wantReason:  axonv1alpha1.TaskFailureDeadlineExceeded,
+			wantMessage: "active deadline",
+		},
+		{
+			name: "Evicted",
+			job:  failedJob,
+			pod: &corev1.Pod{Status: corev1.PodStatus{
+				Reason:  "Evicted",
+				Message: "The node was low on resource: ephemeral-storage.",
+			}},
+			wantReason:  axonv1alpha1.TaskFailureEvicted,
+			wantMessage: "low on resource",
+		},
+		{
+			name: "Agent error result",
+			job:  failedJob,
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("claude-code", 1, "Error",
+					`{"costUSD":"1.5","numTurns":50,"isError":true,"subtype":"error_max_turns"}`),
+			}}},
+			wantReason:  axonv1alpha1.TaskFailureAgentError,
+			wantMessage: "Agent reported an error (error_max_turns)",
+		},
+		{
+			name: "Agent exit code",
+			job:  failedJob,
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("claude-code", 2, "Error", ""),
+			}}},
+			wantReason:  axonv1alpha1.TaskFailureAgentError,
+			wantMessage: "Agent exited with code 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, message := diagnoseFailure(tt.job, tt.pod)
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+			if !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDiagnoseFailureIgnoresSucceededInitContainers(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{
+		InitContainerStatuses: []corev1.ContainerStatus{{
+			Name: "git-clone",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				FinishedAt: metav1.Now(),
+			}},
+		}},
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "claude-code",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+		}},
+	}}
+
+	if reason, _ := diagnoseFailure(&batchv1.Job{}, pod); reason != axonv1alpha1.TaskFailureAgentError {
+		t.Errorf("reason = %q, want %q", reason, axonv1alpha1.TaskFailureAgentError)
+	}
+}
@@ -2,12 +2,17 @@ package controller
 
 import (
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
 )
 
 const (
@@ -26,21 +31,112 @@ const (
 	// WorkspaceMountPath is the mount path for the workspace volume.
 	WorkspaceMountPath = "/workspace"
 
+	// SessionVolumeName is the name of the volume holding the agent's
+	// session state.
+	SessionVolumeName = "session"
+
+	// SessionMountPath is the mount path for the session volume. It is the
+	// Claude Code configuration directory of the claude user.
+	SessionMountPath = "/home/claude/.claude"
+
+	// ArtifactVolumeName is the name of the volume artifacts are written to
+	// when they are stored on a PersistentVolumeClaim.
+	ArtifactVolumeName = "artifacts"
+
+	// ArtifactMountPath is the mount path for the artifact volume. Only the
+	// Task's own directory of the claim is mounted, below this path.
+	ArtifactMountPath = "/axon/artifacts"
+
+	// OutboxVolumeName is the name of the volume the agent's outputs are
+	// staged in for the uploader container.
+	OutboxVolumeName = "outbox"
+
+	// OutboxMountPath is the mount path for the outbox volume.
+	OutboxMountPath = "/axon/outbox"
+
+	// UploaderContainerName is the name of the sidecar container that
+	// uploads the agent's outputs with the credentials of the stores,
+	// which the agent's container never holds.
+	UploaderContainerName = "uploader"
+
+	// TranscriptVolumeName is the name of the volume transcripts are
+	// archived to when the archive is a PersistentVolumeClaim.
+	TranscriptVolumeName = "transcripts"
+
+	// TranscriptMountPath is the mount path for the transcript volume. Only
+	// the directory of the transcripts of the Task's name is mounted, below
+	// this path.
+	TranscriptMountPath = "/axon/transcripts"
+
+	// AskHumanToolName is the fully qualified name of the axon MCP server's
+	// ask_human tool as seen by Claude Code.
+	AskHumanToolName = "mcp__axon__ask_human"
+
 	// ClaudeCodeUID is the UID of the claude user in the claude-code
 	// container image (claude-code/Dockerfile). This must be kept in sync
 	// with the Dockerfile.
 	ClaudeCodeUID = int64(1100)
 )
 
+// axonMCPConfig configures Claude Code to start the axon MCP server bundled
+// in the claude-code image.
+const axonMCPConfig = `{"mcpServers":{"axon":{"command":"axon-agent","args":["mcp"]}}}`
+
+// defaultHumanInputToolTimeout bounds how long Claude Code waits for the
+// ask_human tool when the Task sets no input timeout.
+const defaultHumanInputToolTimeout = 24 * time.Hour
+
+// reservedEnvNames are the environment variables of the agent container
+// that the JobBuilder sets itself, along with every variable prefixed with
+// "AXON_".
+var reservedEnvNames = map[string]bool{
+	"ANTHROPIC_API_KEY":       true,
+	"CLAUDE_CODE_OAUTH_TOKEN": true,
+	"GITHUB_TOKEN":            true,
+	"GH_TOKEN":                true,
+	"MCP_TOOL_TIMEOUT":        true,
+}
+
+// ReservedEnvName reports whether the environment variable is set by the
+// JobBuilder, and thus cannot be overridden: an override could otherwise
+// hand credentials to an agent they are withheld from.
+func ReservedEnvName(name string) bool {
+	return reservedEnvNames[name] || strings.HasPrefix(name, "AXON_")
+}
+
+// AgentServiceAccountName returns the name of the service account, and of
+// its Role and RoleBinding, used by the agent Pods of a Task whose axon-agent
+// helper acts on the Task. Every Task has its own, so an agent can only act
+// on its own Task.
+func AgentServiceAccountName(taskName string) string {
+	return taskName + "-agent"
+}
+
+// usesAgentServiceAccount reports whether the axon-agent helper in the
+// agent Pod acts on the Task, and thus needs the agent service account.
+func usesAgentServiceAccount(spec *axonv1alpha1.TaskSpec) bool {
+	return spec.HumanInput != nil || spec.RequireApproval || spec.Artifacts != nil
+}
+
 // JobBuilder constructs Kubernetes Jobs for Tasks.
 type JobBuilder struct {
 	ClaudeCodeImage           string
 	ClaudeCodeImagePullPolicy corev1.PullPolicy
+
+	// ReportUsage runs every agent under the axon-agent helper, which
+	// reports the agent's usage, so the image must include
+	// /usr/local/bin/axon-agent like the default image does. Otherwise the
+	// image's entrypoint is only replaced for Tasks that need the helper
+	// to wrap the agent: those requiring approval, collecting artifacts or
+	// archiving their transcript. Tasks asking for human input keep the
+	// entrypoint, but start the helper as the agent's MCP server.
+	ReportUsage bool
 }
 
-// NewJobBuilder creates a new JobBuilder.
+// NewJobBuilder creates a new JobBuilder for the default image, which
+// includes the axon-agent helper.
 func NewJobBuilder() *JobBuilder {
-	return &JobBuilder{ClaudeCodeImage: ClaudeCodeImage}
+	return &JobBuilder{ClaudeCodeImage: ClaudeCodeImage, ReportUsage: true}
 }
 
 // Build creates a Job for the given Task.
@@ -66,6 +162,30 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 		args = append(args, "--model", task.Spec.Model)
 	}
 
+	if task.Spec.ContinueFrom != nil {
+		args = append(args, "--continue")
+	}
+
+	if task.Spec.HumanInput != nil {
+		args = append(args, "--mcp-config", axonMCPConfig)
+	}
+
+	if len(task.Spec.AllowedTools) > 0 {
+		allowedTools := task.Spec.AllowedTools
+		if task.Spec.HumanInput != nil {
+			allowedTools = append(allowedTools[:len(allowedTools):len(allowedTools)], AskHumanToolName)
+		}
+		args = append(args, "--allowedTools", strings.Join(allowedTools, ","))
+	}
+
+	if len(task.Spec.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(task.Spec.DisallowedTools, ","))
+	}
+
+	if task.Spec.Credentials == nil {
+		return nil, fmt.Errorf("credentials are required")
+	}
+
 	var envVars []corev1.EnvVar
 
 	switch task.Spec.Credentials.Type {
@@ -95,6 +215,14 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 		})
 	}
 
+	if task.Spec.RequireApproval && workspace == nil {
+		return nil, fmt.Errorf("requireApproval requires a workspace")
+	}
+
+	if task.Spec.Artifacts != nil && task.Spec.Artifacts.Store == nil {
+		return nil, fmt.Errorf("artifacts require a store")
+	}
+
 	var workspaceEnvVars []corev1.EnvVar
 	if workspace != nil && workspace.SecretRef != nil {
 		secretKeyRef := &corev1.SecretKeySelector{
@@ -111,10 +239,39 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 			Name:      "GH_TOKEN",
 			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretKeyRef},
 		}
-		envVars = append(envVars, githubTokenEnv, ghTokenEnv)
+		// Changes that need approval are pushed by a separate Job, so the
+		// agent itself must not be able to push
+		if !task.Spec.RequireApproval {
+			envVars = append(envVars, githubTokenEnv, ghTokenEnv)
+		}
 		workspaceEnvVars = append(workspaceEnvVars, githubTokenEnv, ghTokenEnv)
 	}
 
+	// The axon-agent helper acts on the Task from within the Pod
+	var serviceAccountName string
+	taskEnvVars := []corev1.EnvVar{
+		{Name: "AXON_TASK_NAME", Value: task.Name},
+		{
+			Name: "AXON_TASK_NAMESPACE",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+			},
+		},
+	}
+	if usesAgentServiceAccount(&task.Spec) {
+		envVars = append(envVars, taskEnvVars...)
+		serviceAccountName = AgentServiceAccountName(task.Name)
+	}
+
+	if hi := task.Spec.HumanInput; hi != nil {
+		toolTimeout := defaultHumanInputToolTimeout
+		if hi.Timeout != nil {
+			// Leave the controller time to apply the default answer
+			toolTimeout = hi.Timeout.Duration + time.Minute
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: "MCP_TOOL_TIMEOUT", Value: strconv.FormatInt(toolTimeout.Milliseconds(), 10)})
+	}
+
 	backoffLimit := int32(0)
 	claudeCodeUID := ClaudeCodeUID
 
@@ -126,6 +283,29 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 		Env:             envVars,
 	}
 
+	// The axon-agent helper wraps the agent to report its usage in the
+	// container's termination message, and to act on its output
+	archive := task.Status.TranscriptArchive
+	if b.ReportUsage || task.Spec.RequireApproval || task.Spec.Artifacts != nil || archive != nil {
+		command := []string{"axon-agent", "run", "--usage-file", corev1.TerminationMessagePathDefault}
+		if task.Spec.RequireApproval {
+			command = append(command, "--capture-diff")
+		}
+		if a := task.Spec.Artifacts; a != nil {
+			command = append(command, "--collect-artifacts")
+			for _, p := range a.Paths {
+				command = append(command, "--artifact-path", p)
+			}
+		}
+		if archive != nil {
+			command = append(command, "--archive-transcript")
+		}
+		if task.Spec.Artifacts != nil || archive != nil {
+			command = append(command, "--outbox", OutboxMountPath)
+		}
+		mainContainer.Command = append(command, "--", "claude")
+	}
+
 	var initContainers []corev1.Container
 	var volumes []corev1.Volume
 	var podSecurityContext *corev1.PodSecurityContext
@@ -163,6 +343,8 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 			SecurityContext: &corev1.SecurityContext{
 				RunAsUser: &claudeCodeUID,
 			},
+			// git's error output explains why a clone failed
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		}
 
 		if workspace.SecretRef != nil {
@@ -178,6 +360,119 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 		mainContainer.WorkingDir = WorkspaceMountPath + "/repo"
 	}
 
+	if claimName := task.Status.SessionClaimName; claimName != "" {
+		podSecurityContext = &corev1.PodSecurityContext{
+			FSGroup: &claudeCodeUID,
+		}
+
+		volumes = append(volumes, corev1.Volume{
+			Name: SessionVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+				},
+			},
+		})
+		mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      SessionVolumeName,
+			MountPath: SessionMountPath,
+		})
+	}
+
+	// mountStore configures the axon-agent helper in the container to use
+	// a store, mounting it into the container if it is a
+	// PersistentVolumeClaim. If subPath is set, only that directory of the
+	// claim is mounted, at the same path below mountPath, so the keys
+	// below it resolve as usual.
+	mountStore := func(c *corev1.Container, envPrefix string, store *axonv1alpha1.ArtifactStore, key, subPath, volumeName, mountPath string) error {
+		env, err := artifact.Env(envPrefix, store, key, mountPath)
+		if err != nil {
+			return err
+		}
+		c.Env = append(c.Env, env...)
+
+		if store.PersistentVolumeClaim != nil {
+			podSecurityContext = &corev1.PodSecurityContext{
+				FSGroup: &claudeCodeUID,
+			}
+			volumes = append(volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: store.PersistentVolumeClaim.ClaimName,
+					},
+				},
+			})
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: path.Join(mountPath, subPath),
+				SubPath:   subPath,
+			})
+		}
+		return nil
+	}
+
+	// The uploader runs as a sidecar next to the agent: it starts before
+	// and is stopped after it
+	var uploader *corev1.Container
+	if task.Spec.Artifacts != nil || archive != nil {
+		always := corev1.ContainerRestartPolicyAlways
+		uploader = &corev1.Container{
+			Name:            UploaderContainerName,
+			Image:           b.ClaudeCodeImage,
+			ImagePullPolicy: b.ClaudeCodeImagePullPolicy,
+			RestartPolicy:   &always,
+			Command:         []string{"axon-agent", "upload", "--outbox", OutboxMountPath},
+			VolumeMounts:    []corev1.VolumeMount{{Name: OutboxVolumeName, MountPath: OutboxMountPath}},
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: OutboxVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+		mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      OutboxVolumeName,
+			MountPath: OutboxMountPath,
+		})
+	}
+
+	if a := task.Spec.Artifacts; a != nil {
+		prefix := artifact.TaskPrefix(artifact.StorePrefix(a.Store), task.Namespace, task.Name, string(task.UID))
+		uploader.Command = append(uploader.Command, "--artifacts")
+		uploader.Env = append(uploader.Env, taskEnvVars...)
+		if err := mountStore(uploader, artifact.ArtifactsEnvPrefix, a.Store, prefix, prefix, ArtifactVolumeName, ArtifactMountPath); err != nil {
+			return nil, fmt.Errorf("artifact store: %w", err)
+		}
+	}
+
+	if archive != nil {
+		uploader.Command = append(uploader.Command, "--transcript")
+		if err := mountStore(uploader, artifact.TranscriptEnvPrefix, &archive.Store, archive.Key, path.Dir(archive.Key), TranscriptVolumeName, TranscriptMountPath); err != nil {
+			return nil, fmt.Errorf("transcript archive: %w", err)
+		}
+	}
+
+	if uploader != nil {
+		initContainers = append(initContainers, *uploader)
+	}
+
+	var nodeSelector map[string]string
+	var tolerations []corev1.Toleration
+	if o := task.Spec.PodOverrides; o != nil {
+		if o.Resources != nil {
+			mainContainer.Resources = *o.Resources
+		}
+		for _, e := range o.Env {
+			if ReservedEnvName(e.Name) {
+				return nil, fmt.Errorf("podOverrides.env: %s is set by Axon and cannot be overridden", e.Name)
+			}
+		}
+		mainContainer.Env = append(mainContainer.Env, o.Env...)
+		nodeSelector = o.NodeSelector
+		tolerations = o.Tolerations
+	}
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      task.Name,
@@ -190,7 +485,8 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 			},
 		},
 		Spec: batchv1.JobSpec{
-			BackoffLimit: &backoffLimit,
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: task.Spec.ActiveDeadlineSeconds,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
@@ -201,11 +497,14 @@ func (b *JobBuilder) buildClaudeCodeJob(task *axonv1alpha1.Task, workspace *axon
 					},
 				},
 				Spec: corev1.PodSpec{
-					RestartPolicy:   corev1.RestartPolicyNever,
-					SecurityContext: podSecurityContext,
-					InitContainers:  initContainers,
-					Volumes:         volumes,
-					Containers:      []corev1.Container{mainContainer},
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: serviceAccountName,
+					SecurityContext:    podSecurityContext,
+					InitContainers:     initContainers,
+					Volumes:            volumes,
+					Containers:         []corev1.Container{mainContainer},
+					NodeSelector:       nodeSelector,
+					Tolerations:        tolerations,
 				},
 			},
 		},
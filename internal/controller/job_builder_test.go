@@ -0,0 +1,462 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func newTestTask() *axonv1alpha1.Task {
+	return &axonv1alpha1.Task{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-task",
+			Namespace: "default",
+		},
+		Spec: axonv1alpha1.TaskSpec{
+			Type:   AgentTypeClaudeCode,
+			Prompt: "Fix the bug",
+			Credentials: &axonv1alpha1.Credentials{
+				Type:      axonv1alpha1.CredentialTypeAPIKey,
+				SecretRef: axonv1alpha1.SecretReference{Name: "anthropic-api-key"},
+			},
+		},
+	}
+}
+
+func containsArg(args []string, want ...string) bool {
+	for i := 0; i+len(want) <= len(args); i++ {
+		match := true
+		for j := range want {
+			if args[i+j] != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuildMissingCredentials(t *testing.T) {
+	task := newTestTask()
+	task.Spec.Credentials = nil
+
+	if _, err := NewJobBuilder().Build(task, nil); err == nil {
+		t.Fatal("expected error for missing credentials")
+	}
+}
+
+func TestBuildToolPolicyAndOverrides(t *testing.T) {
+	deadline := int64(600)
+	task := newTestTask()
+	task.Spec.AllowedTools = []string{"Read", "Edit"}
+	task.Spec.DisallowedTools = []string{"WebFetch"}
+	task.Spec.ActiveDeadlineSeconds = &deadline
+	task.Spec.PodOverrides = &axonv1alpha1.PodOverrides{
+		NodeSelector: map[string]string{"pool": "agents"},
+		Tolerations:  []corev1.Toleration{{Key: "agents", Operator: corev1.TolerationOpExists}},
+		Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy"}},
+	}
+
+	job, err := NewJobBuilder().Build(task, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	container := job.Spec.Template.Spec.Containers[0]
+	if !containsArg(container.Args, "--allowedTools", "Read,Edit") {
+		t.Errorf("expected --allowedTools Read,Edit in args: %v", container.Args)
+	}
+	if !containsArg(container.Args, "--disallowedTools", "WebFetch") {
+		t.Errorf("expected --disallowedTools WebFetch in args: %v", container.Args)
+	}
+	if job.Spec.ActiveDeadlineSeconds == nil || *job.Spec.ActiveDeadlineSeconds != 600 {
+		t.Errorf("ActiveDeadlineSeconds = %v, want 600", job.Spec.ActiveDeadlineSeconds)
+	}
+	if job.Spec.Template.Spec.NodeSelector["pool"] != "agents" {
+		t.Errorf("NodeSelector = %v, want pool=agents", job.Spec.Template.Spec.NodeSelector)
+	}
+	if len(job.Spec.Template.Spec.Tolerations) != 1 {
+		t.Errorf("Tolerations = %v, want 1 toleration", job.Spec.Template.Spec.Tolerations)
+	}
+	if last := container.Env[len(container.Env)-1]; last.Name != "HTTP_PROXY" {
+		t.Errorf("expected HTTP_PROXY env var, got %v", container.Env)
+	}
+
+	for _, name := range []string{"GITHUB_TOKEN", "ANTHROPIC_API_KEY", "AXON_TASK_NAME"} {
+		task.Spec.PodOverrides.Env = []corev1.EnvVar{{Name: name, Value: "overridden"}}
+		if _, err := NewJobBuilder().Build(task, nil); err == nil {
+			t.Errorf("expected an error for overriding %s", name)
+		}
+	}
+}
+
+func TestBuildSession(t *testing.T) {
+	t.Run("Persisted session", func(t *testing.T) {
+		task := newTestTask()
+		task.Spec.PersistSession = true
+		task.Status.SessionClaimName = "test-task-session"
+
+		job, err := NewJobBuilder().Build(task, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		podSpec := job.Spec.Template.Spec
+		if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].PersistentVolumeClaim == nil ||
+			podSpec.Volumes[0].PersistentVolumeClaim.ClaimName != "test-task-session" {
+			t.Fatalf("expected session volume, got %+v", podSpec.Volumes)
+		}
+		container := podSpec.Containers[0]
+		if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != SessionMountPath {
+			t.Errorf("expected session mount at %s, got %+v", SessionMountPath, container.VolumeMounts)
+		}
+		if podSpec.SecurityContext == nil || podSpec.SecurityContext.FSGroup == nil {
+			t.Errorf("expected FSGroup to be set")
+		}
+		if containsArg(container.Args, "--continue") {
+			t.Errorf("unexpected --continue in args: %v", container.Args)
+		}
+	})
+
+	t.Run("Continued session", func(t *testing.T) {
+		task := newTestTask()
+		task.Spec.ContinueFrom = &axonv1alpha1.TaskReference{Name: "previous-task"}
+		task.Status.SessionClaimName = "previous-task-session"
+
+		job, err := NewJobBuilder().Build(task, &axonv1alpha1.WorkspaceSpec{
+			Repo: "https://github.com/axon-core/axon.git",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		container := job.Spec.Template.Spec.Containers[0]
+		if !containsArg(container.Args, "--continue") {
+			t.Errorf("expected --continue in args: %v", container.Args)
+		}
+		if len(container.VolumeMounts) != 2 {
+			t.Errorf("expected workspace and session mounts, got %+v", container.VolumeMounts)
+		}
+		if len(job.Spec.Template.Spec.Volumes) != 2 {
+			t.Errorf("expected workspace and session volumes, got %+v", job.Spec.Template.Spec.Volumes)
+		}
+	})
+}
+
+func TestBuildHumanInput(t *testing.T) {
+	task := newTestTask()
+	task.Spec.AllowedTools = []string{"Read"}
+	task.Spec.HumanInput = &axonv1alpha1.HumanInputPolicy{
+		Timeout: &metav1.Duration{Duration: 30 * time.Minute},
+	}
+
+	job, err := NewJobBuilder().Build(task, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	if podSpec.ServiceAccountName != "test-task-agent" {
+		t.Errorf("ServiceAccountName = %q, want %q", podSpec.ServiceAccountName, "test-task-agent")
+	}
+
+	container := podSpec.Containers[0]
+	if !containsArg(container.Args, "--mcp-config", axonMCPConfig) {
+		t.Errorf("expected --mcp-config in args: %v", container.Args)
+	}
+	if !containsArg(container.Args, "--allowedTools", "Read,"+AskHumanToolName) {
+		t.Errorf("expected ask_human tool to be allowed: %v", container.Args)
+	}
+	if len(task.Spec.AllowedTools) != 1 {
+		t.Errorf("Build modified the Task's allowed tools: %v", task.Spec.AllowedTools)
+	}
+
+	env := map[string]corev1.EnvVar{}
+	for _, e := range container.Env {
+		env[e.Name] = e
+	}
+	if env["AXON_TASK_NAME"].Value != "test-task" {
+		t.Errorf("AXON_TASK_NAME = %q, want %q", env["AXON_TASK_NAME"].Value, "test-task")
+	}
+	if ns := env["AXON_TASK_NAMESPACE"]; ns.ValueFrom == nil || ns.ValueFrom.FieldRef == nil {
+		t.Errorf("expected AXON_TASK_NAMESPACE from the downward API, got %+v", ns)
+	}
+	if got := env["MCP_TOOL_TIMEOUT"].Value; got != "1860000" {
+		t.Errorf("MCP_TOOL_TIMEOUT = %q, want %q", got, "1860000")
+	}
+}
+
+func TestBuildWithoutHumanInput(t *testing.T) {
+	job, err := NewJobBuilder().Build(newTestTask(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sa := job.Spec.Template.Spec.ServiceAccountName; sa != "" {
+		t.Errorf("ServiceAccountName = %q, want empty", sa)
+	}
+	if containsArg(job.Spec.Template.Spec.Containers[0].Args, "--mcp-config") {
+		t.Errorf("unexpected --mcp-config in args")
+	}
+}
+
+func TestBuildReportsUsage(t *testing.T) {
+	job, err := NewJobBuilder().Build(newTestTask(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	command := job.Spec.Template.Spec.Containers[0].Command
+	if !containsArg(command, "axon-agent", "run", "--usage-file", corev1.TerminationMessagePathDefault, "--", "claude") {
+		t.Errorf("unexpected command: %v", command)
+	}
+}
+
+func TestBuildCustomImage(t *testing.T) {
+	b := &JobBuilder{ClaudeCodeImage: "example.com/my-claude:latest"}
+
+	job, err := b.Build(newTestTask(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if command := job.Spec.Template.Spec.Containers[0].Command; command != nil {
+		t.Errorf("expected the image's entrypoint, got the command %v", command)
+	}
+
+	// Tasks that need the helper are still run under it
+	task := newTestTask()
+	task.Spec.RequireApproval = true
+	job, err = b.Build(task, &axonv1alpha1.WorkspaceSpec{Repo: "https://github.com/axon-core/axon.git"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	command := job.Spec.Template.Spec.Containers[0].Command
+	if !containsArg(command, "axon-agent", "run", "--usage-file", corev1.TerminationMessagePathDefault, "--capture-diff", "--", "claude") {
+		t.Errorf("unexpected command: %v", command)
+	}
+}
+
+func TestBuildRequireApproval(t *testing.T) {
+	task := newTestTask()
+	task.Spec.RequireApproval = true
+	workspace := &axonv1alpha1.WorkspaceSpec{
+		Repo:      "https://github.com/axon-core/axon.git",
+		SecretRef: &axonv1alpha1.SecretReference{Name: "github-token"},
+	}
+
+	job, err := NewJobBuilder().Build(task, workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	container := podSpec.Containers[0]
+	for _, e := range container.Env {
+		if e.Name == "GITHUB_TOKEN" || e.Name == "GH_TOKEN" {
+			t.Errorf("agent container must not receive %s", e.Name)
+		}
+	}
+	var initHasToken bool
+	for _, e := range podSpec.InitContainers[0].Env {
+		if e.Name == "GITHUB_TOKEN" {
+			initHasToken = true
+		}
+	}
+	if !initHasToken {
+		t.Error("expected the clone init container to receive GITHUB_TOKEN")
+	}
+	if !containsArg(container.Command, "--capture-diff", "--", "claude") {
+		t.Errorf("unexpected command: %v", container.Command)
+	}
+	if podSpec.ServiceAccountName != "test-task-agent" {
+		t.Errorf("ServiceAccountName = %q, want %q", podSpec.ServiceAccountName, "test-task-agent")
+	}
+
+	t.Run("Without workspace", func(t *testing.T) {
+		if _, err := NewJobBuilder().Build(task, nil); err == nil {
+			t.Fatal("expected error for requireApproval without a workspace")
+		}
+	})
+}
+
+func TestBuildArtifacts(t *testing.T) {
+	t.Run("S3 store", func(t *testing.T) {
+		task := newTestTask()
+		task.UID = "uid-1"
+		task.Spec.Artifacts = &axonv1alpha1.ArtifactsSpec{
+			Paths: []string{"reports/*.md"},
+			Store: &axonv1alpha1.ArtifactStore{
+				S3: &axonv1alpha1.S3ArtifactStore{
+					Endpoint:  "http://minio.minio:9000",
+					Bucket:    "axon",
+					Prefix:    "tasks",
+					SecretRef: axonv1alpha1.SecretReference{Name: "minio-credentials"},
+				},
+			},
+		}
+
+		job, err := NewJobBuilder().Build(task, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		podSpec := job.Spec.Template.Spec
+		container := podSpec.Containers[0]
+		if !containsArg(container.Command, "--collect-artifacts", "--artifact-path", "reports/*.md", "--outbox", OutboxMountPath, "--", "claude") {
+			t.Errorf("unexpected command: %v", container.Command)
+		}
+		if podSpec.ServiceAccountName != "test-task-agent" {
+			t.Errorf("ServiceAccountName = %q, want %q", podSpec.ServiceAccountName, "test-task-agent")
+		}
+		for _, e := range container.Env {
+			if strings.HasPrefix(e.Name, "AXON_ARTIFACT_") {
+				t.Errorf("expected the agent container not to configure the store, got %s", e.Name)
+			}
+		}
+
+		if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != UploaderContainerName {
+			t.Fatalf("expected an uploader sidecar, got %+v", podSpec.InitContainers)
+		}
+		uploader := podSpec.InitContainers[0]
+		if uploader.RestartPolicy == nil || *uploader.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+			t.Error("expected the uploader to run as a sidecar")
+		}
+		if !containsArg(uploader.Command, "axon-agent", "upload", "--outbox", OutboxMountPath, "--artifacts") {
+			t.Errorf("unexpected uploader command: %v", uploader.Command)
+		}
+		env := map[string]corev1.EnvVar{}
+		for _, e := range uploader.Env {
+			env[e.Name] = e
+		}
+		if got := env["AXON_TASK_NAME"].Value; got != task.Name {
+			t.Errorf("AXON_TASK_NAME = %q, want %q", got, task.Name)
+		}
+		if got := env["AXON_ARTIFACT_KEY"].Value; got != "tasks/artifacts/default/test-task/uid-1" {
+			t.Errorf("AXON_ARTIFACT_KEY = %q, want %q", got, "tasks/artifacts/default/test-task/uid-1")
+		}
+		if got := env["AXON_ARTIFACT_S3_BUCKET"].Value; got != "axon" {
+			t.Errorf("AXON_ARTIFACT_S3_BUCKET = %q, want %q", got, "axon")
+		}
+		secret := env["AXON_ARTIFACT_S3_SECRET_ACCESS_KEY"].ValueFrom
+		if secret == nil || secret.SecretKeyRef == nil || secret.SecretKeyRef.Name != "minio-credentials" ||
+			secret.SecretKeyRef.Key != "AWS_SECRET_ACCESS_KEY" {
+			t.Errorf("expected the secret access key from the store's Secret, got %+v", secret)
+		}
+	})
+
+	t.Run("PersistentVolumeClaim store", func(t *testing.T) {
+		task := newTestTask()
+		task.UID = "uid-1"
+		task.Spec.Artifacts = &axonv1alpha1.ArtifactsSpec{
+			Store: &axonv1alpha1.ArtifactStore{
+				PersistentVolumeClaim: &axonv1alpha1.PVCArtifactStore{ClaimName: "artifacts"},
+			},
+		}
+
+		job, err := NewJobBuilder().Build(task, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		podSpec := job.Spec.Template.Spec
+		var claims []string
+		for _, v := range podSpec.Volumes {
+			if v.PersistentVolumeClaim != nil {
+				claims = append(claims, v.PersistentVolumeClaim.ClaimName)
+			}
+		}
+		if len(claims) != 1 || claims[0] != "artifacts" {
+			t.Fatalf("expected the artifact claim to be mounted, got %+v", podSpec.Volumes)
+		}
+		// Only the uploader mounts the claim, and only the Task's own
+		// directory of it
+		for _, m := range podSpec.Containers[0].VolumeMounts {
+			if m.Name == ArtifactVolumeName {
+				t.Errorf("expected the agent container not to mount the claim, got %+v", m)
+			}
+		}
+		prefix := "artifacts/default/test-task/uid-1"
+		mounts := podSpec.InitContainers[0].VolumeMounts
+		if len(mounts) != 2 || mounts[1].SubPath != prefix || mounts[1].MountPath != ArtifactMountPath+"/"+prefix {
+			t.Errorf("unexpected uploader volume mounts: %+v", mounts)
+		}
+		if podSpec.SecurityContext == nil || podSpec.SecurityContext.FSGroup == nil {
+			t.Error("expected FSGroup so the uploader can write to the claim")
+		}
+	})
+
+	t.Run("Without store", func(t *testing.T) {
+		task := newTestTask()
+		task.Spec.Artifacts = &axonv1alpha1.ArtifactsSpec{Paths: []string{"report.md"}}
+		if _, err := NewJobBuilder().Build(task, nil); err == nil {
+			t.Fatal("expected error for artifacts without a store")
+		}
+	})
+}
+
+func TestBuildTranscriptArchive(t *testing.T) {
+	task := newTestTask()
+	task.Status.TranscriptArchive = &axonv1alpha1.TranscriptArchive{
+		Store: axonv1alpha1.ArtifactStore{
+			PersistentVolumeClaim: &axonv1alpha1.PVCArtifactStore{ClaimName: "transcripts"},
+		},
+		Key: "transcripts/default/test-task/uid-1.jsonl.gz",
+	}
+
+	job, err := NewJobBuilder().Build(task, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	container := podSpec.Containers[0]
+	if !containsArg(container.Command, "--archive-transcript", "--outbox", OutboxMountPath, "--", "claude") {
+		t.Errorf("unexpected command: %v", container.Command)
+	}
+	// Archiving does not act on the Task, so no service account is needed
+	if podSpec.ServiceAccountName != "" {
+		t.Errorf("ServiceAccountName = %q, want none", podSpec.ServiceAccountName)
+	}
+	for _, e := range container.Env {
+		if strings.HasPrefix(e.Name, "AXON_TRANSCRIPT_") {
+			t.Errorf("expected the agent container not to configure the archive, got %s", e.Name)
+		}
+	}
+	for _, m := range container.VolumeMounts {
+		if m.Name == TranscriptVolumeName {
+			t.Errorf("expected the agent container not to mount the claim, got %+v", m)
+		}
+	}
+
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != UploaderContainerName {
+		t.Fatalf("expected an uploader sidecar, got %+v", podSpec.InitContainers)
+	}
+	uploader := podSpec.InitContainers[0]
+	if !containsArg(uploader.Command, "axon-agent", "upload", "--outbox", OutboxMountPath, "--transcript") {
+		t.Errorf("unexpected uploader command: %v", uploader.Command)
+	}
+	env := map[string]string{}
+	for _, e := range uploader.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["AXON_TRANSCRIPT_KEY"]; got != task.Status.TranscriptArchive.Key {
+		t.Errorf("AXON_TRANSCRIPT_KEY = %q, want %q", got, task.Status.TranscriptArchive.Key)
+	}
+	if got := env["AXON_TRANSCRIPT_DIR"]; got != TranscriptMountPath {
+		t.Errorf("AXON_TRANSCRIPT_DIR = %q, want %q", got, TranscriptMountPath)
+	}
+	// Only the directory of the Task's transcripts is mounted
+	dir := "transcripts/default/test-task"
+	mounts := uploader.VolumeMounts
+	if len(mounts) != 2 || mounts[1].Name != TranscriptVolumeName || mounts[1].SubPath != dir || mounts[1].MountPath != TranscriptMountPath+"/"+dir {
+		t.Errorf("unexpected uploader volume mounts: %+v", mounts)
+	}
+}
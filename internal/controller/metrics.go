@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+var (
+	tasksDesc = prometheus.NewDesc(
+		"axon_tasks",
+		"Number of Tasks by phase and agent type.",
+		[]string{"phase", "type"}, nil,
+	)
+
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "axon_task_duration_seconds",
+		Help:    "Time from a Task starting to run until it finished.",
+		Buckets: []float64{30, 60, 120, 300, 600, 1200, 1800, 3600, 7200, 14400},
+	}, []string{"phase", "type"})
+
+	agentCost = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "axon_agent_cost_usd",
+		Help:    "Cost of a finished Task's agent in US dollars, as reported in its result event.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50},
+	}, []string{"phase", "type"})
+
+	agentTurns = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "axon_agent_turns",
+		Help:    "Number of turns a finished Task's agent took, as reported in its result event.",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100, 200, 500},
+	}, []string{"phase", "type"})
+
+	cloneDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "axon_clone_duration_seconds",
+		Help:    "Time taken to clone a Task's workspace repository.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(taskDuration, agentCost, agentTurns, cloneDuration)
+}
+
+// taskCollector counts Tasks by phase and type when metrics are scraped.
+type taskCollector struct {
+	client client.Reader
+}
+
+// Describe implements prometheus.Collector.
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tasksDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	var tasks axonv1alpha1.TaskList
+	if err := c.client.List(context.Background(), &tasks); err != nil {
+		ch <- prometheus.NewInvalidMetric(tasksDesc, err)
+		return
+	}
+
+	type key struct{ phase, typ string }
+	counts := make(map[key]int)
+	for _, t := range tasks.Items {
+		phase := string(t.Status.Phase)
+		if phase == "" {
+			phase = string(axonv1alpha1.TaskPhasePending)
+		}
+		counts[key{phase, t.Spec.Type}]++
+	}
+	for k, n := range counts {
+		ch <- prometheus.MustNewConstMetric(tasksDesc, prometheus.GaugeValue, float64(n), k.phase, k.typ)
+	}
+}
+
+// observeFinishedTask records the metrics of a Task that has just finished.
+func observeFinishedTask(task *axonv1alpha1.Task, pod *corev1.Pod) {
+	phase := string(task.Status.Phase)
+	if start, end := task.Status.StartTime, task.Status.CompletionTime; start != nil && end != nil {
+		taskDuration.WithLabelValues(phase, task.Spec.Type).Observe(end.Sub(start.Time).Seconds())
+	}
+	if u := task.Status.Usage; u != nil {
+		if cost, err := strconv.ParseFloat(u.CostUSD, 64); err == nil {
+			agentCost.WithLabelValues(phase, task.Spec.Type).Observe(cost)
+		}
+		agentTurns.WithLabelValues(phase, task.Spec.Type).Observe(float64(u.NumTurns))
+	}
+	if d, ok := cloneDurationOf(pod); ok {
+		cloneDuration.Observe(d.Seconds())
+	}
+}
+
+// usageOf returns the usage the axon-agent helper wrote to the termination
+// message of the agent container, if any.
+func usageOf(pod *corev1.Pod) *axonv1alpha1.TaskUsage {
+	t := agentTermination(pod)
+	if t == nil {
+		return nil
+	}
+	return &t.TaskUsage
+}
+
+// cloneDurationOf returns how long the git-clone init container ran.
+func cloneDurationOf(pod *corev1.Pod) (time.Duration, bool) {
+	if pod == nil {
+		return 0, false
+	}
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name != "git-clone" || s.State.Terminated == nil || s.State.Terminated.ExitCode != 0 {
+			continue
+		}
+		t := s.State.Terminated
+		if t.StartedAt.IsZero() || t.FinishedAt.IsZero() {
+			return 0, false
+		}
+		return t.FinishedAt.Sub(t.StartedAt.Time), true
+	}
+	return 0, false
+}
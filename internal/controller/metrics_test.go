@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUsageOf(t *testing.T) {
+	terminated := func(name, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:  name,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		wantCost string
+		wantNil  bool
+	}{
+		{
+			name:    "No Pod",
+			wantNil: true,
+		},
+		{
+			name: "Usage in termination message",
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("claude-code", `{"costUSD":"0.42","numTurns":7}`),
+			}}},
+			wantCost: "0.42",
+		},
+		{
+			name: "No result event",
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				terminated("claude-code", ""),
+			}}},
+			wantNil: true,
+		},
+		{
+			name: "Still running",
+			pod: &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "claude-code"},
+			}}},
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := usageOf(tt.pod)
+			if tt.wantNil {
+				if usage != nil {
+					t.Errorf("expected no usage, got %+v", usage)
+				}
+				return
+			}
+			if usage == nil {
+				t.Fatal("expected usage")
+			}
+			if usage.CostUSD != tt.wantCost || usage.NumTurns != 7 {
+				t.Errorf("usage = %+v", usage)
+			}
+		})
+	}
+}
+
+func TestCloneDurationOf(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{
+		Name: "git-clone",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			StartedAt:  metav1.NewTime(start),
+			FinishedAt: metav1.NewTime(start.Add(12 * time.Second)),
+		}},
+	}}}}
+
+	d, ok := cloneDurationOf(pod)
+	if !ok || d != 12*time.Second {
+		t.Errorf("cloneDurationOf = %v, %v, want 12s, true", d, ok)
+	}
+
+	pod.Status.InitContainerStatuses[0].State.Terminated.ExitCode = 128
+	if _, ok := cloneDurationOf(pod); ok {
+		t.Error("expected no duration for a failed clone")
+	}
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+const (
+	// DiffVolumeName is the name of the volume holding the approved diff.
+	DiffVolumeName = "diff"
+
+	// DiffMountPath is the mount path for the diff volume.
+	DiffMountPath = "/axon/diff"
+
+	// maxPRTitleLength limits the length of the pull request title derived
+	// from the Task's prompt.
+	maxPRTitleLength = 72
+)
+
+// pushScript checks the approved diff against its digest, clones the
+// Workspace, applies the diff on a new branch, pushes it, and opens a pull
+// request.
+const pushScript = `set -euo pipefail
+diff_file="$(mktemp)"
+cp "$DIFF_FILE" "$diff_file"
+if ! echo "$DIFF_SHA256  $diff_file" | sha256sum --check --status; then
+  echo "the diff does not match the approved one" >&2
+  exit 1
+fi
+git config --global credential.helper '!f() { echo "username=x-access-token"; echo "password=$GITHUB_TOKEN"; }; f'
+git config --global user.name "axon"
+git config --global user.email "axon@axon.io"
+if [ -n "$BASE_REF" ]; then
+  git clone --branch "$BASE_REF" -- "$REPO" repo
+else
+  git clone -- "$REPO" repo
+fi
+cd repo
+git checkout -b "$BRANCH"
+git apply --3way "$diff_file"
+git commit -m "$TITLE" -m "$BODY"
+git push origin "$BRANCH"
+gh pr create --head "$BRANCH" --title "$TITLE" --body "$BODY" ${BASE_REF:+--base "$BASE_REF"}
+`
+
+// ApprovalBranch returns the branch the approved changes of a Task are
+// pushed to.
+func ApprovalBranch(task *axonv1alpha1.Task) string {
+	return "axon/" + task.Name
+}
+
+// pushJobName returns the name of the Job pushing the approved changes of
+// a Task.
+func pushJobName(task *axonv1alpha1.Task) string {
+	return task.Name + "-push"
+}
+
+// BuildPush creates a Job that pushes the approved changes of the given
+// Task to a new branch of the Workspace's repository and opens a pull
+// request for them.
+func (b *JobBuilder) BuildPush(task *axonv1alpha1.Task, workspace *axonv1alpha1.WorkspaceSpec) (*batchv1.Job, error) {
+	if workspace == nil || workspace.SecretRef == nil {
+		return nil, fmt.Errorf("pushing approved changes requires a workspace with a secretRef")
+	}
+	if task.Spec.ApprovedDiffSHA256 == "" {
+		return nil, fmt.Errorf("pushing approved changes requires the digest of the approved diff")
+	}
+
+	secretKeyRef := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: workspace.SecretRef.Name,
+		},
+		Key: "GITHUB_TOKEN",
+	}
+
+	title, body := pullRequestText(task)
+	env := []corev1.EnvVar{
+		{Name: "GITHUB_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretKeyRef}},
+		{Name: "GH_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretKeyRef}},
+		{Name: "REPO", Value: workspace.Repo},
+		{Name: "BASE_REF", Value: workspace.Ref},
+		{Name: "BRANCH", Value: ApprovalBranch(task)},
+		{Name: "DIFF_FILE", Value: DiffMountPath + "/" + agent.DiffKey},
+		{Name: "DIFF_SHA256", Value: task.Spec.ApprovedDiffSHA256},
+		{Name: "TITLE", Value: title},
+		{Name: "BODY", Value: body},
+	}
+
+	backoffLimit := int32(0)
+	claudeCodeUID := ClaudeCodeUID
+
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "axon",
+		"app.kubernetes.io/component":  "push",
+		"app.kubernetes.io/managed-by": "axon-controller",
+		"axon.io/task":                 task.Name,
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pushJobName(task),
+			Namespace: task.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					SecurityContext: &corev1.PodSecurityContext{
+						FSGroup: &claudeCodeUID,
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: WorkspaceVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						{
+							Name: DiffVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: agent.ApprovalConfigMapName(task.Name),
+									},
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            "push",
+							Image:           b.ClaudeCodeImage,
+							ImagePullPolicy: b.ClaudeCodeImagePullPolicy,
+							Command:         []string{"bash", "-c", pushScript},
+							Env:             env,
+							WorkingDir:      WorkspaceMountPath,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: WorkspaceVolumeName, MountPath: WorkspaceMountPath},
+								{Name: DiffVolumeName, MountPath: DiffMountPath, ReadOnly: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return job, nil
+}
+
+// pullRequestText returns the title and body of the commit and pull request
+// for the approved changes of a Task.
+func pullRequestText(task *axonv1alpha1.Task) (string, string) {
+	title := strings.TrimSpace(task.Spec.Prompt)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if r := []rune(title); len(r) > maxPRTitleLength {
+		title = strings.TrimSpace(string(r[:maxPRTitleLength-3])) + "..."
+	}
+	if title == "" {
+		title = fmt.Sprintf("Changes from Axon Task %s", task.Name)
+	}
+
+	body := fmt.Sprintf("Changes made by Axon Task `%s` and approved for push.\n\nPrompt:\n\n%s", task.Name, task.Spec.Prompt)
+	return title, body
+}
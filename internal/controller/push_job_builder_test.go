@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func TestBuildPush(t *testing.T) {
+	task := newTestTask()
+	task.Spec.RequireApproval = true
+	task.Spec.ApprovedDiffSHA256 = "0a1b2c"
+	workspace := &axonv1alpha1.WorkspaceSpec{
+		Repo:      "https://github.com/axon-core/axon.git",
+		Ref:       "main",
+		SecretRef: &axonv1alpha1.SecretReference{Name: "github-token"},
+	}
+
+	job, err := NewJobBuilder().BuildPush(task, workspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if job.Name != "test-task-push" {
+		t.Errorf("Name = %q, want %q", job.Name, "test-task-push")
+	}
+
+	env := map[string]string{}
+	for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+			env[e.Name] = "secret:" + e.ValueFrom.SecretKeyRef.Name
+			continue
+		}
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"GITHUB_TOKEN": "secret:github-token",
+		"GH_TOKEN":     "secret:github-token",
+		"REPO":         "https://github.com/axon-core/axon.git",
+		"BASE_REF":     "main",
+		"BRANCH":       "axon/test-task",
+		"DIFF_FILE":    "/axon/diff/diff.patch",
+		"DIFF_SHA256":  "0a1b2c",
+		"TITLE":        "Fix the bug",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("env %s = %q, want %q", k, env[k], v)
+		}
+	}
+
+	volumes := job.Spec.Template.Spec.Volumes
+	if len(volumes) != 2 || volumes[1].ConfigMap == nil || volumes[1].ConfigMap.Name != "test-task-approval" {
+		t.Errorf("expected diff ConfigMap volume, got %+v", volumes)
+	}
+
+	t.Run("Without secretRef", func(t *testing.T) {
+		if _, err := NewJobBuilder().BuildPush(task, &axonv1alpha1.WorkspaceSpec{Repo: workspace.Repo}); err == nil {
+			t.Fatal("expected error for a workspace without secretRef")
+		}
+	})
+
+	t.Run("Without digest", func(t *testing.T) {
+		task := task.DeepCopy()
+		task.Spec.ApprovedDiffSHA256 = ""
+		if _, err := NewJobBuilder().BuildPush(task, workspace); err == nil {
+			t.Fatal("expected error for a Task without the digest of its approved diff")
+		}
+	})
+}
+
+func TestPullRequestText(t *testing.T) {
+	tests := []struct {
+		name      string
+		prompt    string
+		wantTitle string
+	}{
+		{name: "Single line", prompt: "Fix the login bug", wantTitle: "Fix the login bug"},
+		{name: "First line only", prompt: "Fix the login bug\n\nIt fails on Safari.", wantTitle: "Fix the login bug"},
+		{name: "Long prompt", prompt: strings.Repeat("a", 100), wantTitle: strings.Repeat("a", 69) + "..."},
+		{name: "Empty prompt", prompt: "  ", wantTitle: "Changes from Axon Task test-task"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTestTask()
+			task.Spec.Prompt = tt.prompt
+
+			title, body := pullRequestText(task)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if !strings.Contains(body, "test-task") {
+				t.Errorf("body does not mention the Task: %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+// ensureServiceAccountRBAC ensures a ServiceAccount exists in the namespace
+// and is bound to the given ClusterRole by a RoleBinding of the same name.
+func ensureServiceAccountRBAC(ctx context.Context, c client.Client, namespace, serviceAccount, clusterRole string) error {
+	logger := log.FromContext(ctx)
+
+	// Ensure ServiceAccount
+	var sa corev1.ServiceAccount
+	if err := c.Get(ctx, types.NamespacedName{Name: serviceAccount, Namespace: namespace}, &sa); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		sa = corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      serviceAccount,
+				Namespace: namespace,
+			},
+		}
+		if err := c.Create(ctx, &sa); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		} else {
+			logger.Info("created ServiceAccount", "namespace", namespace, "name", serviceAccount)
+		}
+	}
+
+	// Ensure RoleBinding
+	rbName := serviceAccount
+	var rb rbacv1.RoleBinding
+	if err := c.Get(ctx, types.NamespacedName{Name: rbName, Namespace: namespace}, &rb); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		rb = rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      rbName,
+				Namespace: namespace,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     clusterRole,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      "ServiceAccount",
+					Name:      serviceAccount,
+					Namespace: namespace,
+				},
+			},
+		}
+		if err := c.Create(ctx, &rb); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		} else {
+			logger.Info("created RoleBinding", "namespace", namespace, "name", rbName)
+		}
+	}
+
+	return nil
+}
+
+// agentRules returns the rules of the Role of a Task's agent: it can read
+// its own Task, update its status, and record its diff in the ConfigMap the
+// controller created for it. It cannot create ConfigMaps, since
+// resourceNames cannot restrict create.
+func agentRules(task *axonv1alpha1.Task) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{axonv1alpha1.GroupVersion.Group},
+			Resources:     []string{"tasks"},
+			ResourceNames: []string{task.Name},
+			Verbs:         []string{"get"},
+		},
+		{
+			APIGroups:     []string{axonv1alpha1.GroupVersion.Group},
+			Resources:     []string{"tasks/status"},
+			ResourceNames: []string{task.Name},
+			Verbs:         []string{"get", "update"},
+		},
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			ResourceNames: []string{agent.DiffConfigMapName(task.Name)},
+			Verbs:         []string{"get", "update"},
+		},
+	}
+}
+
+// ensureAgentRBAC ensures the Task has its own agent ServiceAccount, bound
+// to a Role that only grants access to the Task and its diff ConfigMap. They
+// are owned by the Task and deleted with it.
+func ensureAgentRBAC(ctx context.Context, c client.Client, scheme *runtime.Scheme, task *axonv1alpha1.Task) error {
+	name := AgentServiceAccountName(task.Name)
+	meta := func() metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: task.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "axon",
+				"app.kubernetes.io/component":  "agent",
+				"app.kubernetes.io/managed-by": "axon-controller",
+				"axon.io/task":                 task.Name,
+			},
+		}
+	}
+
+	objects := []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: meta()},
+		&rbacv1.Role{ObjectMeta: meta(), Rules: agentRules(task)},
+		&rbacv1.RoleBinding{
+			ObjectMeta: meta(),
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     name,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      "ServiceAccount",
+					Name:      name,
+					Namespace: task.Namespace,
+				},
+			},
+		},
+	}
+	created := false
+	for _, obj := range objects {
+		if err := controllerutil.SetControllerReference(task, obj, scheme); err != nil {
+			return err
+		}
+		ok, err := createIfNotFound(ctx, c, obj)
+		if err != nil {
+			return err
+		}
+		created = created || ok
+	}
+	if created {
+		log.FromContext(ctx).Info("created agent ServiceAccount, Role and RoleBinding", "namespace", task.Namespace, "name", name)
+	}
+	return nil
+}
+
+// revokeAgentRBAC deletes the Role and RoleBinding of a Task's agent once
+// its Job finished, so nothing holding the agent's token can change the
+// Task or its diff afterwards. ensureAgentRBAC recreates them for a new Job.
+func revokeAgentRBAC(ctx context.Context, c client.Client, task *axonv1alpha1.Task) error {
+	name := AgentServiceAccountName(task.Name)
+	objects := []client.Object{&rbacv1.RoleBinding{}, &rbacv1.Role{}}
+	revoked := false
+	for _, obj := range objects {
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: task.Namespace}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		revoked = true
+	}
+	if revoked {
+		log.FromContext(ctx).Info("deleted agent Role and RoleBinding", "namespace", task.Namespace, "name", name)
+	}
+	return nil
+}
+
+// createIfNotFound creates the object unless it already exists, and
+// reports whether it created it.
+func createIfNotFound(ctx context.Context, c client.Client, obj client.Object) (bool, error) {
+	existing := obj.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err == nil || !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if err := c.Create(ctx, obj); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
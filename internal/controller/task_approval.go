@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+// diffGracePeriod is how long after the agent's Job completes a missing
+// diff ConfigMap is attributed to cache lag rather than to the agent.
+const diffGracePeriod = 30 * time.Second
+
+// reconcileApproval drives a Task with RequireApproval whose agent Job has
+// succeeded: it holds the Task in PendingApproval until it is approved and
+// then pushes the agent's changes with a separate Job.
+func (r *TaskReconciler) reconcileApproval(ctx context.Context, task *axonv1alpha1.Task, job *batchv1.Job) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if isTaskFinished(task) {
+		return ctrl.Result{}, nil
+	}
+
+	snapshot, err := r.diffSnapshot(ctx, task)
+	if err != nil {
+		logger.Error(err, "Unable to snapshot diff")
+		return ctrl.Result{}, err
+	}
+	if snapshot == nil {
+		var cm corev1.ConfigMap
+		if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: agent.DiffConfigMapName(task.Name)}, &cm); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Unable to fetch diff ConfigMap")
+			return ctrl.Result{}, err
+		}
+		if cm.Data[agent.BaseCommitKey] == "" {
+			if job.Status.CompletionTime != nil && time.Since(job.Status.CompletionTime.Time) < diffGracePeriod {
+				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+			return r.finishApproval(ctx, task, axonv1alpha1.TaskFailureUnknown, "ChangesNotRecorded", "The agent did not record its changes")
+		}
+		if len(cm.BinaryData[agent.DiffKey]) == 0 {
+			return r.finishApproval(ctx, task, "", "NoChanges", "The agent made no changes")
+		}
+		if snapshot, err = r.createDiffSnapshot(ctx, task, &cm); err != nil {
+			logger.Error(err, "Unable to snapshot diff")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Only the diff whose digest was approved is pushed, so neither the agent
+	// nor anyone else can swap it after it was reviewed
+	message := ""
+	switch digest := agent.DiffSHA256(snapshot); {
+	case !task.Spec.Approved:
+		message = fmt.Sprintf("Waiting for approval: review the changes with 'axon diff %s' and push them with 'axon approve %s'", task.Name, task.Name)
+	case task.Spec.ApprovedDiffSHA256 != digest:
+		message = fmt.Sprintf("Waiting for approval: the approved digest %s does not match the changes %s, review them again with 'axon diff %s'",
+			task.Spec.ApprovedDiffSHA256, digest, task.Name)
+	}
+	if message != "" {
+		if task.Status.Phase != axonv1alpha1.TaskPhasePendingApproval || task.Status.DiffConfigMapName != snapshot.Name || task.Status.Message != message {
+			task.SetCondition(axonv1alpha1.TaskConditionAwaitingApproval, metav1.ConditionTrue, "ChangesRecorded", message)
+			task.Status.Message = message
+			task.Status.DiffConfigMapName = snapshot.Name
+			task.Status.PodName = ""
+			if err := r.Status().Update(ctx, task); err != nil {
+				logger.Error(err, "Unable to update Task status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var pushJob batchv1.Job
+	if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: pushJobName(task)}, &pushJob); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "Unable to fetch push Job")
+			return ctrl.Result{}, err
+		}
+		return r.createPushJob(ctx, task)
+	}
+
+	switch {
+	case pushJob.Status.Succeeded > 0:
+		return r.finishApproval(ctx, task, "", "ChangesPushed",
+			fmt.Sprintf("Approved changes pushed to branch %s", ApprovalBranch(task)))
+	case pushJob.Status.Failed > 0:
+		return r.finishApproval(ctx, task, axonv1alpha1.TaskFailurePushFailed, "PushFailed", "Failed to push approved changes")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resetDiff ensures the ConfigMap the agent records its diff in exists and
+// is empty before the agent starts. It is created by the controller, since
+// the agent may not create ConfigMaps, and a snapshot of a previous run is
+// deleted.
+func (r *TaskReconciler) resetDiff(ctx context.Context, task *axonv1alpha1.Task) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agent.DiffConfigMapName(task.Name),
+			Namespace: task.Namespace,
+			Labels:    diffLabels(task, "diff"),
+		},
+	}
+	if err := controllerutil.SetControllerReference(task, cm, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, cm); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		// Left behind by a previous Job of the Task, e.g. before it was
+		// suspended
+		var existing corev1.ConfigMap
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cm), &existing); err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(&existing, task) {
+			return fmt.Errorf("ConfigMap %s is not owned by Task %s", cm.Name, task.Name)
+		}
+		existing.Data = nil
+		existing.BinaryData = nil
+		if err := r.Update(ctx, &existing); err != nil {
+			return err
+		}
+	}
+
+	snapshot := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agent.ApprovalConfigMapName(task.Name),
+			Namespace: task.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, snapshot); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// diffSnapshot returns the snapshot of the Task's diff taken for approval,
+// or nil if there is none yet.
+func (r *TaskReconciler) diffSnapshot(ctx context.Context, task *axonv1alpha1.Task) (*corev1.ConfigMap, error) {
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: agent.ApprovalConfigMapName(task.Name)}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !metav1.IsControlledBy(&cm, task) {
+		return nil, fmt.Errorf("ConfigMap %s is not owned by Task %s", cm.Name, task.Name)
+	}
+	return &cm, nil
+}
+
+// createDiffSnapshot copies the diff the agent recorded into an immutable
+// ConfigMap that is reviewed and pushed, so it no longer depends on the
+// ConfigMap the agent could write.
+func (r *TaskReconciler) createDiffSnapshot(ctx context.Context, task *axonv1alpha1.Task, diff *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	immutable := true
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agent.ApprovalConfigMapName(task.Name),
+			Namespace: task.Namespace,
+			Labels:    diffLabels(task, "approval"),
+		},
+		Immutable:  &immutable,
+		Data:       diff.Data,
+		BinaryData: diff.BinaryData,
+	}
+	if err := controllerutil.SetControllerReference(task, cm, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, cm); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// Created by an earlier reconcile the cache has not seen yet
+			var existing corev1.ConfigMap
+			if err := r.Get(ctx, client.ObjectKeyFromObject(cm), &existing); err != nil {
+				return nil, err
+			}
+			if !metav1.IsControlledBy(&existing, task) {
+				return nil, fmt.Errorf("ConfigMap %s is not owned by Task %s", cm.Name, task.Name)
+			}
+			return &existing, nil
+		}
+		return nil, err
+	}
+	log.FromContext(ctx).Info("Created diff snapshot", "configMap", cm.Name)
+	return cm, nil
+}
+
+// diffLabels returns the labels of the ConfigMaps holding a Task's diff.
+func diffLabels(task *axonv1alpha1.Task, component string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "axon",
+		"app.kubernetes.io/component":  component,
+		"app.kubernetes.io/managed-by": "axon-controller",
+		"axon.io/task":                 task.Name,
+	}
+}
+
+// createPushJob creates the Job that pushes the approved changes of a Task.
+func (r *TaskReconciler) createPushJob(ctx context.Context, task *axonv1alpha1.Task) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var workspace *axonv1alpha1.WorkspaceSpec
+	if task.Spec.WorkspaceRef != nil {
+		var ws axonv1alpha1.Workspace
+		if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: task.Spec.WorkspaceRef.Name}, &ws); err != nil {
+			if apierrors.IsNotFound(err) {
+				return r.finishApproval(ctx, task, axonv1alpha1.TaskFailureConfigurationError, reasonWorkspaceNotFound,
+					fmt.Sprintf("Workspace %q not found", task.Spec.WorkspaceRef.Name))
+			}
+			logger.Error(err, "Unable to fetch Workspace", "workspace", task.Spec.WorkspaceRef.Name)
+			return ctrl.Result{}, err
+		}
+		workspace = &ws.Spec
+	}
+
+	job, err := r.JobBuilder.BuildPush(task, workspace)
+	if err != nil {
+		return r.finishApproval(ctx, task, axonv1alpha1.TaskFailureConfigurationError, reasonJobBuildFailed, fmt.Sprintf("Failed to build push Job: %v", err))
+	}
+	if err := controllerutil.SetControllerReference(task, job, r.Scheme); err != nil {
+		logger.Error(err, "Unable to set owner reference")
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, job); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		logger.Error(err, "Unable to create push Job")
+		return ctrl.Result{}, err
+	}
+	logger.Info("Created push Job", "job", job.Name)
+
+	task.Status.PushJobName = job.Name
+	task.Status.Message = fmt.Sprintf("Pushing approved changes to branch %s", ApprovalBranch(task))
+	task.SetCondition(axonv1alpha1.TaskConditionAwaitingApproval, metav1.ConditionFalse, "Approved", task.Status.Message)
+	if err := r.Status().Update(ctx, task); err != nil {
+		logger.Error(err, "Unable to update Task status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// finishApproval moves a Task with RequireApproval to a terminal phase. The
+// Task succeeds if failure is empty.
+func (r *TaskReconciler) finishApproval(ctx context.Context, task *axonv1alpha1.Task, failure axonv1alpha1.TaskFailureReason, reason, message string) (ctrl.Result, error) {
+	now := metav1.Now()
+	status := metav1.ConditionTrue
+	if failure != "" {
+		status = metav1.ConditionFalse
+	}
+	task.Status.FailureReason = failure
+	task.ClearCondition(axonv1alpha1.TaskConditionAwaitingApproval, reason, message)
+	task.SetCondition(axonv1alpha1.TaskConditionSucceeded, status, reason, message)
+	task.Status.Message = message
+	task.Status.CompletionTime = &now
+	if err := r.Status().Update(ctx, task); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to update Task status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,16 +11,30 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
 )
 
 const (
 	taskFinalizer = "axon.io/finalizer"
+
+	// pendingPodRequeueInterval is how often a Task whose Pod has not
+	// started is checked for a Pod stuck pulling its image or waiting to
+	// be scheduled. Pods are not watched, and a stuck Pod does not change
+	// its Job's status.
+	pendingPodRequeueInterval = 15 * time.Second
+
+	// completionActionsAnnotation is set to completionActionsPending by the
+	// spawner on Tasks whose item it acts on once they finished, and
+	// changed once it did.
+	completionActionsAnnotation = "axon.io/completion-actions"
+	completionActionsPending    = "Pending"
 )
 
 // TaskReconciler reconciles a Task object.
@@ -27,14 +42,26 @@ type TaskReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	JobBuilder *JobBuilder
+	Recorder   events.EventRecorder
+
+	// APIReader reads Secrets directly from the API server. If nil, the
+	// Client is used.
+	APIReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=axon.io,resources=tasks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=axon.io,resources=tasks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=axon.io,resources=tasks/finalizers,verbs=update
 // +kubebuilder:rbac:groups=axon.io,resources=workspaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=axon.io,resources=axonconfigs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;delete
 
 // Reconcile handles Task reconciliation.
 func (r *TaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -64,10 +91,28 @@ func (r *TaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Deliver the notifications of the Task's current phase
+	notificationsPending, notificationsRequeue, err := r.reconcileNotifications(ctx, &task)
+	if err != nil {
+		logger.Error(err, "Unable to deliver notifications")
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.reconcileTask(ctx, &task)
+	if err == nil && notificationsPending {
+		result.RequeueAfter = minRequeue(result.RequeueAfter, notificationsRequeue)
+	}
+	return result, err
+}
+
+// reconcileTask runs the Task's Job and updates the Task's status from it.
+func (r *TaskReconciler) reconcileTask(ctx context.Context, task *axonv1alpha1.Task) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
 	// Check if Job already exists
 	var job batchv1.Job
 	jobExists := true
-	if err := r.Get(ctx, req.NamespacedName, &job); err != nil {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(task), &job); err != nil {
 		if apierrors.IsNotFound(err) {
 			jobExists = false
 		} else {
@@ -76,21 +121,80 @@ func (r *TaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		}
 	}
 
+	// Stop the agent of a suspended Task that has not finished yet
+	if task.Spec.Suspend && !isTaskFinished(task) {
+		if !jobExists {
+			return r.suspendTask(ctx, task, nil)
+		}
+		return r.suspendTask(ctx, task, &job)
+	}
+
+	// Wait for the Job of a previously suspended Task to go away before
+	// starting a new one
+	if jobExists && !job.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
 	// Create Job if it doesn't exist
 	if !jobExists {
-		return r.createJob(ctx, &task)
+		return r.createJob(ctx, task)
+	}
+
+	// The agent must not change its Task or its diff once it has exited.
+	// The effective spec is not consulted, since the agent could rewrite it.
+	if job.Status.Active == 0 && (job.Status.Succeeded > 0 || job.Status.Failed > 0) {
+		if err := revokeAgentRBAC(ctx, r.Client, task); err != nil {
+			logger.Error(err, "Unable to revoke agent RBAC")
+			return ctrl.Result{}, err
+		}
 	}
 
-	// Update status based on Job status
-	result, err := r.updateStatus(ctx, &task, &job)
+	// Update status based on Job status. The changes of a Task that
+	// requires approval are only done once they have been pushed.
+	var result ctrl.Result
+	var err error
+	if effectiveSpec(task).RequireApproval && job.Status.Succeeded > 0 {
+		result, err = r.reconcileApproval(ctx, task, &job)
+	} else {
+		result, err = r.updateStatus(ctx, task, &job)
+	}
 	if err != nil {
 		return result, err
 	}
 
-	// Check TTL expiration for finished Tasks
-	if expired, requeueAfter := r.ttlExpired(&task); expired {
+	// Check TTL expiration for finished Tasks. The TTL may be edited after
+	// the Job was built, so it is taken from the Task's current spec.
+	ttl, err := r.resolveTTLSecondsAfterFinished(ctx, task)
+	if err != nil {
+		logger.Error(err, "Unable to resolve the TTL")
+		return ctrl.Result{}, err
+	}
+	if expired, requeueAfter := r.ttlExpired(task, ttl); expired {
+		// The Task may have just finished, so deliver the notifications of
+		// its final phase before it is gone
+		pending, retryAfter, err := r.reconcileNotifications(ctx, task)
+		if err != nil {
+			logger.Error(err, "Unable to deliver notifications")
+			return ctrl.Result{}, err
+		}
+		if pending {
+			logger.Info("Delaying TTL deletion until notifications are delivered", "task", task.Name)
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+		awaiting, err := r.awaitingCompletionActions(ctx, task)
+		if err != nil {
+			logger.Error(err, "Unable to check completion actions")
+			return ctrl.Result{}, err
+		}
+		if awaiting {
+			// Setting the annotation triggers another reconcile; the
+			// requeue notices a TaskSpawner that was deleted meanwhile
+			logger.Info("Delaying TTL deletion until the TaskSpawner applied its completion actions", "task", task.Name)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+
 		logger.Info("Deleting Task due to TTL expiration", "task", task.Name)
-		if err := r.Delete(ctx, &task); err != nil {
+		if err := r.Delete(ctx, task); err != nil {
 			if apierrors.IsNotFound(err) {
 				return ctrl.Result{}, nil
 			}
@@ -136,6 +240,41 @@ func (r *TaskReconciler) handleDeletion(ctx context.Context, task *axonv1alpha1.
 	return ctrl.Result{}, nil
 }
 
+// suspendTask deletes the Task's Job, if any, and moves the Task to the
+// Suspended phase while keeping the rest of its status.
+func (r *TaskReconciler) suspendTask(ctx context.Context, task *axonv1alpha1.Task, job *batchv1.Job) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if job != nil && job.DeletionTimestamp.IsZero() {
+		propagationPolicy := metav1.DeletePropagationBackground
+		if err := r.Delete(ctx, job, &client.DeleteOptions{
+			PropagationPolicy: &propagationPolicy,
+		}); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Unable to delete Job of suspended Task")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Deleted Job of suspended Task", "job", job.Name)
+	}
+
+	if task.Status.Phase != axonv1alpha1.TaskPhaseSuspended {
+		task.SetCondition(axonv1alpha1.TaskConditionSuspended, metav1.ConditionTrue, "Suspended", "Task suspended")
+		task.ClearCondition(axonv1alpha1.TaskConditionAgentStarted, "Suspended", "The agent was stopped")
+		task.Status.PodName = ""
+		task.Status.Message = "Task suspended"
+		if err := r.Status().Update(ctx, task); err != nil {
+			logger.Error(err, "Unable to update Task status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// isTaskFinished reports whether the Task has reached a terminal phase.
+func isTaskFinished(task *axonv1alpha1.Task) bool {
+	return task.Status.Phase == axonv1alpha1.TaskPhaseSucceeded || task.Status.Phase == axonv1alpha1.TaskPhaseFailed
+}
+
 // createJob creates a Job for the Task.
 func (r *TaskReconciler) createJob(ctx context.Context, task *axonv1alpha1.Task) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -149,8 +288,11 @@ func (r *TaskReconciler) createJob(ctx context.Context, task *axonv1alpha1.Task)
 		}, &ws); err != nil {
 			logger.Error(err, "Unable to fetch Workspace", "workspace", task.Spec.WorkspaceRef.Name)
 			if apierrors.IsNotFound(err) {
-				task.Status.Phase = axonv1alpha1.TaskPhaseFailed
 				task.Status.Message = fmt.Sprintf("Workspace %q not found", task.Spec.WorkspaceRef.Name)
+				task.SetCondition(axonv1alpha1.TaskConditionWorkspaceReady, metav1.ConditionFalse, reasonWorkspaceNotFound, task.Status.Message)
+				task.Status.FailureReason = axonv1alpha1.TaskFailureConfigurationError
+				task.SetCondition(axonv1alpha1.TaskConditionSucceeded, metav1.ConditionFalse, reasonWorkspaceNotFound, task.Status.Message)
+				r.Recorder.Eventf(task, nil, corev1.EventTypeWarning, reasonWorkspaceNotFound, "CreateJob", "%s", task.Status.Message)
 				if updateErr := r.Status().Update(ctx, task); updateErr != nil {
 					logger.Error(updateErr, "Unable to update Task status")
 					return ctrl.Result{}, updateErr
@@ -160,13 +302,96 @@ func (r *TaskReconciler) createJob(ctx context.Context, task *axonv1alpha1.Task)
 			return ctrl.Result{}, err
 		}
 		workspace = &ws.Spec
+		task.SetCondition(axonv1alpha1.TaskConditionWorkspaceReady, metav1.ConditionTrue, "WorkspaceFound",
+			fmt.Sprintf("Workspace %q found", task.Spec.WorkspaceRef.Name))
 	}
 
-	job, err := r.JobBuilder.Build(task, workspace)
+	spec, err := r.resolveEffectiveSpec(ctx, task)
+	if err != nil {
+		logger.Error(err, "Unable to resolve effective Task spec")
+		return ctrl.Result{}, err
+	}
+	if spec.Credentials == nil {
+		task.Status.Message = "No credentials specified and no AxonConfig in the namespace provides default credentials"
+		task.SetCondition(axonv1alpha1.TaskConditionJobCreated, metav1.ConditionFalse, reasonMissingCredentials, task.Status.Message)
+		task.Status.FailureReason = axonv1alpha1.TaskFailureConfigurationError
+		task.SetCondition(axonv1alpha1.TaskConditionSucceeded, metav1.ConditionFalse, reasonMissingCredentials, task.Status.Message)
+		r.Recorder.Eventf(task, nil, corev1.EventTypeWarning, reasonMissingCredentials, "CreateJob", "%s", task.Status.Message)
+		if updateErr := r.Status().Update(ctx, task); updateErr != nil {
+			logger.Error(updateErr, "Unable to update Task status")
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, nil
+	}
+
+	claimName, err := r.resolveSessionClaim(ctx, task, spec)
+	if err != nil {
+		var notReady *errSessionNotReady
+		var unavailable *errSessionUnavailable
+		switch {
+		case errors.As(err, &notReady):
+			if task.Status.Message != notReady.Error() {
+				task.Status.Message = notReady.Error()
+				task.SetCondition(axonv1alpha1.TaskConditionJobCreated, metav1.ConditionFalse, "SessionNotReady", task.Status.Message)
+				if updateErr := r.Status().Update(ctx, task); updateErr != nil {
+					logger.Error(updateErr, "Unable to update Task status")
+					return ctrl.Result{}, updateErr
+				}
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		case errors.As(err, &unavailable):
+			task.Status.Message = unavailable.Error()
+			task.SetCondition(axonv1alpha1.TaskConditionJobCreated, metav1.ConditionFalse, "SessionUnavailable", task.Status.Message)
+			task.Status.FailureReason = axonv1alpha1.TaskFailureConfigurationError
+			task.SetCondition(axonv1alpha1.TaskConditionSucceeded, metav1.ConditionFalse, "SessionUnavailable", task.Status.Message)
+			if updateErr := r.Status().Update(ctx, task); updateErr != nil {
+				logger.Error(updateErr, "Unable to update Task status")
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Unable to resolve session PersistentVolumeClaim")
+		return ctrl.Result{}, err
+	}
+	task.Status.SessionClaimName = claimName
+
+	archive, err := r.resolveTranscriptArchive(ctx, task)
+	if err != nil {
+		logger.Error(err, "Unable to resolve transcript archive")
+		return ctrl.Result{}, err
+	}
+	task.Status.TranscriptArchive = archive
+
+	if spec.RequireApproval {
+		if err := r.resetDiff(ctx, task); err != nil {
+			logger.Error(err, "Unable to reset diff ConfigMap")
+			return ctrl.Result{}, err
+		}
+	}
+	if usesAgentServiceAccount(spec) {
+		if err := ensureAgentRBAC(ctx, r.Client, r.Scheme, task); err != nil {
+			logger.Error(err, "Unable to ensure agent RBAC")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// A question left unanswered by a previous Job cannot be answered anymore
+	if awaitingInput(task) {
+		task.Status.InputRequest = nil
+	}
+	task.ClearCondition(axonv1alpha1.TaskConditionAwaitingInput, "JobCreated", "The question was asked by a previous Job")
+
+	effective := task.DeepCopy()
+	effective.Spec = *spec
+
+	job, err := r.JobBuilder.Build(effective, workspace)
 	if err != nil {
 		logger.Error(err, "unable to build Job")
-		task.Status.Phase = axonv1alpha1.TaskPhaseFailed
 		task.Status.Message = fmt.Sprintf("Failed to build Job: %v", err)
+		task.SetCondition(axonv1alpha1.TaskConditionJobCreated, metav1.ConditionFalse, reasonJobBuildFailed, task.Status.Message)
+		task.Status.FailureReason = axonv1alpha1.TaskFailureConfigurationError
+		task.SetCondition(axonv1alpha1.TaskConditionSucceeded, metav1.ConditionFalse, reasonJobBuildFailed, task.Status.Message)
+		r.Recorder.Eventf(task, nil, corev1.EventTypeWarning, reasonJobBuildFailed, "CreateJob", "%s", task.Status.Message)
 		if updateErr := r.Status().Update(ctx, task); updateErr != nil {
 			logger.Error(updateErr, "unable to update Task status")
 		}
@@ -188,10 +413,15 @@ func (r *TaskReconciler) createJob(ctx context.Context, task *axonv1alpha1.Task)
 	}
 
 	logger.Info("created Job", "job", job.Name)
+	r.Recorder.Eventf(task, job, corev1.EventTypeNormal, reasonJobCreated, "CreateJob", "Created Job %s", job.Name)
 
 	// Update status
-	task.Status.Phase = axonv1alpha1.TaskPhasePending
+	message := fmt.Sprintf("Created Job %s", job.Name)
+	task.SetCondition(axonv1alpha1.TaskConditionJobCreated, metav1.ConditionTrue, reasonJobCreated, message)
+	task.ClearCondition(axonv1alpha1.TaskConditionSuspended, "Resumed", "Task resumed")
+	task.ClearCondition(axonv1alpha1.TaskConditionAgentStarted, reasonJobCreated, "Waiting for the new Job to start")
 	task.Status.JobName = job.Name
+	task.Status.EffectiveSpec = spec
 	if err := r.Status().Update(ctx, task); err != nil {
 		logger.Error(err, "unable to update Task status")
 		return ctrl.Result{}, err
@@ -204,44 +434,87 @@ func (r *TaskReconciler) createJob(ctx context.Context, task *axonv1alpha1.Task)
 func (r *TaskReconciler) updateStatus(ctx context.Context, task *axonv1alpha1.Task, job *batchv1.Job) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// Find pod name
-	if task.Status.PodName == "" {
-		var pods corev1.PodList
-		if err := r.List(ctx, &pods, client.InNamespace(task.Namespace), client.MatchingLabels{
-			"axon.io/task": task.Name,
-		}); err == nil && len(pods.Items) > 0 {
-			task.Status.PodName = pods.Items[0].Name
-		}
+	pod := r.jobPod(ctx, task, job)
+	if pod != nil && task.Status.PodName == "" {
+		task.Status.PodName = pod.Name
 	}
 
 	// Update phase based on Job status
 	var statusChanged bool
+	var result ctrl.Result
 
 	if job.Status.Active > 0 {
-		if task.Status.Phase != axonv1alpha1.TaskPhaseRunning {
-			task.Status.Phase = axonv1alpha1.TaskPhaseRunning
-			now := metav1.Now()
-			task.Status.StartTime = &now
+		answered, requeueAfter := applyInputTimeout(task, time.Now())
+		if answered {
+			logger.Info("Answered input request with the default answer after timeout", "request", task.Status.InputRequest.ID)
 			statusChanged = true
 		}
+		result.RequeueAfter = requeueAfter
+
+		if pod == nil || pod.Status.Phase == corev1.PodPending {
+			if result.RequeueAfter == 0 || result.RequeueAfter > pendingPodRequeueInterval {
+				result.RequeueAfter = pendingPodRequeueInterval
+			}
+		}
+
+		if reason, message, stuck := stuckPod(pod); stuck {
+			// The kubelet keeps retrying, so the Task stays Pending until
+			// the Pod starts or the Job gives up
+			if task.Status.Message != message {
+				task.SetCondition(axonv1alpha1.TaskConditionAgentStarted, metav1.ConditionFalse, string(reason), message)
+				task.Status.Message = message
+				statusChanged = true
+			}
+		} else {
+			phase := axonv1alpha1.TaskPhaseRunning
+			message := ""
+			if awaitingInput(task) {
+				phase = axonv1alpha1.TaskPhaseAwaitingInput
+				message = fmt.Sprintf("Waiting for input: %s", task.Status.InputRequest.Question)
+			}
+			if task.Status.Phase != phase || task.Status.Message != message {
+				// The agent keeps running while it waits for input, so only a
+				// new run resets the start time
+				if task.Status.Phase != axonv1alpha1.TaskPhaseRunning && task.Status.Phase != axonv1alpha1.TaskPhaseAwaitingInput {
+					now := metav1.Now()
+					task.Status.StartTime = &now
+				}
+				task.SetCondition(axonv1alpha1.TaskConditionAgentStarted, metav1.ConditionTrue, "JobActive", "The agent is running")
+				if phase == axonv1alpha1.TaskPhaseAwaitingInput {
+					task.SetCondition(axonv1alpha1.TaskConditionAwaitingInput, metav1.ConditionTrue, "QuestionAsked", message)
+				} else {
+					task.ClearCondition(axonv1alpha1.TaskConditionAwaitingInput, "QuestionAnswered", "The question was answered")
+				}
+				task.Status.Message = message
+				statusChanged = true
+			}
+		}
 	} else if job.Status.Succeeded > 0 {
 		if task.Status.Phase != axonv1alpha1.TaskPhaseSucceeded {
-			task.Status.Phase = axonv1alpha1.TaskPhaseSucceeded
 			now := metav1.Now()
 			task.Status.CompletionTime = &now
 			task.Status.Message = "Task completed successfully"
+			task.ClearCondition(axonv1alpha1.TaskConditionAwaitingInput, "JobFinished", "The agent has exited")
+			task.SetCondition(axonv1alpha1.TaskConditionSucceeded, metav1.ConditionTrue, "JobSucceeded", task.Status.Message)
 			statusChanged = true
 		}
 	} else if job.Status.Failed > 0 {
 		if task.Status.Phase != axonv1alpha1.TaskPhaseFailed {
-			task.Status.Phase = axonv1alpha1.TaskPhaseFailed
 			now := metav1.Now()
 			task.Status.CompletionTime = &now
-			task.Status.Message = "Task failed"
+			task.Status.FailureReason, task.Status.Message = diagnoseFailure(job, pod)
+			task.ClearCondition(axonv1alpha1.TaskConditionAwaitingInput, "JobFinished", "The agent has exited")
+			task.SetCondition(axonv1alpha1.TaskConditionSucceeded, metav1.ConditionFalse, string(task.Status.FailureReason), task.Status.Message)
 			statusChanged = true
 		}
 	}
 
+	// Record the usage the agent reported once it has finished
+	finished := statusChanged && isTaskFinished(task)
+	if finished {
+		task.Status.Usage = usageOf(pod)
+	}
+
 	if statusChanged {
 		if err := r.Status().Update(ctx, task); err != nil {
 			logger.Error(err, "unable to update Task status")
@@ -249,24 +522,82 @@ func (r *TaskReconciler) updateStatus(ctx context.Context, task *axonv1alpha1.Ta
 		}
 	}
 
-	return ctrl.Result{}, nil
+	if finished {
+		observeFinishedTask(task, pod)
+		if name, ok := oomKilledContainer(pod); ok {
+			r.Recorder.Eventf(task, pod, corev1.EventTypeWarning, reasonOOMKilled, "UpdateStatus", "Container %s of Pod %s was OOMKilled", name, pod.Name)
+		}
+		if task.Status.Phase == axonv1alpha1.TaskPhaseSucceeded {
+			r.Recorder.Eventf(task, job, corev1.EventTypeNormal, reasonSucceeded, "UpdateStatus", "%s", task.Status.Message)
+		} else {
+			r.Recorder.Eventf(task, job, corev1.EventTypeWarning, reasonFailed, "UpdateStatus", "%s", task.Status.Message)
+		}
+	}
+
+	return result, nil
 }
 
-// ttlExpired checks whether a finished Task has exceeded its TTL.
-// It returns (true, 0) if the Task should be deleted now, or (false, duration)
-// if the Task should be requeued after the given duration.
-func (r *TaskReconciler) ttlExpired(task *axonv1alpha1.Task) (bool, time.Duration) {
-	if task.Spec.TTLSecondsAfterFinished == nil {
+// jobPod returns the Pod of the Task's current Job, preferring the one
+// recorded in the Task's status.
+func (r *TaskReconciler) jobPod(ctx context.Context, task *axonv1alpha1.Task, job *batchv1.Job) *corev1.Pod {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(task.Namespace), client.MatchingLabels{
+		"axon.io/task": task.Name,
+	}); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list Pods of the Task's Job")
+		return nil
+	}
+
+	var found *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		// Pods of a Job deleted when the Task was suspended may still
+		// be terminating, so only consider Pods owned by the current Job.
+		if !metav1.IsControlledBy(pod, job) {
+			continue
+		}
+		if pod.Name == task.Status.PodName {
+			return pod
+		}
+		if found == nil {
+			found = pod
+		}
+	}
+	return found
+}
+
+// awaitingCompletionActions reports whether the TaskSpawner that created the
+// Task has yet to apply its completion actions to the Task's item.
+func (r *TaskReconciler) awaitingCompletionActions(ctx context.Context, task *axonv1alpha1.Task) (bool, error) {
+	name := task.Labels["axon.io/taskspawner"]
+	if name == "" || task.Annotations[completionActionsAnnotation] != completionActionsPending {
+		return false, nil
+	}
+	var ts axonv1alpha1.TaskSpawner
+	if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: name}, &ts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ttlExpired checks whether a finished Task has exceeded the given TTL in
+// seconds. It returns (true, 0) if the Task should be deleted now, or
+// (false, duration) if the Task should be requeued after the given duration.
+func (r *TaskReconciler) ttlExpired(task *axonv1alpha1.Task, ttlSeconds *int32) (bool, time.Duration) {
+	if ttlSeconds == nil {
 		return false, 0
 	}
-	if task.Status.Phase != axonv1alpha1.TaskPhaseSucceeded && task.Status.Phase != axonv1alpha1.TaskPhaseFailed {
+	if !isTaskFinished(task) {
 		return false, 0
 	}
 	if task.Status.CompletionTime == nil {
 		return false, 0
 	}
 
-	ttl := time.Duration(*task.Spec.TTLSecondsAfterFinished) * time.Second
+	ttl := time.Duration(*ttlSeconds) * time.Second
 	expireAt := task.Status.CompletionTime.Add(ttl)
 	remaining := time.Until(expireAt)
 	if remaining <= 0 {
@@ -277,6 +608,9 @@ func (r *TaskReconciler) ttlExpired(task *axonv1alpha1.Task) (bool, time.Duratio
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := metrics.Registry.Register(&taskCollector{client: mgr.GetClient()}); err != nil {
+		return fmt.Errorf("registering Task metrics: %w", err)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&axonv1alpha1.Task{}).
 		Owns(&batchv1.Job{}).
@@ -141,7 +141,7 @@ func TestTTLExpired(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expired, requeueAfter := r.ttlExpired(tt.task)
+			expired, requeueAfter := r.ttlExpired(tt.task, tt.task.Spec.TTLSecondsAfterFinished)
 			if expired != tt.wantExpired {
 				t.Errorf("ttlExpired() expired = %v, want %v", expired, tt.wantExpired)
 			}
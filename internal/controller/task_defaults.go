@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/artifact"
+)
+
+// resolveEffectiveSpec returns the Task's spec merged with the defaults of
+// every AxonConfig in the Task's namespace. AxonConfigs are applied in name
+// order, so the first AxonConfig that sets a field wins.
+func (r *TaskReconciler) resolveEffectiveSpec(ctx context.Context, task *axonv1alpha1.Task) (*axonv1alpha1.TaskSpec, error) {
+	configs, err := r.listAxonConfigs(ctx, task.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := task.Spec.DeepCopy()
+	for _, c := range configs {
+		applyTaskDefaults(spec, c.Spec.TaskDefaults)
+	}
+	return spec, nil
+}
+
+// resolveTTLSecondsAfterFinished returns the TTL of the Task: the one in its
+// current spec, which may have been edited since its Job was built, or else
+// the default of the first AxonConfig in name order that sets one.
+func (r *TaskReconciler) resolveTTLSecondsAfterFinished(ctx context.Context, task *axonv1alpha1.Task) (*int32, error) {
+	if task.Spec.TTLSecondsAfterFinished != nil {
+		return task.Spec.TTLSecondsAfterFinished, nil
+	}
+
+	configs, err := r.listAxonConfigs(ctx, task.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range configs {
+		if d := c.Spec.TaskDefaults; d != nil && d.TTLSecondsAfterFinished != nil {
+			return d.TTLSecondsAfterFinished, nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveTranscriptArchive returns where the Task's transcript is archived,
+// or nil if no AxonConfig in the Task's namespace configures a transcript
+// archive. The first AxonConfig in name order that configures one wins.
+func (r *TaskReconciler) resolveTranscriptArchive(ctx context.Context, task *axonv1alpha1.Task) (*axonv1alpha1.TranscriptArchive, error) {
+	configs, err := r.listAxonConfigs(ctx, task.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range configs {
+		store := c.Spec.TranscriptArchive
+		if store == nil {
+			continue
+		}
+		return &axonv1alpha1.TranscriptArchive{
+			Store: *store.DeepCopy(),
+			Key:   artifact.TranscriptKey(artifact.StorePrefix(store), task.Namespace, task.Name, string(task.UID)),
+		}, nil
+	}
+	return nil, nil
+}
+
+// listAxonConfigs returns the AxonConfigs in the namespace in name order.
+func (r *TaskReconciler) listAxonConfigs(ctx context.Context, namespace string) ([]axonv1alpha1.AxonConfig, error) {
+	var configs axonv1alpha1.AxonConfigList
+	if err := r.List(ctx, &configs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(configs.Items, func(i, j int) bool {
+		return configs.Items[i].Name < configs.Items[j].Name
+	})
+	return configs.Items, nil
+}
+
+// effectiveSpec returns the spec the Task's Job was built from, falling back
+// to the Task's own spec if the Job has not been built yet.
+func effectiveSpec(task *axonv1alpha1.Task) *axonv1alpha1.TaskSpec {
+	if task.Status.EffectiveSpec != nil {
+		return task.Status.EffectiveSpec
+	}
+	return &task.Spec
+}
+
+// applyTaskDefaults fills the fields of spec that are unset with the values
+// from defaults. Fields that are already set on spec are left untouched.
+func applyTaskDefaults(spec *axonv1alpha1.TaskSpec, defaults *axonv1alpha1.TaskDefaults) {
+	if defaults == nil {
+		return
+	}
+
+	if spec.Credentials == nil && defaults.Credentials != nil {
+		spec.Credentials = defaults.Credentials.DeepCopy()
+	}
+	if spec.Model == "" {
+		spec.Model = defaults.Model
+	}
+	if spec.ActiveDeadlineSeconds == nil && defaults.ActiveDeadlineSeconds != nil {
+		v := *defaults.ActiveDeadlineSeconds
+		spec.ActiveDeadlineSeconds = &v
+	}
+	if spec.TTLSecondsAfterFinished == nil && defaults.TTLSecondsAfterFinished != nil {
+		v := *defaults.TTLSecondsAfterFinished
+		spec.TTLSecondsAfterFinished = &v
+	}
+	if len(spec.AllowedTools) == 0 && len(defaults.AllowedTools) > 0 {
+		spec.AllowedTools = append([]string(nil), defaults.AllowedTools...)
+	}
+	if len(spec.DisallowedTools) == 0 && len(defaults.DisallowedTools) > 0 {
+		spec.DisallowedTools = append([]string(nil), defaults.DisallowedTools...)
+	}
+	if defaults.Artifacts != nil {
+		if spec.Artifacts == nil {
+			spec.Artifacts = defaults.Artifacts.DeepCopy()
+		} else if spec.Artifacts.Store == nil && defaults.Artifacts.Store != nil {
+			spec.Artifacts.Store = defaults.Artifacts.Store.DeepCopy()
+		}
+	}
+	if defaults.PodOverrides != nil {
+		if spec.PodOverrides == nil {
+			spec.PodOverrides = &axonv1alpha1.PodOverrides{}
+		}
+		mergePodOverrides(spec.PodOverrides, defaults.PodOverrides)
+	}
+}
+
+// mergePodOverrides fills the unset fields of o from defaults. Node selector
+// entries and environment variables are merged by key, with the values
+// already present in o taking precedence.
+func mergePodOverrides(o, defaults *axonv1alpha1.PodOverrides) {
+	if o.Resources == nil && defaults.Resources != nil {
+		o.Resources = defaults.Resources.DeepCopy()
+	}
+	if len(o.Tolerations) == 0 && len(defaults.Tolerations) > 0 {
+		for i := range defaults.Tolerations {
+			o.Tolerations = append(o.Tolerations, *defaults.Tolerations[i].DeepCopy())
+		}
+	}
+	for k, v := range defaults.NodeSelector {
+		if _, ok := o.NodeSelector[k]; ok {
+			continue
+		}
+		if o.NodeSelector == nil {
+			o.NodeSelector = make(map[string]string)
+		}
+		o.NodeSelector[k] = v
+	}
+
+	names := make(map[string]struct{}, len(o.Env))
+	for _, e := range o.Env {
+		names[e.Name] = struct{}{}
+	}
+	for i := range defaults.Env {
+		// AxonConfigs are not validated on admission, so reserved
+		// variables are not taken from them
+		if _, ok := names[defaults.Env[i].Name]; ok || ReservedEnvName(defaults.Env[i].Name) {
+			continue
+		}
+		o.Env = append(o.Env, *defaults.Env[i].DeepCopy())
+	}
+}
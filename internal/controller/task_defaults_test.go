@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func TestApplyTaskDefaults(t *testing.T) {
+	int32Ptr := func(v int32) *int32 { return &v }
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	defaults := &axonv1alpha1.TaskDefaults{
+		Credentials: &axonv1alpha1.Credentials{
+			Type:      axonv1alpha1.CredentialTypeOAuth,
+			SecretRef: axonv1alpha1.SecretReference{Name: "team-credentials"},
+		},
+		Model:                   "opus",
+		ActiveDeadlineSeconds:   int64Ptr(3600),
+		TTLSecondsAfterFinished: int32Ptr(600),
+		AllowedTools:            []string{"Read", "Edit"},
+		PodOverrides: &axonv1alpha1.PodOverrides{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			NodeSelector: map[string]string{"pool": "agents", "zone": "a"},
+			Env: []corev1.EnvVar{
+				{Name: "HTTP_PROXY", Value: "http://proxy"},
+				{Name: "FOO", Value: "default"},
+				{Name: "GITHUB_TOKEN", Value: "ghp_injected"},
+			},
+		},
+	}
+
+	t.Run("Fills unset fields", func(t *testing.T) {
+		spec := &axonv1alpha1.TaskSpec{Type: AgentTypeClaudeCode, Prompt: "hello"}
+		applyTaskDefaults(spec, defaults)
+
+		if spec.Credentials == nil || spec.Credentials.SecretRef.Name != "team-credentials" {
+			t.Errorf("Credentials = %+v, want team-credentials", spec.Credentials)
+		}
+		if spec.Model != "opus" {
+			t.Errorf("Model = %q, want %q", spec.Model, "opus")
+		}
+		if spec.ActiveDeadlineSeconds == nil || *spec.ActiveDeadlineSeconds != 3600 {
+			t.Errorf("ActiveDeadlineSeconds = %v, want 3600", spec.ActiveDeadlineSeconds)
+		}
+		if spec.TTLSecondsAfterFinished == nil || *spec.TTLSecondsAfterFinished != 600 {
+			t.Errorf("TTLSecondsAfterFinished = %v, want 600", spec.TTLSecondsAfterFinished)
+		}
+		if len(spec.AllowedTools) != 2 {
+			t.Errorf("AllowedTools = %v, want 2 tools", spec.AllowedTools)
+		}
+		if spec.PodOverrides == nil || spec.PodOverrides.Resources == nil {
+			t.Fatalf("PodOverrides.Resources not applied: %+v", spec.PodOverrides)
+		}
+		if len(spec.PodOverrides.Env) != 2 {
+			t.Errorf("Env = %v, want 2 entries", spec.PodOverrides.Env)
+		}
+	})
+
+	t.Run("Keeps fields set on the Task", func(t *testing.T) {
+		spec := &axonv1alpha1.TaskSpec{
+			Type:   AgentTypeClaudeCode,
+			Prompt: "hello",
+			Credentials: &axonv1alpha1.Credentials{
+				Type:      axonv1alpha1.CredentialTypeAPIKey,
+				SecretRef: axonv1alpha1.SecretReference{Name: "my-key"},
+			},
+			Model:                   "haiku",
+			TTLSecondsAfterFinished: int32Ptr(0),
+			PodOverrides: &axonv1alpha1.PodOverrides{
+				NodeSelector: map[string]string{"pool": "gpu"},
+				Env:          []corev1.EnvVar{{Name: "FOO", Value: "task"}},
+			},
+		}
+		applyTaskDefaults(spec, defaults)
+
+		if spec.Credentials.SecretRef.Name != "my-key" {
+			t.Errorf("Credentials overridden: %+v", spec.Credentials)
+		}
+		if spec.Model != "haiku" {
+			t.Errorf("Model = %q, want %q", spec.Model, "haiku")
+		}
+		if *spec.TTLSecondsAfterFinished != 0 {
+			t.Errorf("TTLSecondsAfterFinished = %d, want 0", *spec.TTLSecondsAfterFinished)
+		}
+		if got := spec.PodOverrides.NodeSelector["pool"]; got != "gpu" {
+			t.Errorf("NodeSelector[pool] = %q, want %q", got, "gpu")
+		}
+		if got := spec.PodOverrides.NodeSelector["zone"]; got != "a" {
+			t.Errorf("NodeSelector[zone] = %q, want %q", got, "a")
+		}
+		env := map[string]string{}
+		for _, e := range spec.PodOverrides.Env {
+			env[e.Name] = e.Value
+		}
+		if env["FOO"] != "task" || env["HTTP_PROXY"] != "http://proxy" {
+			t.Errorf("Env = %v, want FOO=task and HTTP_PROXY merged", env)
+		}
+	})
+
+	t.Run("Does not alias the defaults", func(t *testing.T) {
+		spec := &axonv1alpha1.TaskSpec{Type: AgentTypeClaudeCode, Prompt: "hello"}
+		applyTaskDefaults(spec, defaults)
+		spec.Credentials.SecretRef.Name = "changed"
+		spec.PodOverrides.NodeSelector["pool"] = "changed"
+
+		if defaults.Credentials.SecretRef.Name != "team-credentials" {
+			t.Errorf("defaults.Credentials was modified")
+		}
+		if defaults.PodOverrides.NodeSelector["pool"] != "agents" {
+			t.Errorf("defaults.PodOverrides.NodeSelector was modified")
+		}
+	})
+
+	t.Run("Fills the artifact store of a Task that collects artifacts", func(t *testing.T) {
+		store := &axonv1alpha1.ArtifactStore{
+			PersistentVolumeClaim: &axonv1alpha1.PVCArtifactStore{ClaimName: "artifacts"},
+		}
+		spec := &axonv1alpha1.TaskSpec{
+			Type:      AgentTypeClaudeCode,
+			Prompt:    "hello",
+			Artifacts: &axonv1alpha1.ArtifactsSpec{Paths: []string{"report.md"}},
+		}
+		applyTaskDefaults(spec, &axonv1alpha1.TaskDefaults{
+			Artifacts: &axonv1alpha1.ArtifactsSpec{Paths: []string{"*.log"}, Store: store},
+		})
+
+		if len(spec.Artifacts.Paths) != 1 || spec.Artifacts.Paths[0] != "report.md" {
+			t.Errorf("Artifacts.Paths = %v, want [report.md]", spec.Artifacts.Paths)
+		}
+		if spec.Artifacts.Store == nil || spec.Artifacts.Store.PersistentVolumeClaim == nil {
+			t.Fatalf("Artifacts.Store not applied: %+v", spec.Artifacts.Store)
+		}
+		if spec.Artifacts.Store == store {
+			t.Errorf("Artifacts.Store aliases the defaults")
+		}
+	})
+
+	t.Run("Nil defaults", func(t *testing.T) {
+		spec := &axonv1alpha1.TaskSpec{Type: AgentTypeClaudeCode, Prompt: "hello"}
+		applyTaskDefaults(spec, nil)
+		if spec.Credentials != nil || spec.Model != "" || spec.PodOverrides != nil {
+			t.Errorf("expected spec to be unchanged, got %+v", spec)
+		}
+	})
+}
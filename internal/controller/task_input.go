@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+// awaitingInput reports whether the Task's agent is blocked on a question
+// that has not been answered yet.
+func awaitingInput(task *axonv1alpha1.Task) bool {
+	req := task.Status.InputRequest
+	return req != nil && req.AnsweredAt == nil
+}
+
+// applyInputTimeout answers a pending input request with the Task's default
+// answer once the input timeout has elapsed. It returns true if the request
+// was answered, or the duration after which the timeout elapses otherwise.
+func applyInputTimeout(task *axonv1alpha1.Task, now time.Time) (bool, time.Duration) {
+	if !awaitingInput(task) {
+		return false, 0
+	}
+	policy := effectiveSpec(task).HumanInput
+	if policy == nil || policy.Timeout == nil {
+		return false, 0
+	}
+
+	req := task.Status.InputRequest
+	remaining := req.RequestedAt.Add(policy.Timeout.Duration).Sub(now)
+	if remaining > 0 {
+		return false, remaining
+	}
+
+	answeredAt := metav1.NewTime(now)
+	req.Answer = policy.DefaultAnswer
+	req.AnsweredBy = agent.AnsweredByTimeout
+	req.AnsweredAt = &answeredAt
+	return true, 0
+}
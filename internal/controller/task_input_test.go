@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/agent"
+)
+
+func TestApplyInputTimeout(t *testing.T) {
+	now := time.Now()
+	requestedAt := func(ago time.Duration) metav1.Time {
+		return metav1.NewTime(now.Add(-ago))
+	}
+	policy := func(timeout time.Duration, defaultAnswer string) *axonv1alpha1.HumanInputPolicy {
+		return &axonv1alpha1.HumanInputPolicy{
+			Timeout:       &metav1.Duration{Duration: timeout},
+			DefaultAnswer: defaultAnswer,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		spec          axonv1alpha1.TaskSpec
+		request       *axonv1alpha1.InputRequest
+		wantAnswered  bool
+		wantRequeue   time.Duration
+		wantAnswer    string
+		wantUnchanged bool
+	}{
+		{
+			name:          "No input request",
+			spec:          axonv1alpha1.TaskSpec{HumanInput: policy(time.Minute, "yes")},
+			wantUnchanged: true,
+		},
+		{
+			name: "Already answered",
+			spec: axonv1alpha1.TaskSpec{HumanInput: policy(time.Minute, "yes")},
+			request: &axonv1alpha1.InputRequest{
+				ID: "a", RequestedAt: requestedAt(time.Hour), Answer: "no", AnsweredBy: "cli",
+				AnsweredAt: &metav1.Time{Time: now},
+			},
+			wantAnswer: "no",
+		},
+		{
+			name:       "No timeout",
+			spec:       axonv1alpha1.TaskSpec{HumanInput: &axonv1alpha1.HumanInputPolicy{}},
+			request:    &axonv1alpha1.InputRequest{ID: "a", RequestedAt: requestedAt(time.Hour)},
+			wantAnswer: "",
+		},
+		{
+			name:        "Timeout not yet elapsed",
+			spec:        axonv1alpha1.TaskSpec{HumanInput: policy(time.Minute, "yes")},
+			request:     &axonv1alpha1.InputRequest{ID: "a", RequestedAt: requestedAt(20 * time.Second)},
+			wantRequeue: 40 * time.Second,
+		},
+		{
+			name:         "Timeout elapsed",
+			spec:         axonv1alpha1.TaskSpec{HumanInput: policy(time.Minute, "yes")},
+			request:      &axonv1alpha1.InputRequest{ID: "a", RequestedAt: requestedAt(2 * time.Minute)},
+			wantAnswered: true,
+			wantAnswer:   "yes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &axonv1alpha1.Task{
+				Spec:   tt.spec,
+				Status: axonv1alpha1.TaskStatus{InputRequest: tt.request},
+			}
+
+			answered, requeueAfter := applyInputTimeout(task, now)
+			if answered != tt.wantAnswered {
+				t.Errorf("answered = %v, want %v", answered, tt.wantAnswered)
+			}
+			if requeueAfter != tt.wantRequeue {
+				t.Errorf("requeueAfter = %v, want %v", requeueAfter, tt.wantRequeue)
+			}
+			if tt.wantUnchanged {
+				if task.Status.InputRequest != nil {
+					t.Errorf("expected no input request, got %+v", task.Status.InputRequest)
+				}
+				return
+			}
+			if task.Status.InputRequest.Answer != tt.wantAnswer {
+				t.Errorf("Answer = %q, want %q", task.Status.InputRequest.Answer, tt.wantAnswer)
+			}
+			if tt.wantAnswered {
+				if task.Status.InputRequest.AnsweredBy != agent.AnsweredByTimeout {
+					t.Errorf("AnsweredBy = %q, want %q", task.Status.InputRequest.AnsweredBy, agent.AnsweredByTimeout)
+				}
+				if awaitingInput(task) {
+					t.Error("expected the input request to be answered")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/notify"
+)
+
+// sourceNumberAnnotation records the number of the GitHub issue or pull
+// request a Task was spawned for. The spawner only sets it for Tasks of
+// GitHub issues and comments sources.
+const sourceNumberAnnotation = "axon.io/source-number"
+
+// defaultNotificationPhases are the phases notifications are sent for
+// unless they list their own.
+var defaultNotificationPhases = []axonv1alpha1.TaskPhase{
+	axonv1alpha1.TaskPhaseSucceeded,
+	axonv1alpha1.TaskPhaseFailed,
+}
+
+// reconcileNotifications delivers the notifications of the Task's current
+// phase that have not been delivered yet. It returns whether any of them is
+// still pending, and if so, when delivery should be attempted again.
+func (r *TaskReconciler) reconcileNotifications(ctx context.Context, task *axonv1alpha1.Task) (bool, time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	phase := task.Status.Phase
+	if phase == "" {
+		return false, 0, nil
+	}
+	notifications, err := r.resolveNotifications(ctx, task)
+	if err != nil {
+		return false, 0, fmt.Errorf("resolving notifications: %w", err)
+	}
+
+	now := time.Now()
+	pending := false
+	var requeueAfter time.Duration
+	changed := false
+	var event *notify.Event
+	for i := range notifications {
+		n := &notifications[i]
+		phases := n.Phases
+		if len(phases) == 0 {
+			phases = defaultNotificationPhases
+		}
+		if !slices.Contains(phases, phase) {
+			continue
+		}
+
+		st := findNotificationStatus(task, n.Name, phase)
+		if st != nil && st.State != axonv1alpha1.NotificationStatePending {
+			continue
+		}
+		if st != nil && st.LastAttemptTime != nil {
+			if wait := st.LastAttemptTime.Add(notify.Backoff(st.Attempts)).Sub(now); wait > 0 {
+				pending = true
+				requeueAfter = minRequeue(requeueAfter, wait)
+				continue
+			}
+		}
+
+		sender, err := r.notificationSender(ctx, task, n)
+		if sender == nil && err == nil {
+			// The notification does not apply to this Task
+			continue
+		}
+		if err == nil {
+			if event == nil {
+				event = r.notificationEvent(ctx, task)
+			}
+			err = sender.Send(ctx, event)
+		}
+
+		if st == nil {
+			task.Status.Notifications = append(task.Status.Notifications, axonv1alpha1.NotificationStatus{
+				Name:  n.Name,
+				Phase: phase,
+				State: axonv1alpha1.NotificationStatePending,
+			})
+			st = &task.Status.Notifications[len(task.Status.Notifications)-1]
+		}
+		attemptTime := metav1.NewTime(now)
+		st.Attempts++
+		st.LastAttemptTime = &attemptTime
+		changed = true
+
+		switch {
+		case err == nil:
+			st.State = axonv1alpha1.NotificationStateDelivered
+			st.LastError = ""
+			logger.Info("Delivered notification", "notification", n.Name, "phase", phase)
+			r.Recorder.Eventf(task, nil, corev1.EventTypeNormal, reasonNotificationSent, "Notify",
+				"Sent notification %s for phase %s", n.Name, phase)
+		case notify.IsPermanent(err) || st.Attempts >= notify.MaxAttempts:
+			st.State = axonv1alpha1.NotificationStateFailed
+			st.LastError = err.Error()
+			logger.Error(err, "Giving up on notification", "notification", n.Name, "phase", phase, "attempts", st.Attempts)
+			r.Recorder.Eventf(task, nil, corev1.EventTypeWarning, reasonNotificationFailed, "Notify",
+				"Notification %s for phase %s failed after %d attempts: %v", n.Name, phase, st.Attempts, err)
+		default:
+			st.LastError = err.Error()
+			logger.Error(err, "Unable to deliver notification, will retry", "notification", n.Name, "phase", phase, "attempts", st.Attempts)
+			pending = true
+			requeueAfter = minRequeue(requeueAfter, notify.Backoff(st.Attempts))
+		}
+	}
+
+	if changed {
+		if err := r.Status().Update(ctx, task); err != nil {
+			return false, 0, err
+		}
+	}
+	return pending, requeueAfter, nil
+}
+
+// resolveNotifications returns the notifications of the AxonConfigs in the
+// Task's namespace, in name order, followed by those of the TaskSpawner
+// that created the Task. A notification of the TaskSpawner replaces a
+// notification of an AxonConfig with the same name; otherwise the first
+// notification with a name wins.
+func (r *TaskReconciler) resolveNotifications(ctx context.Context, task *axonv1alpha1.Task) ([]axonv1alpha1.Notification, error) {
+	configs, err := r.listAxonConfigs(ctx, task.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []axonv1alpha1.Notification
+	add := func(n axonv1alpha1.Notification, replace bool) {
+		for i := range notifications {
+			if notifications[i].Name == n.Name {
+				if replace {
+					notifications[i] = n
+				}
+				return
+			}
+		}
+		notifications = append(notifications, n)
+	}
+	for _, c := range configs {
+		for _, n := range c.Spec.Notifications {
+			add(n, false)
+		}
+	}
+
+	if name := task.Labels["axon.io/taskspawner"]; name != "" {
+		var ts axonv1alpha1.TaskSpawner
+		err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: name}, &ts)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		for _, n := range ts.Spec.Notifications {
+			add(n, true)
+		}
+	}
+	return notifications, nil
+}
+
+// findNotificationStatus returns the Task's delivery status of the
+// notification for the phase, or nil if it was not attempted yet.
+func findNotificationStatus(task *axonv1alpha1.Task, name string, phase axonv1alpha1.TaskPhase) *axonv1alpha1.NotificationStatus {
+	for i := range task.Status.Notifications {
+		st := &task.Status.Notifications[i]
+		if st.Name == name && st.Phase == phase {
+			return st
+		}
+	}
+	return nil
+}
+
+// notificationSender returns the Sender for the notification. It returns
+// nil without an error if the notification does not apply to the Task,
+// like a GitHub comment for a Task that was not spawned from GitHub.
+func (r *TaskReconciler) notificationSender(ctx context.Context, task *axonv1alpha1.Task, n *axonv1alpha1.Notification) (notify.Sender, error) {
+	switch {
+	case n.Slack != nil:
+		url, err := r.secretValue(ctx, task.Namespace, n.Slack.SecretRef.Name, "SLACK_WEBHOOK_URL")
+		if err != nil {
+			return nil, err
+		}
+		return &notify.Slack{WebhookURL: url}, nil
+
+	case n.Webhook != nil:
+		w := &notify.Webhook{URL: n.Webhook.URL}
+		if n.Webhook.SecretRef != nil {
+			secret, err := r.secretValue(ctx, task.Namespace, n.Webhook.SecretRef.Name, "WEBHOOK_SECRET")
+			if err != nil {
+				return nil, err
+			}
+			w.Secret = secret
+		}
+		return w, nil
+
+	case n.GitHubComment != nil:
+		src, err := r.githubSource(ctx, task)
+		if err != nil || src == nil {
+			return nil, err
+		}
+		if src.secretName == "" {
+			return nil, fmt.Errorf("commenting on GitHub requires the Workspace %q to have a secretRef", task.Spec.WorkspaceRef.Name)
+		}
+		token, err := r.secretValue(ctx, task.Namespace, src.secretName, "GITHUB_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		return &notify.GitHubComment{Owner: src.owner, Repo: src.repo, Number: src.number, Token: token}, nil
+	}
+	return nil, nil
+}
+
+// notificationEvent describes the Task's current phase for notifications.
+func (r *TaskReconciler) notificationEvent(ctx context.Context, task *axonv1alpha1.Task) *notify.Event {
+	event := &notify.Event{
+		Namespace:     task.Namespace,
+		Task:          task.Name,
+		Phase:         string(task.Status.Phase),
+		Message:       task.Status.Message,
+		FailureReason: string(task.Status.FailureReason),
+		TaskSpawner:   task.Labels["axon.io/taskspawner"],
+	}
+	if u := task.Status.Usage; u != nil {
+		event.CostUSD = u.CostUSD
+		event.NumTurns = u.NumTurns
+	}
+	if t := task.Status.StartTime; t != nil {
+		event.StartTime = &t.Time
+	}
+	if t := task.Status.CompletionTime; t != nil {
+		event.CompletionTime = &t.Time
+	}
+	if src, err := r.githubSource(ctx, task); err == nil && src != nil {
+		event.SourceURL = fmt.Sprintf("https://github.com/%s/%s/issues/%d", src.owner, src.repo, src.number)
+	}
+	return event
+}
+
+// taskGitHubSource is the GitHub issue or pull request a Task was spawned
+// for.
+type taskGitHubSource struct {
+	owner, repo string
+	number      int
+
+	// secretName is the name of the Workspace's Secret holding the
+	// GITHUB_TOKEN.
+	secretName string
+}
+
+// githubSource returns the GitHub issue or pull request the Task was
+// spawned for, or nil if it was not spawned from GitHub.
+func (r *TaskReconciler) githubSource(ctx context.Context, task *axonv1alpha1.Task) (*taskGitHubSource, error) {
+	number, err := strconv.Atoi(task.Annotations[sourceNumberAnnotation])
+	if err != nil || number <= 0 || task.Spec.WorkspaceRef == nil {
+		return nil, nil
+	}
+
+	var ws axonv1alpha1.Workspace
+	if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: task.Spec.WorkspaceRef.Name}, &ws); err != nil {
+		return nil, fmt.Errorf("fetching Workspace %q: %w", task.Spec.WorkspaceRef.Name, err)
+	}
+	owner, repo := parseGitHubOwnerRepo(ws.Spec.Repo)
+	src := &taskGitHubSource{owner: owner, repo: repo, number: number}
+	if ws.Spec.SecretRef != nil {
+		src.secretName = ws.Spec.SecretRef.Name
+	}
+	return src, nil
+}
+
+// secretValue returns the value of the key of the Secret. Secrets are read
+// through the APIReader, if set, so that not every Secret in the cluster is
+// cached.
+func (r *TaskReconciler) secretValue(ctx context.Context, namespace, name, key string) (string, error) {
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	var secret corev1.Secret
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return "", fmt.Errorf("fetching Secret %q: %w", name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %q has no %s key", name, key)
+	}
+	return string(value), nil
+}
+
+// minRequeue returns the shorter of the two requeue delays, ignoring zero.
+func minRequeue(a, b time.Duration) time.Duration {
+	if a == 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+// sessionClaimSize is the requested size of a session PersistentVolumeClaim.
+var sessionClaimSize = resource.MustParse("1Gi")
+
+// sessionClaimName returns the name of the session PersistentVolumeClaim
+// created for the given Task.
+func sessionClaimName(task *axonv1alpha1.Task) string {
+	return task.Name + "-session"
+}
+
+// errSessionNotReady is returned by resolveSessionClaim when the Task to
+// continue from has not finished yet.
+type errSessionNotReady struct {
+	task string
+}
+
+func (e *errSessionNotReady) Error() string {
+	return fmt.Sprintf("Waiting for Task %q to finish before continuing its session", e.task)
+}
+
+// errSessionUnavailable is returned by resolveSessionClaim when the session
+// to continue from cannot be used. It fails the Task.
+type errSessionUnavailable struct {
+	msg string
+}
+
+func (e *errSessionUnavailable) Error() string {
+	return e.msg
+}
+
+// resolveSessionClaim returns the name of the PersistentVolumeClaim that
+// holds the agent's session state for the Task, or an empty string if the
+// Task does not persist its session. A Task that continues from another
+// Task shares the claim of that Task, and is added as an owner of the claim
+// so that it outlives the Task it was created for. The claim can only be
+// mounted by one agent, so a session is continued by one Task at a time.
+func (r *TaskReconciler) resolveSessionClaim(ctx context.Context, task *axonv1alpha1.Task, spec *axonv1alpha1.TaskSpec) (string, error) {
+	logger := log.FromContext(ctx)
+
+	if spec.ContinueFrom != nil {
+		var prev axonv1alpha1.Task
+		if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: spec.ContinueFrom.Name}, &prev); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", &errSessionUnavailable{msg: fmt.Sprintf("Task %q to continue from not found", spec.ContinueFrom.Name)}
+			}
+			return "", err
+		}
+		if !isTaskFinished(&prev) {
+			return "", &errSessionNotReady{task: prev.Name}
+		}
+		if prev.Status.SessionClaimName == "" {
+			return "", &errSessionUnavailable{msg: fmt.Sprintf("Task %q has no persisted session", prev.Name)}
+		}
+
+		var pvc corev1.PersistentVolumeClaim
+		if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: prev.Status.SessionClaimName}, &pvc); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", &errSessionUnavailable{msg: fmt.Sprintf("Session of Task %q no longer exists", prev.Name)}
+			}
+			return "", err
+		}
+		if other, err := r.sessionClaimUser(ctx, task, pvc.Name); err != nil {
+			return "", err
+		} else if other != "" {
+			return "", &errSessionUnavailable{msg: fmt.Sprintf("Session of Task %q is already being continued by Task %q", prev.Name, other)}
+		}
+		if !hasOwnerReference(&pvc, task) {
+			if err := controllerutil.SetOwnerReference(task, &pvc, r.Scheme); err != nil {
+				return "", err
+			}
+			if err := r.Update(ctx, &pvc); err != nil {
+				return "", err
+			}
+		}
+		return pvc.Name, nil
+	}
+
+	if !spec.PersistSession {
+		return "", nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sessionClaimName(task),
+			Namespace: task.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "axon",
+				"app.kubernetes.io/component":  "session",
+				"app.kubernetes.io/managed-by": "axon-controller",
+				"axon.io/task":                 task.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: sessionClaimSize,
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(task, pvc, r.Scheme); err != nil {
+		return "", err
+	}
+	if err := r.Create(ctx, pvc); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return "", err
+		}
+	} else {
+		logger.Info("Created session PersistentVolumeClaim", "pvc", pvc.Name)
+	}
+	return pvc.Name, nil
+}
+
+// sessionClaimUser returns the name of another unfinished Task using the
+// session claim, or an empty string if there is none.
+func (r *TaskReconciler) sessionClaimUser(ctx context.Context, task *axonv1alpha1.Task, claimName string) (string, error) {
+	var tasks axonv1alpha1.TaskList
+	if err := r.List(ctx, &tasks, client.InNamespace(task.Namespace)); err != nil {
+		return "", err
+	}
+	for i := range tasks.Items {
+		other := &tasks.Items[i]
+		if other.UID != task.UID && other.Status.SessionClaimName == claimName && !isTaskFinished(other) {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func hasOwnerReference(obj metav1.Object, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,11 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -28,14 +28,18 @@ type TaskSpawnerReconciler struct {
 	client.Client
 	Scheme            *runtime.Scheme
 	DeploymentBuilder *DeploymentBuilder
+	Recorder          events.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=axon.io,resources=taskspawners,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=axon.io,resources=taskspawners/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=axon.io,resources=taskspawners/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create
 
 // Reconcile handles TaskSpawner reconciliation.
@@ -66,6 +70,8 @@ func (r *TaskSpawnerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	orig := ts.DeepCopy()
+
 	// Check if Deployment already exists
 	var deploy appsv1.Deployment
 	deployExists := true
@@ -84,18 +90,25 @@ func (r *TaskSpawnerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	// Resolve workspace for GitHub Issues source
+	// Ensure the Service exposing the spawner's metrics exists
+	if err := r.ensureMetricsService(ctx, &ts); err != nil {
+		logger.Error(err, "unable to ensure spawner metrics Service")
+		return ctrl.Result{}, err
+	}
+
+	// Resolve workspace for the GitHub sources
 	var workspace *axonv1alpha1.WorkspaceSpec
-	if gh := ts.Spec.When.GitHubIssues; gh != nil && gh.WorkspaceRef != nil {
+	if ref := ts.Spec.When.WorkspaceRef(); ref != nil {
 		var ws axonv1alpha1.Workspace
 		if err := r.Get(ctx, client.ObjectKey{
 			Namespace: ts.Namespace,
-			Name:      gh.WorkspaceRef.Name,
+			Name:      ref.Name,
 		}, &ws); err != nil {
-			logger.Error(err, "Unable to fetch Workspace for TaskSpawner", "workspace", gh.WorkspaceRef.Name)
+			logger.Error(err, "Unable to fetch Workspace for TaskSpawner", "workspace", ref.Name)
 			if apierrors.IsNotFound(err) {
-				ts.Status.Phase = axonv1alpha1.TaskSpawnerPhaseFailed
-				ts.Status.Message = fmt.Sprintf("Workspace %q not found", gh.WorkspaceRef.Name)
+				ts.Status.Message = fmt.Sprintf("Workspace %q not found", ref.Name)
+				ts.SetCondition(axonv1alpha1.TaskSpawnerConditionWorkspaceReady, metav1.ConditionFalse, reasonWorkspaceNotFound, ts.Status.Message)
+				r.Recorder.Eventf(&ts, nil, corev1.EventTypeWarning, reasonWorkspaceNotFound, "CreateDeployment", "%s", ts.Status.Message)
 				if updateErr := r.Status().Update(ctx, &ts); updateErr != nil {
 					logger.Error(updateErr, "Unable to update TaskSpawner status")
 					return ctrl.Result{}, updateErr
@@ -105,6 +118,7 @@ func (r *TaskSpawnerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return ctrl.Result{}, err
 		}
 		workspace = &ws.Spec
+		ts.SetCondition(axonv1alpha1.TaskSpawnerConditionWorkspaceReady, metav1.ConditionTrue, "WorkspaceFound", fmt.Sprintf("Workspace %q found", ref.Name))
 	}
 
 	// Create Deployment if it doesn't exist
@@ -118,12 +132,14 @@ func (r *TaskSpawnerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	// Update status with deployment name if not set
-	if ts.Status.DeploymentName != deploy.Name {
-		ts.Status.DeploymentName = deploy.Name
-		if ts.Status.Phase == "" {
-			ts.Status.Phase = axonv1alpha1.TaskSpawnerPhasePending
-		}
+	// Update status with the deployment name and its availability
+	ts.Status.DeploymentName = deploy.Name
+	if deploy.Status.AvailableReplicas > 0 {
+		ts.SetCondition(axonv1alpha1.TaskSpawnerConditionDeploymentAvailable, metav1.ConditionTrue, "MinimumReplicasAvailable", "The spawner is running")
+	} else {
+		ts.SetCondition(axonv1alpha1.TaskSpawnerConditionDeploymentAvailable, metav1.ConditionFalse, "NoReplicasAvailable", "The spawner is not running")
+	}
+	if !equality.Semantic.DeepEqual(orig.Status, ts.Status) {
 		if err := r.Status().Update(ctx, &ts); err != nil {
 			logger.Error(err, "unable to update TaskSpawner status")
 			return ctrl.Result{}, err
@@ -171,10 +187,11 @@ func (r *TaskSpawnerReconciler) createDeployment(ctx context.Context, ts *axonv1
 	}
 
 	logger.Info("created Deployment", "deployment", deploy.Name)
+	r.Recorder.Eventf(ts, deploy, corev1.EventTypeNormal, reasonDeploymentCreated, "CreateDeployment", "Created Deployment %s", deploy.Name)
 
 	// Update status
-	ts.Status.Phase = axonv1alpha1.TaskSpawnerPhasePending
 	ts.Status.DeploymentName = deploy.Name
+	ts.SetCondition(axonv1alpha1.TaskSpawnerConditionDeploymentAvailable, metav1.ConditionFalse, "DeploymentCreated", "The spawner Deployment was created")
 	if err := r.Status().Update(ctx, ts); err != nil {
 		logger.Error(err, "unable to update TaskSpawner status")
 		return ctrl.Result{}, err
@@ -198,7 +215,8 @@ func (r *TaskSpawnerReconciler) updateDeployment(ctx context.Context, ts *axonv1
 
 	needsUpdate := current.Image != target.Image ||
 		!equalStringSlices(current.Args, target.Args) ||
-		!equalEnvVars(current.Env, target.Env)
+		!equalEnvVars(current.Env, target.Env) ||
+		len(current.Ports) != len(target.Ports)
 
 	if !needsUpdate {
 		return nil
@@ -207,6 +225,7 @@ func (r *TaskSpawnerReconciler) updateDeployment(ctx context.Context, ts *axonv1
 	deploy.Spec.Template.Spec.Containers[0].Image = target.Image
 	deploy.Spec.Template.Spec.Containers[0].Args = target.Args
 	deploy.Spec.Template.Spec.Containers[0].Env = target.Env
+	deploy.Spec.Template.Spec.Containers[0].Ports = target.Ports
 
 	if err := r.Update(ctx, deploy); err != nil {
 		return err
@@ -257,72 +276,45 @@ func equalEnvVars(a, b []corev1.EnvVar) bool {
 	return true
 }
 
-// ensureSpawnerRBAC ensures a ServiceAccount and RoleBinding exist in the namespace.
-func (r *TaskSpawnerReconciler) ensureSpawnerRBAC(ctx context.Context, namespace string) error {
+// ensureMetricsService ensures the Service exposing the spawner's metrics
+// exists.
+func (r *TaskSpawnerReconciler) ensureMetricsService(ctx context.Context, ts *axonv1alpha1.TaskSpawner) error {
 	logger := log.FromContext(ctx)
 
-	// Ensure ServiceAccount
-	var sa corev1.ServiceAccount
-	if err := r.Get(ctx, types.NamespacedName{Name: SpawnerServiceAccount, Namespace: namespace}, &sa); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return err
-		}
-		sa = corev1.ServiceAccount{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      SpawnerServiceAccount,
-				Namespace: namespace,
-			},
-		}
-		if err := r.Create(ctx, &sa); err != nil {
-			if !apierrors.IsAlreadyExists(err) {
-				return err
-			}
-		} else {
-			logger.Info("created ServiceAccount", "namespace", namespace, "name", SpawnerServiceAccount)
-		}
+	var svc corev1.Service
+	err := r.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: ts.Name}, &svc)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
 	}
 
-	// Ensure RoleBinding
-	rbName := SpawnerServiceAccount
-	var rb rbacv1.RoleBinding
-	if err := r.Get(ctx, types.NamespacedName{Name: rbName, Namespace: namespace}, &rb); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return err
-		}
-		rb = rbacv1.RoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      rbName,
-				Namespace: namespace,
-			},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: rbacv1.GroupName,
-				Kind:     "ClusterRole",
-				Name:     SpawnerClusterRole,
-			},
-			Subjects: []rbacv1.Subject{
-				{
-					Kind:      "ServiceAccount",
-					Name:      SpawnerServiceAccount,
-					Namespace: namespace,
-				},
-			},
-		}
-		if err := r.Create(ctx, &rb); err != nil {
-			if !apierrors.IsAlreadyExists(err) {
-				return err
-			}
-		} else {
-			logger.Info("created RoleBinding", "namespace", namespace, "name", rbName)
+	desired := r.DeploymentBuilder.BuildService(ts)
+	if err := controllerutil.SetControllerReference(ts, desired, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, desired); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
 		}
+		return err
 	}
 
+	logger.Info("created metrics Service", "service", desired.Name)
 	return nil
 }
 
+// ensureSpawnerRBAC ensures a ServiceAccount and RoleBinding exist in the namespace.
+func (r *TaskSpawnerReconciler) ensureSpawnerRBAC(ctx context.Context, namespace string) error {
+	return ensureServiceAccountRBAC(ctx, r.Client, namespace, SpawnerServiceAccount, SpawnerClusterRole)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *TaskSpawnerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&axonv1alpha1.TaskSpawner{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
 		Complete(r)
 }
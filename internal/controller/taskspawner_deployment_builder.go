@@ -8,8 +8,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/source"
 )
 
 const (
@@ -21,6 +23,13 @@ const (
 
 	// SpawnerClusterRole is the ClusterRole referenced by spawner RoleBindings.
 	SpawnerClusterRole = "axon-spawner-role"
+
+	// SpawnerMetricsPort is the port spawners serve their metrics on.
+	SpawnerMetricsPort = 8080
+
+	// SpawnerMetricsPortName is the name of the metrics port of spawner
+	// Deployments and Services, for ServiceMonitors to select.
+	SpawnerMetricsPortName = "metrics"
 )
 
 // DeploymentBuilder constructs Kubernetes Deployments for TaskSpawners.
@@ -36,13 +45,16 @@ func NewDeploymentBuilder() *DeploymentBuilder {
 
 // Build creates a Deployment for the given TaskSpawner.
 // The workspace parameter provides the repository URL and optional secretRef
-// for GitHub API authentication.
+// for GitHub API authentication. The values of the HTTP source's headers
+// that come from Secrets, and the credentials of the Jira and Linear
+// sources, are passed in the environment.
 func (b *DeploymentBuilder) Build(ts *axonv1alpha1.TaskSpawner, workspace *axonv1alpha1.WorkspaceSpec) *appsv1.Deployment {
 	replicas := int32(1)
 
 	args := []string{
 		"--taskspawner-name=" + ts.Name,
 		"--taskspawner-namespace=" + ts.Namespace,
+		fmt.Sprintf("--metrics-bind-address=:%d", SpawnerMetricsPort),
 	}
 
 	var envVars []corev1.EnvVar
@@ -68,13 +80,61 @@ func (b *DeploymentBuilder) Build(ts *axonv1alpha1.TaskSpawner, workspace *axonv
 		}
 	}
 
-	labels := map[string]string{
-		"app.kubernetes.io/name":       "axon",
-		"app.kubernetes.io/component":  "spawner",
-		"app.kubernetes.io/managed-by": "axon-controller",
-		"axon.io/taskspawner":          ts.Name,
+	if src := ts.Spec.When.HTTP; src != nil {
+		for i, h := range src.Headers {
+			if h.SecretKeyRef == nil {
+				continue
+			}
+			envVars = append(envVars, corev1.EnvVar{
+				Name: fmt.Sprintf("%s%d", source.HTTPHeaderEnvPrefix, i),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: h.SecretKeyRef.Name,
+						},
+						Key: h.SecretKeyRef.Key,
+					},
+				},
+			})
+		}
+	}
+
+	if src := ts.Spec.When.JiraIssues; src != nil && src.SecretRef != nil {
+		optional := true
+		for _, key := range []string{"JIRA_USER", "JIRA_TOKEN"} {
+			selector := &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: src.SecretRef.Name,
+				},
+				Key: key,
+			}
+			if key == "JIRA_USER" {
+				// Personal access tokens come without a user
+				selector.Optional = &optional
+			}
+			envVars = append(envVars, corev1.EnvVar{
+				Name:      key,
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: selector},
+			})
+		}
+	}
+
+	if src := ts.Spec.When.LinearIssues; src != nil && src.SecretRef != nil {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: "LINEAR_API_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: src.SecretRef.Name,
+					},
+					Key: "LINEAR_API_KEY",
+				},
+			},
+		})
 	}
 
+	labels := spawnerLabels(ts)
+
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ts.Name,
@@ -100,6 +160,11 @@ func (b *DeploymentBuilder) Build(ts *axonv1alpha1.TaskSpawner, workspace *axonv
 							ImagePullPolicy: b.SpawnerImagePullPolicy,
 							Args:            args,
 							Env:             envVars,
+							Ports: []corev1.ContainerPort{{
+								Name:          SpawnerMetricsPortName,
+								ContainerPort: SpawnerMetricsPort,
+								Protocol:      corev1.ProtocolTCP,
+							}},
 						},
 					},
 				},
@@ -108,6 +173,40 @@ func (b *DeploymentBuilder) Build(ts *axonv1alpha1.TaskSpawner, workspace *axonv
 	}
 }
 
+// BuildService creates the Service exposing the metrics of the given
+// TaskSpawner's spawner. It carries the spawner's labels so that a single
+// ServiceMonitor selecting app.kubernetes.io/component=spawner scrapes all
+// spawners.
+func (b *DeploymentBuilder) BuildService(ts *axonv1alpha1.TaskSpawner) *corev1.Service {
+	labels := spawnerLabels(ts)
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ts.Name,
+			Namespace: ts.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Name:       SpawnerMetricsPortName,
+				Port:       SpawnerMetricsPort,
+				TargetPort: intstr.FromString(SpawnerMetricsPortName),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+func spawnerLabels(ts *axonv1alpha1.TaskSpawner) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "axon",
+		"app.kubernetes.io/component":  "spawner",
+		"app.kubernetes.io/managed-by": "axon-controller",
+		"axon.io/taskspawner":          ts.Name,
+	}
+}
+
 var gitHubHTTPSRe = regexp.MustCompile(`github\.com/([^/]+)/([^/.]+)`)
 var gitHubSSHRe = regexp.MustCompile(`github\.com:([^/]+)/([^/.]+)`)
 
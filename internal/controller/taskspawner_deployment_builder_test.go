@@ -1,6 +1,12 @@
 package controller
 
-import "testing"
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
 
 func TestParseGitHubOwnerRepo(t *testing.T) {
 	tests := []struct {
@@ -59,3 +65,114 @@ func TestParseGitHubOwnerRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSpawnerMetrics(t *testing.T) {
+	ts := &axonv1alpha1.TaskSpawner{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-spawner", Namespace: "default"},
+	}
+	b := NewDeploymentBuilder()
+
+	deploy := b.Build(ts, nil)
+	container := deploy.Spec.Template.Spec.Containers[0]
+	if !containsArg(container.Args, "--metrics-bind-address=:8080") {
+		t.Errorf("expected metrics bind address in args: %v", container.Args)
+	}
+	if len(container.Ports) != 1 || container.Ports[0].Name != SpawnerMetricsPortName || container.Ports[0].ContainerPort != SpawnerMetricsPort {
+		t.Errorf("unexpected ports: %+v", container.Ports)
+	}
+
+	svc := b.BuildService(ts)
+	if svc.Name != "my-spawner" || svc.Namespace != "default" {
+		t.Errorf("unexpected Service %s/%s", svc.Namespace, svc.Name)
+	}
+	if svc.Labels["app.kubernetes.io/component"] != "spawner" || svc.Labels["axon.io/taskspawner"] != "my-spawner" {
+		t.Errorf("unexpected Service labels: %v", svc.Labels)
+	}
+	for k, v := range deploy.Spec.Template.Labels {
+		if svc.Spec.Selector[k] != v {
+			t.Errorf("Service selector %v does not select the spawner Pod labels %v", svc.Spec.Selector, deploy.Spec.Template.Labels)
+			break
+		}
+	}
+	if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Name != SpawnerMetricsPortName || svc.Spec.Ports[0].TargetPort.StrVal != SpawnerMetricsPortName {
+		t.Errorf("unexpected Service ports: %+v", svc.Spec.Ports)
+	}
+}
+
+func TestBuildSpawnerHTTPHeaders(t *testing.T) {
+	ts := &axonv1alpha1.TaskSpawner{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-spawner", Namespace: "default"},
+		Spec: axonv1alpha1.TaskSpawnerSpec{
+			When: axonv1alpha1.When{HTTP: &axonv1alpha1.HTTPSource{
+				URL: "https://tickets.example.com/api/search",
+				Headers: []axonv1alpha1.HTTPHeader{
+					{Name: "Accept", Value: "application/json"},
+					{Name: "Authorization", Value: "Bearer ", SecretKeyRef: &axonv1alpha1.SecretKeySelector{Name: "tickets", Key: "token"}},
+				},
+			}},
+		},
+	}
+
+	deploy := NewDeploymentBuilder().Build(ts, nil)
+	env := deploy.Spec.Template.Spec.Containers[0].Env
+	if len(env) != 1 {
+		t.Fatalf("expected one environment variable, got %+v", env)
+	}
+	if env[0].Name != "HTTP_HEADER_1" || env[0].ValueFrom == nil || env[0].ValueFrom.SecretKeyRef == nil ||
+		env[0].ValueFrom.SecretKeyRef.Name != "tickets" || env[0].ValueFrom.SecretKeyRef.Key != "token" {
+		t.Errorf("expected the header's Secret key in HTTP_HEADER_1, got %+v", env[0])
+	}
+}
+
+func TestBuildSpawnerJiraCredentials(t *testing.T) {
+	ts := &axonv1alpha1.TaskSpawner{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-spawner", Namespace: "default"},
+		Spec: axonv1alpha1.TaskSpawnerSpec{
+			When: axonv1alpha1.When{JiraIssues: &axonv1alpha1.JiraIssues{
+				URL:       "https://example.atlassian.net",
+				JQL:       "project = PROJ",
+				SecretRef: &axonv1alpha1.SecretReference{Name: "jira"},
+			}},
+		},
+	}
+
+	deploy := NewDeploymentBuilder().Build(ts, nil)
+	env := deploy.Spec.Template.Spec.Containers[0].Env
+	if len(env) != 2 {
+		t.Fatalf("expected two environment variables, got %+v", env)
+	}
+	for i, key := range []string{"JIRA_USER", "JIRA_TOKEN"} {
+		ref := env[i].ValueFrom.SecretKeyRef
+		if env[i].Name != key || ref == nil || ref.Name != "jira" || ref.Key != key {
+			t.Errorf("expected %s from the Secret, got %+v", key, env[i])
+		}
+	}
+	if opt := env[0].ValueFrom.SecretKeyRef.Optional; opt == nil || !*opt {
+		t.Error("expected JIRA_USER to be optional")
+	}
+	if opt := env[1].ValueFrom.SecretKeyRef.Optional; opt != nil && *opt {
+		t.Error("expected JIRA_TOKEN to be required")
+	}
+}
+
+func TestBuildSpawnerLinearAPIKey(t *testing.T) {
+	ts := &axonv1alpha1.TaskSpawner{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-spawner", Namespace: "default"},
+		Spec: axonv1alpha1.TaskSpawnerSpec{
+			When: axonv1alpha1.When{LinearIssues: &axonv1alpha1.LinearIssues{
+				Team:      "ENG",
+				SecretRef: &axonv1alpha1.SecretReference{Name: "linear"},
+			}},
+		},
+	}
+
+	deploy := NewDeploymentBuilder().Build(ts, nil)
+	env := deploy.Spec.Template.Spec.Containers[0].Env
+	if len(env) != 1 {
+		t.Fatalf("expected one environment variable, got %+v", env)
+	}
+	ref := env[0].ValueFrom.SecretKeyRef
+	if env[0].Name != "LINEAR_API_KEY" || ref == nil || ref.Name != "linear" || ref.Key != "LINEAR_API_KEY" {
+		t.Errorf("expected LINEAR_API_KEY from the Secret, got %+v", env[0])
+	}
+}
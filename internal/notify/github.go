@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/axon-core/axon/internal/source"
+)
+
+// GitHubComment comments events on a GitHub issue or pull request.
+type GitHubComment struct {
+	Owner  string
+	Repo   string
+	Number int
+	Token  string
+
+	// BaseURL overrides the GitHub API URL.
+	BaseURL string
+
+	Client *http.Client
+}
+
+// Send implements Sender.
+func (g *GitHubComment) Send(ctx context.Context, event *Event) error {
+	client := g.Client
+	if client == nil {
+		client = defaultClient
+	}
+	gh := &source.GitHubSource{
+		Owner:   g.Owner,
+		Repo:    g.Repo,
+		Token:   g.Token,
+		BaseURL: g.BaseURL,
+		Client:  client,
+	}
+	return gh.CreateComment(ctx, g.Number, githubCommentBody(event))
+}
+
+// githubCommentBody formats the event in Markdown.
+func githubCommentBody(event *Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Task `%s` %s**", event.Task, event.Phase)
+	if event.FailureReason != "" {
+		fmt.Fprintf(&b, " (%s)", event.FailureReason)
+	}
+	if event.Message != "" {
+		fmt.Fprintf(&b, "\n\n%s", event.Message)
+	}
+	if details := event.details(); len(details) > 0 {
+		fmt.Fprintf(&b, "\n\n%s", strings.Join(details, " · "))
+	}
+	fmt.Fprintf(&b, "\n\nRun `axon get task %s -n %s` or `axon logs %s -n %s` for details.",
+		event.Task, event.Namespace, event.Task, event.Namespace)
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxAttempts is how many times delivery of a notification is attempted
+	// before giving up.
+	MaxAttempts = 6
+
+	// initialBackoff is the delay before the first retry. It doubles with
+	// every further attempt.
+	initialBackoff = 10 * time.Second
+
+	// maxBackoff caps the delay between attempts.
+	maxBackoff = 5 * time.Minute
+)
+
+// defaultClient is used by senders without a Client. Unlike
+// http.DefaultClient it does not wait forever for an unresponsive receiver.
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Event describes a Task that entered a phase.
+type Event struct {
+	Namespace      string     `json:"namespace"`
+	Task           string     `json:"task"`
+	Phase          string     `json:"phase"`
+	Message        string     `json:"message,omitempty"`
+	FailureReason  string     `json:"failureReason,omitempty"`
+	CostUSD        string     `json:"costUSD,omitempty"`
+	NumTurns       int32      `json:"numTurns,omitempty"`
+	StartTime      *time.Time `json:"startTime,omitempty"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	TaskSpawner    string     `json:"taskSpawner,omitempty"`
+
+	// SourceURL links to the issue or pull request the Task was spawned
+	// for.
+	SourceURL string `json:"sourceURL,omitempty"`
+}
+
+// details returns the cost, turns and duration of the event, if known.
+func (e *Event) details() []string {
+	var details []string
+	if e.CostUSD != "" {
+		details = append(details, "cost $"+e.CostUSD)
+	}
+	if e.NumTurns > 0 {
+		details = append(details, fmt.Sprintf("%d turns", e.NumTurns))
+	}
+	if e.StartTime != nil && e.CompletionTime != nil {
+		details = append(details, "took "+e.CompletionTime.Sub(*e.StartTime).Round(time.Second).String())
+	}
+	return details
+}
+
+// Sender delivers events to a receiver.
+type Sender interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+// PermanentError is returned by Send when retrying the delivery cannot
+// succeed, e.g. because the receiver rejected the request as invalid.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// IsPermanent reports whether err is a PermanentError.
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// Backoff returns how long to wait after the given number of failed
+// attempts before attempting delivery again.
+func Backoff(attempts int32) time.Duration {
+	d := initialBackoff
+	for i := int32(1); i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// post sends body to url and checks the response status. Client errors
+// other than timeouts and rate limits are permanent.
+func post(ctx context.Context, client *http.Client, url, contentType string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("creating request: %w", err)}
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("receiver returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent() *Event {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	return &Event{
+		Namespace:      "default",
+		Task:           "fixer-42",
+		Phase:          "Failed",
+		Message:        "The agent exited with code 1",
+		FailureReason:  "AgentError",
+		CostUSD:        "0.42",
+		NumTurns:       12,
+		StartTime:      &start,
+		CompletionTime: &end,
+		TaskSpawner:    "fixer",
+		SourceURL:      "https://github.com/org/repo/issues/42",
+	}
+}
+
+// receiver records the requests it receives and answers them with the
+// given status codes in order, repeating the last one.
+type receiver struct {
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	status := r.statuses[min(len(r.requests), len(r.statuses))-1]
+	w.WriteHeader(status)
+}
+
+func TestSlackSend(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	s := &Slack{WebhookURL: srv.URL}
+	if err := s.Send(context.Background(), testEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recv.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(recv.requests))
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(recv.bodies[0], &payload); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	for _, want := range []string{":x:", "*default/fixer-42* Failed (AgentError)", "<https://github.com/org/repo/issues/42>", "The agent exited with code 1", "cost $0.42, 12 turns, took 1m30s"} {
+		if !strings.Contains(payload["text"], want) {
+			t.Errorf("expected text to contain %q, got %q", want, payload["text"])
+		}
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusNoContent}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	w := &Webhook{URL: srv.URL, Secret: "s3cret"}
+	if err := w.Send(context.Background(), testEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recv.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(recv.requests))
+	}
+	body := recv.bodies[0]
+	if got, want := recv.requests[0].Header.Get(SignatureHeader), Sign("s3cret", body); got != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, got, want)
+	}
+	var got Event
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	if got.Task != "fixer-42" || got.Phase != "Failed" || got.CostUSD != "0.42" || got.SourceURL != "https://github.com/org/repo/issues/42" {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+}
+
+func TestWebhookSendUnsigned(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	w := &Webhook{URL: srv.URL}
+	if err := w.Send(context.Background(), testEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sig := recv.requests[0].Header.Get(SignatureHeader); sig != "" {
+		t.Errorf("expected no signature, got %q", sig)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{status: http.StatusBadRequest, permanent: true},
+		{status: http.StatusNotFound, permanent: true},
+		{status: http.StatusRequestTimeout},
+		{status: http.StatusTooManyRequests},
+		{status: http.StatusInternalServerError},
+		{status: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(&receiver{statuses: []int{tt.status}})
+			defer srv.Close()
+
+			err := (&Webhook{URL: srv.URL}).Send(context.Background(), testEvent())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if IsPermanent(err) != tt.permanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", err, IsPermanent(err), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestSendUnreachable(t *testing.T) {
+	srv := httptest.NewServer(&receiver{statuses: []int{http.StatusOK}})
+	srv.Close()
+
+	err := (&Slack{WebhookURL: srv.URL}).Send(context.Background(), testEvent())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if IsPermanent(err) {
+		t.Errorf("expected an unreachable receiver to be retried, got %v", err)
+	}
+}
+
+func TestGitHubCommentSend(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusCreated}}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	g := &GitHubComment{Owner: "org", Repo: "repo", Number: 42, Token: "ghp_test", BaseURL: srv.URL}
+	if err := g.Send(context.Background(), testEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := recv.requests[0]
+	if req.URL.Path != "/repos/org/repo/issues/42/comments" {
+		t.Errorf("unexpected path %s", req.URL.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "token ghp_test" {
+		t.Errorf("Authorization = %q", got)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(recv.bodies[0], &payload); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	for _, want := range []string{"**Task `fixer-42` Failed** (AgentError)", "The agent exited with code 1", "cost $0.42 · 12 turns · took 1m30s", "axon logs fixer-42 -n default"} {
+		if !strings.Contains(payload["body"], want) {
+			t.Errorf("expected body to contain %q, got %q", want, payload["body"])
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 5, want: 160 * time.Second},
+		{attempts: 6, want: 5 * time.Minute},
+		{attempts: 100, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
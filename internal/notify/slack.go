@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Slack posts events to a Slack-compatible incoming webhook.
+type Slack struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// Send implements Sender.
+func (s *Slack) Send(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(map[string]string{"text": slackText(event)})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("encoding Slack message: %w", err)}
+	}
+	return post(ctx, s.Client, s.WebhookURL, "application/json", body, nil)
+}
+
+// slackText formats the event in Slack's mrkdwn.
+func slackText(event *Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s Task *%s/%s* %s", phaseEmoji(event.Phase), event.Namespace, event.Task, event.Phase)
+	if event.FailureReason != "" {
+		fmt.Fprintf(&b, " (%s)", event.FailureReason)
+	}
+	if event.SourceURL != "" {
+		fmt.Fprintf(&b, " for <%s>", event.SourceURL)
+	}
+	if event.Message != "" {
+		fmt.Fprintf(&b, "\n%s", event.Message)
+	}
+	if details := event.details(); len(details) > 0 {
+		fmt.Fprintf(&b, "\n_%s_", strings.Join(details, ", "))
+	}
+	return b.String()
+}
+
+// phaseEmoji returns the emoji shortcode shown in front of the phase.
+func phaseEmoji(phase string) string {
+	switch phase {
+	case "Succeeded":
+		return ":white_check_mark:"
+	case "Failed":
+		return ":x:"
+	case "AwaitingInput", "PendingApproval":
+		return ":raising_hand:"
+	default:
+		return ":information_source:"
+	}
+}
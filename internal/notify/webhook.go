@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SignatureHeader is the header carrying the HMAC-SHA256 signature of a
+// webhook payload.
+const SignatureHeader = "X-Axon-Signature-256"
+
+// Webhook posts events as JSON to a URL.
+type Webhook struct {
+	URL string
+
+	// Secret, if set, is used to sign the payload.
+	Secret string
+
+	Client *http.Client
+}
+
+// Send implements Sender.
+func (w *Webhook) Send(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("encoding webhook payload: %w", err)}
+	}
+
+	header := http.Header{}
+	if w.Secret != "" {
+		header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+	return post(ctx, w.Client, w.URL, "application/json", body, header)
+}
+
+// Sign returns the signature of body sent in the SignatureHeader, in the
+// form sha256=<hex digest>, so receivers can verify the payload.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,117 @@
+package source
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ResponseCache caches GitHub API responses by URL, so that requests for
+// them can be made conditional. GitHub does not count conditional requests
+// answered with 304 Not Modified against the rate limit, so polling
+// unchanged issues is free. It is safe for concurrent use.
+type ResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+type cachedResponse struct {
+	ETag string `json:"etag"`
+	Link string `json:"link,omitempty"`
+	Body []byte `json:"body"`
+
+	// UsedAt is the hour the response was last used in. It is not more
+	// precise so that using the cache does not change its encoding.
+	UsedAt time.Time `json:"usedAt"`
+}
+
+// NewResponseCache returns an empty ResponseCache.
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{entries: map[string]*cachedResponse{}}
+}
+
+func (c *ResponseCache) get(url string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[url]
+	if ok {
+		e.UsedAt = time.Now().Truncate(time.Hour)
+	}
+	return e, ok
+}
+
+func (c *ResponseCache) put(url string, e *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.UsedAt = time.Now().Truncate(time.Hour)
+	c.entries[url] = e
+}
+
+// Len returns the number of cached responses.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Prune removes the responses that were not used since the given time,
+// like those of issues that were closed, and returns how many it removed.
+func (c *ResponseCache) Prune(unusedSince time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	removed := 0
+	for url, e := range c.entries {
+		if e.UsedAt.Before(unusedSince) {
+			delete(c.entries, url)
+			removed++
+		}
+	}
+	return removed
+}
+
+// MarshalBinary encodes the cached responses as gzipped JSON. The encoding
+// of the same responses is always the same.
+func (c *ResponseCache) MarshalBinary() ([]byte, error) {
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("encoding response cache: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("compressing response cache: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressing response cache: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the cached responses with those encoded by
+// MarshalBinary.
+func (c *ResponseCache) UnmarshalBinary(data []byte) error {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decompressing response cache: %w", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return fmt.Errorf("decompressing response cache: %w", err)
+	}
+
+	entries := map[string]*cachedResponse{}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return fmt.Errorf("decoding response cache: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = entries
+	return nil
+}
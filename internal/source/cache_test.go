@@ -0,0 +1,58 @@
+package source
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestResponseCachePrune(t *testing.T) {
+	c := NewResponseCache()
+	c.put("a", &cachedResponse{ETag: `"a"`})
+	c.put("b", &cachedResponse{ETag: `"b"`})
+	c.entries["b"].UsedAt = time.Now().Add(-2 * time.Hour)
+
+	if removed := c.Prune(time.Now().Add(-time.Hour)); removed != 1 {
+		t.Fatalf("expected 1 response to be pruned, got %d", removed)
+	}
+	if _, ok := c.get("b"); ok {
+		t.Error("expected the unused response to be pruned")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("expected the used response to be kept")
+	}
+}
+
+func TestResponseCacheMarshalBinary(t *testing.T) {
+	c := NewResponseCache()
+	c.put("https://api.github.com/repos/o/r/issues?page=1", &cachedResponse{ETag: `"1"`, Link: `<...>; rel="next"`, Body: []byte(`[{"number":1}]`)})
+	c.put("https://api.github.com/repos/o/r/issues?page=2", &cachedResponse{ETag: `"2"`, Body: []byte(`[]`)})
+
+	data, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Error("expected the same responses to encode the same")
+	}
+
+	restored := NewResponseCache()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.Len() != 2 {
+		t.Fatalf("expected 2 responses, got %d", restored.Len())
+	}
+	e, ok := restored.get("https://api.github.com/repos/o/r/issues?page=1")
+	if !ok || e.ETag != `"1"` || e.Link != `<...>; rel="next"` || string(e.Body) != `[{"number":1}]` {
+		t.Errorf("unexpected response: %+v", e)
+	}
+
+	if err := restored.UnmarshalBinary([]byte("not gzip")); err == nil {
+		t.Error("expected an error for corrupt data")
+	}
+}
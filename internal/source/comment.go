@@ -0,0 +1,37 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// TaskResult describes a finished Task for the comment posted on the item
+// it was spawned for.
+type TaskResult struct {
+	Number        int
+	Task          string
+	Namespace     string
+	Phase         string
+	Message       string
+	FailureReason string
+	CostUSD       string
+	NumTurns      int32
+	Duration      string
+}
+
+// RenderComment renders a completion comment for the given Task result
+// using the provided template.
+func RenderComment(commentTemplate string, result TaskResult) (string, error) {
+	tmpl, err := template.New("comment").Parse(commentTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parsing comment template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, result); err != nil {
+		return "", fmt.Errorf("executing comment template: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,34 @@
+package source
+
+import (
+	"testing"
+)
+
+func TestRenderComment(t *testing.T) {
+	result := TaskResult{
+		Number:        42,
+		Task:          "fixer-42",
+		Namespace:     "default",
+		Phase:         "Failed",
+		FailureReason: "OOMKilled",
+		CostUSD:       "1.25",
+		NumTurns:      30,
+		Duration:      "12m3s",
+	}
+
+	got, err := RenderComment("Task `{{.Task}}` {{.Phase}}{{if .FailureReason}} ({{.FailureReason}}){{end}} after {{.Duration}}, ${{.CostUSD}}", result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Task `fixer-42` Failed (OOMKilled) after 12m3s, $1.25"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderCommentErrors(t *testing.T) {
+	for _, tmpl := range []string{"{{.Task", "{{.Title}}"} {
+		if _, err := RenderComment(tmpl, TaskResult{}); err == nil {
+			t.Errorf("expected an error for %q", tmpl)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,13 +9,19 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	defaultBaseURL = "https://api.github.com"
 
+	// githubJSON is the media type of GitHub API responses, unless another
+	// one is requested.
+	githubJSON = "application/vnd.github.v3+json"
+
 	// maxPages limits the number of pages fetched from the GitHub API to prevent
 	// unbounded API calls for repositories with many issues.
 	maxPages = 10
@@ -34,15 +41,80 @@ type GitHubSource struct {
 	Token         string
 	BaseURL       string
 	Client        *http.Client
+
+	// Cache, if set, makes requests for previously fetched resources
+	// conditional.
+	Cache *ResponseCache
+
+	// Index, if set, holds the issues discovered so far, so that only
+	// issues updated since are fetched.
+	Index *ItemIndex
+
+	// Assignee filters issues by the login of a user they are assigned to.
+	// "@me" stands for the user the Token belongs to, "*" for any user and
+	// "none" for unassigned issues.
+	Assignee string
+	// Author filters issues by the login of the user who opened them.
+	Author string
+	// AuthorAssociations filters issues by the association of their author
+	// with the repository, e.g. MEMBER.
+	AuthorAssociations []string
+	// Milestone filters issues by the title of their milestone. "*" stands
+	// for any milestone and "none" for issues without one.
+	Milestone string
+	// CreatedWithin and UpdatedWithin, if not zero, filter issues by how
+	// long ago they were created and last updated.
+	CreatedWithin time.Duration
+	UpdatedWithin time.Duration
+	// Query is a GitHub search query the issues must also match. With a
+	// Query, issues are discovered with the search API.
+	Query string
+
+	// GraphQL discovers issues using the GraphQL API, which returns them
+	// along with their comments and linked pull requests in one paginated
+	// query, instead of listing them and then fetching the comments of
+	// each one with the REST API.
+	GraphQL bool
+
+	rateLimit RateLimit
+	requests  int
+
+	// me is the login of the user the Token belongs to, once resolved.
+	me string
+}
+
+// RateLimit is the state of the GitHub API rate limit.
+type RateLimit struct {
+	// Limit is the number of requests allowed per window.
+	Limit int
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+	// Reset is when the current window ends.
+	Reset time.Time
 }
 
 type githubIssue struct {
-	Number      int           `json:"number"`
-	Title       string        `json:"title"`
-	Body        string        `json:"body"`
-	HTMLURL     string        `json:"html_url"`
-	Labels      []githubLabel `json:"labels"`
-	PullRequest *struct{}     `json:"pull_request,omitempty"`
+	Number            int              `json:"number"`
+	Title             string           `json:"title"`
+	Body              string           `json:"body"`
+	HTMLURL           string           `json:"html_url"`
+	State             string           `json:"state,omitempty"`
+	User              githubUser       `json:"user"`
+	AuthorAssociation string           `json:"author_association,omitempty"`
+	Labels            []githubLabel    `json:"labels"`
+	Assignees         []githubUser     `json:"assignees,omitempty"`
+	Milestone         *githubMilestone `json:"milestone,omitempty"`
+	PullRequest       *struct{}        `json:"pull_request,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+
+	// LinkedPRs are the pull requests that close the issue. Only the
+	// GraphQL API returns them.
+	LinkedPRs []int `json:"linked_prs,omitempty"`
+
+	// comments are the comments on the issue, if the API returned them
+	// along with it.
+	comments []IssueComment
 }
 
 type githubLabel struct {
@@ -50,7 +122,29 @@ type githubLabel struct {
 }
 
 type githubComment struct {
-	Body string `json:"body"`
+	Body              string     `json:"body"`
+	User              githubUser `json:"user"`
+	AuthorAssociation string     `json:"author_association"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubMilestone struct {
+	Title string `json:"title"`
+}
+
+// IssueComment is a comment on a GitHub issue or pull request.
+type IssueComment struct {
+	Author    string    `json:"author,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// AuthorAssociation is the association of the author with the
+	// repository, like "MEMBER". It is only set by ListComments.
+	AuthorAssociation string `json:"authorAssociation,omitempty"`
 }
 
 func (s *GitHubSource) baseURL() string {
@@ -67,8 +161,117 @@ func (s *GitHubSource) httpClient() *http.Client {
 	return http.DefaultClient
 }
 
-// Discover fetches issues from GitHub and returns them as WorkItems.
+// do sends the request with the source's credentials and records the
+// response in the GitHub API metrics. GET requests go through the Cache, if
+// set: a 304 Not Modified response is replaced by the cached response.
+func (s *GitHubSource) do(req *http.Request, operation string) (*http.Response, error) {
+	if s.Token != "" {
+		req.Header.Set("Authorization", "token "+s.Token)
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", githubJSON)
+	}
+
+	// Listings of the updates since a time are not cached, their URL
+	// changes with every listing, nor are logs, which are large and only
+	// fetched once. Other media types of a resource are cached separately.
+	url := req.URL.String()
+	if accept := req.Header.Get("Accept"); accept != githubJSON {
+		url += " " + accept
+	}
+	cacheable := s.Cache != nil && req.Method == http.MethodGet && !req.URL.Query().Has("since") && !strings.HasSuffix(req.URL.Path, "/logs")
+	var cached *cachedResponse
+	if cacheable {
+		if e, ok := s.Cache.get(url); ok {
+			cached = e
+			req.Header.Set("If-None-Match", e.ETag)
+		}
+	}
+
+	resp, err := s.httpClient().Do(req)
+	recordGitHubResponse(operation, resp, err)
+	if err != nil {
+		return nil, err
+	}
+	s.observeRateLimit(resp)
+
+	switch {
+	case cached != nil && resp.StatusCode == http.StatusNotModified:
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		if resp.Header.Get("Link") == "" && cached.Link != "" {
+			resp.Header.Set("Link", cached.Link)
+		}
+	case cacheable && resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+		s.Cache.put(url, &cachedResponse{ETag: resp.Header.Get("ETag"), Link: resp.Header.Get("Link"), Body: body})
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return resp, nil
+}
+
+// observeRateLimit records the rate limit state reported by the response
+// and counts the requests that were charged against it.
+func (s *GitHubSource) observeRateLimit(resp *http.Response) {
+	if resp.StatusCode != http.StatusNotModified {
+		s.requests++
+	}
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	s.rateLimit = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+}
+
+// RateLimit returns the rate limit state of the last response that
+// reported it, and whether there was one.
+func (s *GitHubSource) RateLimit() (RateLimit, bool) {
+	return s.rateLimit, s.rateLimit.Limit > 0
+}
+
+// Requests returns the number of requests that were charged against the
+// rate limit, i.e. all requests but those answered from the Cache.
+func (s *GitHubSource) Requests() int {
+	return s.requests
+}
+
+// Discover fetches issues from GitHub and returns them as WorkItems. With
+// an Index, only the issues updated since the last discovery are fetched.
 func (s *GitHubSource) Discover(ctx context.Context) ([]WorkItem, error) {
+	if s.Assignee == "@me" && s.me == "" {
+		me, err := s.authenticatedUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.me = me
+	}
+
+	if s.Query != "" {
+		// An incremental search cannot tell issues that stopped matching
+		// the query from those that did not change, so search anew
+		return s.discoverIncremental(ctx, NewItemIndex())
+	}
+	if s.Index != nil {
+		return s.discoverIncremental(ctx, s.Index)
+	}
+	if s.GraphQL {
+		// A new index makes for a full listing
+		return s.discoverIncremental(ctx, NewItemIndex())
+	}
+
 	issues, err := s.fetchAllIssues(ctx)
 	if err != nil {
 		return nil, err
@@ -78,34 +281,68 @@ func (s *GitHubSource) Discover(ctx context.Context) ([]WorkItem, error) {
 
 	var items []WorkItem
 	for _, issue := range issues {
-		var labels []string
-		for _, l := range issue.Labels {
-			labels = append(labels, l.Name)
-		}
-
-		comments, err := s.fetchComments(ctx, issue.Number)
+		comments, err := s.ListComments(ctx, issue.Number, time.Time{})
 		if err != nil {
 			return nil, fmt.Errorf("fetching comments for issue #%d: %w", issue.Number, err)
 		}
+		items = append(items, issue.workItem(comments))
+	}
 
-		kind := "Issue"
-		if issue.PullRequest != nil {
-			kind = "PR"
-		}
+	if err := s.addPullRequestDetails(ctx, items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
-		items = append(items, WorkItem{
-			ID:       strconv.Itoa(issue.Number),
-			Number:   issue.Number,
-			Title:    issue.Title,
-			Body:     issue.Body,
-			URL:      issue.HTMLURL,
-			Labels:   labels,
-			Comments: comments,
-			Kind:     kind,
-		})
+// workItem returns the issue with the given comments as a WorkItem. Only
+// the first maxCommentBytes of comments are included.
+func (issue *githubIssue) workItem(comments []IssueComment) WorkItem {
+	var labels []string
+	for _, l := range issue.Labels {
+		labels = append(labels, l.Name)
 	}
 
-	return items, nil
+	var assignees []string
+	for _, a := range issue.Assignees {
+		assignees = append(assignees, a.Login)
+	}
+
+	var milestone string
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Title
+	}
+
+	kind := "Issue"
+	if issue.PullRequest != nil {
+		kind = "PR"
+	}
+
+	var parts []string
+	totalBytes := 0
+	for i, c := range comments {
+		totalBytes += len(c.Body)
+		if totalBytes > maxCommentBytes {
+			comments = comments[:i]
+			break
+		}
+		parts = append(parts, c.Body)
+	}
+
+	return WorkItem{
+		ID:          strconv.Itoa(issue.Number),
+		Number:      issue.Number,
+		Title:       issue.Title,
+		Body:        issue.Body,
+		URL:         issue.HTMLURL,
+		Labels:      labels,
+		Comments:    strings.Join(parts, "\n---\n"),
+		Kind:        kind,
+		Author:      issue.User.Login,
+		Assignees:   assignees,
+		Milestone:   milestone,
+		LinkedPRs:   issue.LinkedPRs,
+		CommentList: comments,
+	}
 }
 
 func (s *GitHubSource) resolvedTypes() map[string]struct{} {
@@ -120,8 +357,11 @@ func (s *GitHubSource) resolvedTypes() map[string]struct{} {
 	return m
 }
 
+// filterItems applies the filters that are not applied by the GitHub API,
+// or not by every request. It applies all of them but state and labels.
 func (s *GitHubSource) filterItems(issues []githubIssue) []githubIssue {
 	types := s.resolvedTypes()
+	now := time.Now()
 
 	excluded := make(map[string]struct{}, len(s.ExcludeLabels))
 	for _, l := range s.ExcludeLabels {
@@ -141,6 +381,10 @@ func (s *GitHubSource) filterItems(issues []githubIssue) []githubIssue {
 			}
 		}
 
+		if !s.matchesFilters(&issue, now) {
+			continue
+		}
+
 		// Exclude-label filtering
 		skip := false
 		for _, l := range issue.Labels {
@@ -156,6 +400,89 @@ func (s *GitHubSource) filterItems(issues []githubIssue) []githubIssue {
 	return filtered
 }
 
+// matchesFilters reports whether the issue passes the assignee, author,
+// milestone and time window filters.
+func (s *GitHubSource) matchesFilters(issue *githubIssue, now time.Time) bool {
+	switch assignee := s.assignee(); assignee {
+	case "":
+	case "*":
+		if len(issue.Assignees) == 0 {
+			return false
+		}
+	case "none":
+		if len(issue.Assignees) > 0 {
+			return false
+		}
+	default:
+		if !slices.ContainsFunc(issue.Assignees, func(u githubUser) bool { return strings.EqualFold(u.Login, assignee) }) {
+			return false
+		}
+	}
+
+	if s.Author != "" && !strings.EqualFold(issue.User.Login, s.Author) {
+		return false
+	}
+	if len(s.AuthorAssociations) > 0 && !slices.Contains(s.AuthorAssociations, issue.AuthorAssociation) {
+		return false
+	}
+
+	switch s.Milestone {
+	case "":
+	case "*":
+		if issue.Milestone == nil {
+			return false
+		}
+	case "none":
+		if issue.Milestone != nil {
+			return false
+		}
+	default:
+		if issue.Milestone == nil || issue.Milestone.Title != s.Milestone {
+			return false
+		}
+	}
+
+	if s.CreatedWithin > 0 && issue.CreatedAt.Before(now.Add(-s.CreatedWithin)) {
+		return false
+	}
+	if s.UpdatedWithin > 0 && issue.UpdatedAt.Before(now.Add(-s.UpdatedWithin)) {
+		return false
+	}
+	return true
+}
+
+// assignee returns the login the Assignee filter stands for.
+func (s *GitHubSource) assignee() string {
+	if s.Assignee == "@me" {
+		return s.me
+	}
+	return s.Assignee
+}
+
+// authenticatedUser returns the login of the user the Token belongs to.
+func (s *GitHubSource) authenticatedUser(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL()+"/user", nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, "get_user")
+	if err != nil {
+		return "", fmt.Errorf("fetching authenticated user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", githubAPIError(resp)
+	}
+
+	var user githubUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("decoding user: %w", err)
+	}
+	return user.Login, nil
+}
+
 func (s *GitHubSource) fetchAllIssues(ctx context.Context) ([]githubIssue, error) {
 	var allIssues []githubIssue
 
@@ -178,18 +505,36 @@ func (s *GitHubSource) buildIssuesURL() string {
 
 	params := url.Values{}
 	params.Set("per_page", "100")
+	s.setListFilters(params)
 
-	state := s.State
-	if state == "" {
-		state = "open"
-	}
-	params.Set("state", state)
+	return u + "?" + params.Encode()
+}
 
+// setListFilters sets the parameters of an issue listing that filter by
+// state, labels, assignee, author and milestone. The API only filters by
+// milestone number, so milestone titles are filtered client-side.
+func (s *GitHubSource) setListFilters(params url.Values) {
+	params.Set("state", s.state())
 	if len(s.Labels) > 0 {
 		params.Set("labels", strings.Join(s.Labels, ","))
 	}
+	if assignee := s.assignee(); assignee != "" {
+		params.Set("assignee", assignee)
+	}
+	if s.Author != "" {
+		params.Set("creator", s.Author)
+	}
+	if s.Milestone == "*" || s.Milestone == "none" {
+		params.Set("milestone", s.Milestone)
+	}
+}
 
-	return u + "?" + params.Encode()
+// state returns the state of the issues to discover.
+func (s *GitHubSource) state() string {
+	if s.State == "" {
+		return "open"
+	}
+	return s.State
 }
 
 func (s *GitHubSource) fetchIssuesPage(ctx context.Context, pageURL string) ([]githubIssue, string, error) {
@@ -198,20 +543,14 @@ func (s *GitHubSource) fetchIssuesPage(ctx context.Context, pageURL string) ([]g
 		return nil, "", fmt.Errorf("creating request: %w", err)
 	}
 
-	if s.Token != "" {
-		req.Header.Set("Authorization", "token "+s.Token)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient().Do(req)
+	resp, err := s.do(req, "list_issues")
 	if err != nil {
 		return nil, "", fmt.Errorf("fetching issues: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, "", fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, "", githubAPIError(resp)
 	}
 
 	var issues []githubIssue
@@ -224,46 +563,171 @@ func (s *GitHubSource) fetchIssuesPage(ctx context.Context, pageURL string) ([]g
 	return issues, nextURL, nil
 }
 
-func (s *GitHubSource) fetchComments(ctx context.Context, issueNumber int) (string, error) {
-	u := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments?per_page=100", s.baseURL(), s.Owner, s.Repo, issueNumber)
+// ListComments returns the comments on the given issue or pull request that
+// were created or updated at or after since, oldest first.
+func (s *GitHubSource) ListComments(ctx context.Context, number int, since time.Time) ([]IssueComment, error) {
+	params := url.Values{}
+	params.Set("per_page", "100")
+	if !since.IsZero() {
+		params.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	u := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments?%s", s.baseURL(), s.Owner, s.Repo, number, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	if s.Token != "" {
-		req.Header.Set("Authorization", "token "+s.Token)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient().Do(req)
+	resp, err := s.do(req, "list_comments")
 	if err != nil {
-		return "", fmt.Errorf("fetching comments: %w", err)
+		return nil, fmt.Errorf("fetching comments: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return "", fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, githubAPIError(resp)
 	}
 
 	var comments []githubComment
 	if err := json.NewDecoder(resp.Body).Decode(&comments); err != nil {
-		return "", fmt.Errorf("decoding comments: %w", err)
+		return nil, fmt.Errorf("decoding comments: %w", err)
 	}
 
-	var parts []string
-	totalBytes := 0
+	result := make([]IssueComment, 0, len(comments))
 	for _, c := range comments {
-		totalBytes += len(c.Body)
-		if totalBytes > maxCommentBytes {
-			break
+		result = append(result, IssueComment{
+			Author:            c.User.Login,
+			AuthorAssociation: c.AuthorAssociation,
+			Body:              c.Body,
+			CreatedAt:         c.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// AuthorizedComment reports whether commands in the comment, like answers
+// to an agent's question, are accepted: its author is one of users or has
+// one of the associations, and is not the user the Token belongs to. If
+// neither users nor associations are set, the owners, members and
+// collaborators of the repository are accepted.
+func (s *GitHubSource) AuthorizedComment(ctx context.Context, c IssueComment, users, associations []string) (bool, error) {
+	if s.me == "" {
+		me, err := s.authenticatedUser(ctx)
+		if err != nil {
+			return false, err
 		}
-		parts = append(parts, c.Body)
+		s.me = me
+	}
+	return authorizedAuthor(s.me, c.Author, c.AuthorAssociation, users, associations), nil
+}
+
+// CreateComment posts a comment on the given issue or pull request.
+func (s *GitHubSource) CreateComment(ctx context.Context, number int, body string) error {
+	path := fmt.Sprintf("issues/%d/comments", number)
+	if err := s.write(ctx, http.MethodPost, path, map[string]string{"body": body}, "create_comment", http.StatusCreated); err != nil {
+		return fmt.Errorf("creating comment: %w", err)
+	}
+	return nil
+}
+
+// AddLabels adds labels to the given issue or pull request.
+func (s *GitHubSource) AddLabels(ctx context.Context, number int, labels []string) error {
+	path := fmt.Sprintf("issues/%d/labels", number)
+	if err := s.write(ctx, http.MethodPost, path, map[string][]string{"labels": labels}, "add_labels", http.StatusOK); err != nil {
+		return fmt.Errorf("adding labels: %w", err)
+	}
+	return nil
+}
+
+// RemoveLabel removes a label from the given issue or pull request. It is
+// not an error if the item does not have the label.
+func (s *GitHubSource) RemoveLabel(ctx context.Context, number int, label string) error {
+	path := fmt.Sprintf("issues/%d/labels/%s", number, url.PathEscape(label))
+	if err := s.write(ctx, http.MethodDelete, path, nil, "remove_label", http.StatusOK, http.StatusNotFound); err != nil {
+		return fmt.Errorf("removing label %q: %w", label, err)
+	}
+	return nil
+}
+
+// AddAssignees assigns users to the given issue or pull request.
+func (s *GitHubSource) AddAssignees(ctx context.Context, number int, assignees []string) error {
+	path := fmt.Sprintf("issues/%d/assignees", number)
+	if err := s.write(ctx, http.MethodPost, path, map[string][]string{"assignees": assignees}, "add_assignees", http.StatusCreated); err != nil {
+		return fmt.Errorf("adding assignees: %w", err)
+	}
+	return nil
+}
+
+// Close closes the given issue or pull request.
+func (s *GitHubSource) Close(ctx context.Context, number int) error {
+	path := fmt.Sprintf("issues/%d", number)
+	if err := s.write(ctx, http.MethodPatch, path, map[string]string{"state": "closed"}, "close_issue", http.StatusOK); err != nil {
+		return fmt.Errorf("closing: %w", err)
 	}
+	return nil
+}
 
-	return strings.Join(parts, "\n---\n"), nil
+// write sends a request with the JSON encoded payload, if any, to the path
+// below the repository and checks that the response has one of the
+// expected statuses.
+func (s *GitHubSource) write(ctx context.Context, method, path string, payload any, operation string, expected ...int) error {
+	u := fmt.Sprintf("%s/repos/%s/%s/%s", s.baseURL(), s.Owner, s.Repo, path)
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.do(req, operation)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !slices.Contains(expected, resp.StatusCode) {
+		return githubAPIError(resp)
+	}
+	return nil
+}
+
+// RateLimitError is returned when a request is rejected by the GitHub API
+// rate limit.
+type RateLimitError struct {
+	// Reset is when requests are allowed again.
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit exceeded until %s", e.Reset.Format(time.RFC3339))
+}
+
+// githubAPIError returns the error for an unsuccessful GitHub API response.
+func githubAPIError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		// Secondary rate limits set Retry-After, the primary rate limit
+		// sets the time it resets at
+		if after, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return &RateLimitError{Reset: time.Now().Add(time.Duration(after) * time.Second)}
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			return &RateLimitError{Reset: time.Unix(reset, 0)}
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body))
 }
 
 var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
@@ -0,0 +1,306 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultCommand starts command comments if no Command is set.
+	DefaultCommand = "/axon"
+
+	// acknowledgeReaction is the reaction acknowledging a command.
+	acknowledgeReaction = "eyes"
+)
+
+// defaultCommandAuthorAssociations are the associations of the users whose
+// commands are accepted if neither AuthorAssociations nor Users are set.
+var defaultCommandAuthorAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// GitHubCommentsSource discovers commands in the comments on the issues and
+// pull requests of a GitHub repository, like "/axon review focus on tests".
+// Every command is a WorkItem for the issue or pull request it was made on,
+// identified by the ID of the comment. Commands are acknowledged with a
+// reaction once a Task was created for them, and are not discovered again.
+//
+// The issue filters of the GitHubSource apply to the items commands are
+// made on.
+type GitHubCommentsSource struct {
+	*GitHubSource
+
+	// Command starts the first line of the comments that are commands.
+	// Defaults to DefaultCommand.
+	Command string
+	// AuthorAssociations and Users are the associations with the repository
+	// and the logins of the users whose commands are accepted.
+	AuthorAssociations []string
+	Users              []string
+
+	// Since is the time the comments updated since are discovered. Discover
+	// advances it to where the next discovery continues. If it is zero, the
+	// first discovery starts at the time it runs rather than scanning the
+	// history of the repository.
+	Since time.Time
+}
+
+type githubIssueComment struct {
+	ID                int64      `json:"id"`
+	Body              string     `json:"body"`
+	IssueURL          string     `json:"issue_url"`
+	User              githubUser `json:"user"`
+	AuthorAssociation string     `json:"author_association"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	Reactions         struct {
+		Eyes int `json:"eyes"`
+	} `json:"reactions"`
+}
+
+type githubReaction struct {
+	User githubUser `json:"user"`
+}
+
+// Discover fetches the comments updated since Since and returns the
+// commands among them that were not acknowledged yet.
+func (s *GitHubCommentsSource) Discover(ctx context.Context) ([]WorkItem, error) {
+	if s.me == "" {
+		me, err := s.authenticatedUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.me = me
+	}
+
+	start := time.Now()
+	since := s.Since
+	if since.IsZero() {
+		since = start
+	}
+	pageURL := s.buildRepoCommentsURL(since)
+	var commands []githubIssueComment
+	for page := 0; ; page++ {
+		if page == maxPages {
+			// Continue after the last comment in the next discovery
+			break
+		}
+		comments, nextURL, err := s.fetchRepoCommentsPage(ctx, pageURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			since = c.UpdatedAt
+			if _, ok := s.parseCommand(c.Body); ok && s.authorized(&c) {
+				commands = append(commands, c)
+			}
+		}
+		pageURL = nextURL
+		if pageURL == "" {
+			since = start.Add(-sinceOverlap)
+			break
+		}
+	}
+
+	var items []WorkItem
+	threads := map[int]*WorkItem{}
+	for _, c := range commands {
+		acknowledged, err := s.acknowledged(ctx, &c)
+		if err != nil {
+			return nil, fmt.Errorf("fetching reactions to comment %d: %w", c.ID, err)
+		}
+		if acknowledged {
+			continue
+		}
+
+		number, err := strconv.Atoi(c.IssueURL[strings.LastIndex(c.IssueURL, "/")+1:])
+		if err != nil {
+			return nil, fmt.Errorf("parsing the issue URL of comment %d: %w", c.ID, err)
+		}
+		thread, ok := threads[number]
+		if !ok {
+			thread, err = s.fetchThread(ctx, number)
+			if err != nil {
+				return nil, fmt.Errorf("fetching issue #%d: %w", number, err)
+			}
+			threads[number] = thread
+		}
+		if thread == nil {
+			// Filtered out
+			continue
+		}
+
+		item := *thread
+		item.ID = strconv.FormatInt(c.ID, 10)
+		item.Args, _ = s.parseCommand(c.Body)
+		item.Requester = c.User.Login
+		items = append(items, item)
+	}
+
+	s.Since = since
+	return items, nil
+}
+
+// Acknowledge reacts to the comment of the command the item was discovered
+// for, so that it is not discovered again.
+func (s *GitHubCommentsSource) Acknowledge(ctx context.Context, item WorkItem) error {
+	path := fmt.Sprintf("issues/comments/%s/reactions", item.ID)
+	payload := map[string]string{"content": acknowledgeReaction}
+	if err := s.write(ctx, http.MethodPost, path, payload, "create_reaction", http.StatusOK, http.StatusCreated); err != nil {
+		return fmt.Errorf("reacting to comment %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+// parseCommand returns the arguments of the command the first line of the
+// comment is, and whether it is one.
+func (s *GitHubCommentsSource) parseCommand(body string) (string, bool) {
+	command := s.Command
+	if command == "" {
+		command = DefaultCommand
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), command)
+	if !ok {
+		return "", false
+	}
+	// "/axonfix" is not a command
+	if r, _ := utf8.DecodeRuneInString(rest); rest != "" && !unicode.IsSpace(r) {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// authorized reports whether commands of the comment's author are
+// accepted. Commands of the user the Token belongs to are not, so that the
+// spawner cannot trigger itself.
+func (s *GitHubCommentsSource) authorized(c *githubIssueComment) bool {
+	return authorizedAuthor(s.me, c.User.Login, c.AuthorAssociation, s.Users, s.AuthorAssociations)
+}
+
+// authorizedAuthor reports whether commands of the author with the login
+// and association are accepted, given the user the Token belongs to, me.
+func authorizedAuthor(me, login, association string, users, associations []string) bool {
+	if strings.EqualFold(login, me) {
+		return false
+	}
+	if slices.ContainsFunc(users, func(u string) bool { return strings.EqualFold(u, login) }) {
+		return true
+	}
+	if len(associations) == 0 && len(users) == 0 {
+		associations = defaultCommandAuthorAssociations
+	}
+	return slices.Contains(associations, association)
+}
+
+// acknowledged reports whether the user the Token belongs to reacted to
+// the comment. The reactions are only fetched if the comment has any.
+func (s *GitHubCommentsSource) acknowledged(ctx context.Context, c *githubIssueComment) (bool, error) {
+	if c.Reactions.Eyes == 0 {
+		return false, nil
+	}
+	u := fmt.Sprintf("%s/repos/%s/%s/issues/comments/%d/reactions?content=%s&per_page=100", s.baseURL(), s.Owner, s.Repo, c.ID, acknowledgeReaction)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, "list_reactions")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, githubAPIError(resp)
+	}
+
+	var reactions []githubReaction
+	if err := json.NewDecoder(resp.Body).Decode(&reactions); err != nil {
+		return false, fmt.Errorf("decoding reactions: %w", err)
+	}
+	return slices.ContainsFunc(reactions, func(r githubReaction) bool { return strings.EqualFold(r.User.Login, s.me) }), nil
+}
+
+// fetchThread returns the issue or pull request with the given number and
+// its comments as a WorkItem, or nil if it does not pass the filters.
+func (s *GitHubCommentsSource) fetchThread(ctx context.Context, number int) (*WorkItem, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/issues/%d", s.baseURL(), s.Owner, s.Repo, number)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, "get_issue")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, githubAPIError(resp)
+	}
+
+	var issue githubIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("decoding issue: %w", err)
+	}
+	if len(s.filterItems([]githubIssue{issue})) == 0 {
+		return nil, nil
+	}
+
+	comments, err := s.ListComments(ctx, number, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("fetching comments: %w", err)
+	}
+	items := []WorkItem{issue.workItem(comments)}
+	if err := s.addPullRequestDetails(ctx, items); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// buildRepoCommentsURL returns the URL listing the comments on all issues
+// and pull requests of the repository that were updated since the given
+// time, in the order they were updated in.
+func (s *GitHubCommentsSource) buildRepoCommentsURL(since time.Time) string {
+	params := url.Values{}
+	params.Set("per_page", "100")
+	params.Set("sort", "updated")
+	params.Set("direction", "asc")
+	if !since.IsZero() {
+		params.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s/repos/%s/%s/issues/comments?%s", s.baseURL(), s.Owner, s.Repo, params.Encode())
+}
+
+func (s *GitHubCommentsSource) fetchRepoCommentsPage(ctx context.Context, pageURL string) ([]githubIssueComment, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, "list_repo_comments")
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching comments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", githubAPIError(resp)
+	}
+
+	var comments []githubIssueComment
+	if err := json.NewDecoder(resp.Body).Decode(&comments); err != nil {
+		return nil, "", fmt.Errorf("decoding comments: %w", err)
+	}
+	return comments, parseNextLink(resp.Header.Get("Link")), nil
+}
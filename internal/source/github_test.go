@@ -3,11 +3,14 @@ package source
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDiscover(t *testing.T) {
@@ -123,6 +126,93 @@ func TestDiscoverStateFiltering(t *testing.T) {
 	}
 }
 
+func TestDiscoverAssigneeAuthorMilestoneFiltering(t *testing.T) {
+	var receivedQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			json.NewEncoder(w).Encode(githubUser{Login: "axon-bot"})
+		case "/repos/owner/repo/issues":
+			receivedQuery = r.URL.Query()
+			// Not filtered by the server, so the client-side filters apply
+			json.NewEncoder(w).Encode([]githubIssue{
+				{Number: 1, User: githubUser{Login: "alice"}, Assignees: []githubUser{{Login: "axon-bot"}}},
+				{Number: 2, User: githubUser{Login: "mallory"}, Assignees: []githubUser{{Login: "axon-bot"}}},
+				{Number: 3, User: githubUser{Login: "alice"}},
+				{Number: 4, User: githubUser{Login: "alice"}, Assignees: []githubUser{{Login: "axon-bot"}}, Milestone: &githubMilestone{Title: "v1.0"}},
+			})
+		default:
+			json.NewEncoder(w).Encode([]githubComment{})
+		}
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{
+		Owner:     "owner",
+		Repo:      "repo",
+		Assignee:  "@me",
+		Author:    "alice",
+		Milestone: "none",
+		BaseURL:   server.URL,
+	}
+
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for param, want := range map[string]string{"assignee": "axon-bot", "creator": "alice", "milestone": "none"} {
+		if got := receivedQuery.Get(param); got != want {
+			t.Errorf("expected %s=%s in query, got %q", param, want, got)
+		}
+	}
+	if len(items) != 1 || items[0].Number != 1 {
+		t.Errorf("expected only issue #1, got %+v", items)
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	now := time.Now()
+	issue := githubIssue{
+		User:              githubUser{Login: "Alice"},
+		AuthorAssociation: "MEMBER",
+		Assignees:         []githubUser{{Login: "bob"}},
+		Milestone:         &githubMilestone{Title: "v1.0"},
+		CreatedAt:         now.Add(-48 * time.Hour),
+		UpdatedAt:         now.Add(-time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		source GitHubSource
+		want   bool
+	}{
+		{"no filters", GitHubSource{}, true},
+		{"any assignee", GitHubSource{Assignee: "*"}, true},
+		{"unassigned", GitHubSource{Assignee: "none"}, false},
+		{"other assignee", GitHubSource{Assignee: "carol"}, false},
+		{"author ignores case", GitHubSource{Author: "alice"}, true},
+		{"other author", GitHubSource{Author: "mallory"}, false},
+		{"member", GitHubSource{AuthorAssociations: []string{"OWNER", "MEMBER"}}, true},
+		{"not a collaborator", GitHubSource{AuthorAssociations: []string{"COLLABORATOR"}}, false},
+		{"any milestone", GitHubSource{Milestone: "*"}, true},
+		{"milestone title", GitHubSource{Milestone: "v1.0"}, true},
+		{"other milestone", GitHubSource{Milestone: "v2.0"}, false},
+		{"no milestone", GitHubSource{Milestone: "none"}, false},
+		{"created within", GitHubSource{CreatedWithin: 72 * time.Hour}, true},
+		{"created before", GitHubSource{CreatedWithin: 24 * time.Hour}, false},
+		{"updated within", GitHubSource{UpdatedWithin: 2 * time.Hour}, true},
+		{"updated before", GitHubSource{UpdatedWithin: 30 * time.Minute}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.source.matchesFilters(&issue, now); got != tt.want {
+				t.Errorf("matchesFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDiscoverAuthHeader(t *testing.T) {
 	var authHeader string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -221,6 +311,89 @@ func TestDiscoverAPIError(t *testing.T) {
 	}
 }
 
+func TestDiscoverRateLimited(t *testing.T) {
+	reset := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{
+		Owner:   "owner",
+		Repo:    "repo",
+		BaseURL: server.URL,
+	}
+
+	_, err := s.Discover(context.Background())
+	var rateLimited *RateLimitError
+	if !errors.As(err, &rateLimited) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if !rateLimited.Reset.Equal(reset) {
+		t.Errorf("Reset = %v, want %v", rateLimited.Reset, reset)
+	}
+}
+
+func TestDiscoverConditionalRequests(t *testing.T) {
+	reset := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	issues := []githubIssue{
+		{Number: 1, Title: "Bug 1", Body: "Body 1"},
+		{Number: 2, Title: "Bug 2", Body: "Body 2"},
+	}
+	served := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + r.URL.Path + `"`
+		notModified := r.Header.Get("If-None-Match") == etag
+		if !notModified {
+			served++
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(4000-served))
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		if notModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		if strings.HasSuffix(r.URL.Path, "/comments") {
+			json.NewEncoder(w).Encode([]githubComment{{Body: "A comment"}})
+			return
+		}
+		json.NewEncoder(w).Encode(issues)
+	}))
+	defer server.Close()
+
+	cache := NewResponseCache()
+	for cycle := 1; cycle <= 2; cycle++ {
+		s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL, Cache: cache}
+		items, err := s.Discover(context.Background())
+		if err != nil {
+			t.Fatalf("cycle %d: unexpected error: %v", cycle, err)
+		}
+		if len(items) != 2 || items[1].Title != "Bug 2" || items[1].Comments != "A comment" {
+			t.Fatalf("cycle %d: unexpected items: %+v", cycle, items)
+		}
+
+		wantRequests := 3
+		if cycle == 2 {
+			wantRequests = 0
+		}
+		if s.Requests() != wantRequests {
+			t.Errorf("cycle %d: Requests() = %d, want %d", cycle, s.Requests(), wantRequests)
+		}
+		rl, ok := s.RateLimit()
+		if !ok || rl.Limit != 5000 || rl.Remaining != 3997 || !rl.Reset.Equal(reset) {
+			t.Errorf("cycle %d: RateLimit() = %+v, %v", cycle, rl, ok)
+		}
+	}
+	if served != 3 {
+		t.Errorf("expected 3 full responses, got %d", served)
+	}
+}
+
 func TestDiscoverEmptyResponse(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode([]githubIssue{})
@@ -244,11 +417,19 @@ func TestDiscoverEmptyResponse(t *testing.T) {
 
 func TestDiscoverComments(t *testing.T) {
 	issues := []githubIssue{
-		{Number: 42, Title: "Bug", Body: "Details", HTMLURL: "https://github.com/o/r/issues/42"},
+		{
+			Number:    42,
+			Title:     "Bug",
+			Body:      "Details",
+			HTMLURL:   "https://github.com/o/r/issues/42",
+			User:      githubUser{Login: "alice"},
+			Assignees: []githubUser{{Login: "bob"}},
+			Milestone: &githubMilestone{Title: "v1.0"},
+		},
 	}
 	comments := []githubComment{
-		{Body: "First comment"},
-		{Body: "Second comment"},
+		{Body: "First comment", User: githubUser{Login: "bob"}},
+		{Body: "Second comment", User: githubUser{Login: "alice"}},
 	}
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -279,6 +460,12 @@ func TestDiscoverComments(t *testing.T) {
 	if items[0].Comments != expected {
 		t.Errorf("expected comments %q, got %q", expected, items[0].Comments)
 	}
+	if len(items[0].CommentList) != 2 || items[0].CommentList[0].Author != "bob" || items[0].CommentList[1].Body != "Second comment" {
+		t.Errorf("unexpected comment list: %+v", items[0].CommentList)
+	}
+	if items[0].Author != "alice" || len(items[0].Assignees) != 1 || items[0].Assignees[0] != "bob" || items[0].Milestone != "v1.0" {
+		t.Errorf("unexpected author, assignees or milestone: %+v", items[0])
+	}
 }
 
 func TestDiscoverExcludeLabels(t *testing.T) {
@@ -288,12 +475,15 @@ func TestDiscoverExcludeLabels(t *testing.T) {
 		{Number: 3, Title: "Feature", Body: "Body 3", HTMLURL: "https://github.com/o/r/issues/3", Labels: []githubLabel{{Name: "enhancement"}}},
 	}
 
+	pr := &fakePullRequest{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case r.URL.Path == "/repos/owner/repo/issues":
 			json.NewEncoder(w).Encode(issues)
 		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
 			json.NewEncoder(w).Encode([]githubComment{})
+		default:
+			pr.serve(w, r)
 		}
 	}))
 	defer server.Close()
@@ -327,12 +517,15 @@ func TestDiscoverExcludeLabelsNoMatch(t *testing.T) {
 		{Number: 2, Title: "Feature", Body: "Body 2", HTMLURL: "https://github.com/o/r/issues/2", Labels: []githubLabel{{Name: "enhancement"}}},
 	}
 
+	pr := &fakePullRequest{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case r.URL.Path == "/repos/owner/repo/issues":
 			json.NewEncoder(w).Encode(issues)
 		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
 			json.NewEncoder(w).Encode([]githubComment{})
+		default:
+			pr.serve(w, r)
 		}
 	}))
 	defer server.Close()
@@ -361,12 +554,15 @@ func TestDiscoverTypesIssuesOnly(t *testing.T) {
 		{Number: 3, Title: "Feature", Body: "Body", HTMLURL: "https://github.com/o/r/issues/3"},
 	}
 
+	pr := &fakePullRequest{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case r.URL.Path == "/repos/owner/repo/issues":
 			json.NewEncoder(w).Encode(issues)
 		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
 			json.NewEncoder(w).Encode([]githubComment{})
+		default:
+			pr.serve(w, r)
 		}
 	}))
 	defer server.Close()
@@ -400,12 +596,15 @@ func TestDiscoverTypesPullsOnly(t *testing.T) {
 		{Number: 3, Title: "PR 2", Body: "Body", HTMLURL: "https://github.com/o/r/pull/3", PullRequest: &struct{}{}},
 	}
 
+	pr := &fakePullRequest{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case r.URL.Path == "/repos/owner/repo/issues":
 			json.NewEncoder(w).Encode(issues)
 		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
 			json.NewEncoder(w).Encode([]githubComment{})
+		default:
+			pr.serve(w, r)
 		}
 	}))
 	defer server.Close()
@@ -439,12 +638,15 @@ func TestDiscoverTypesBoth(t *testing.T) {
 		{Number: 3, Title: "Feature", Body: "Body", HTMLURL: "https://github.com/o/r/issues/3"},
 	}
 
+	pr := &fakePullRequest{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case r.URL.Path == "/repos/owner/repo/issues":
 			json.NewEncoder(w).Encode(issues)
 		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
 			json.NewEncoder(w).Encode([]githubComment{})
+		default:
+			pr.serve(w, r)
 		}
 	}))
 	defer server.Close()
@@ -483,12 +685,15 @@ func TestDiscoverTypesDefault(t *testing.T) {
 		{Number: 2, Title: "PR", Body: "Body", HTMLURL: "https://github.com/o/r/pull/2", PullRequest: &struct{}{}},
 	}
 
+	pr := &fakePullRequest{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case r.URL.Path == "/repos/owner/repo/issues":
 			json.NewEncoder(w).Encode(issues)
 		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
 			json.NewEncoder(w).Encode([]githubComment{})
+		default:
+			pr.serve(w, r)
 		}
 	}))
 	defer server.Close()
@@ -513,6 +718,170 @@ func TestDiscoverTypesDefault(t *testing.T) {
 	}
 }
 
+func TestListComments(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/issues/7/comments" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("since"); got != "2026-01-02T03:04:05Z" {
+			t.Errorf("since = %q, want %q", got, "2026-01-02T03:04:05Z")
+		}
+		json.NewEncoder(w).Encode([]githubComment{
+			{Body: "/answer yes", User: githubUser{Login: "alice"}, AuthorAssociation: "MEMBER", CreatedAt: since.Add(time.Minute)},
+		})
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}
+
+	comments, err := s.ListComments(context.Background(), 7, since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected 1 comment, got %d", len(comments))
+	}
+	if comments[0].Author != "alice" || comments[0].AuthorAssociation != "MEMBER" || comments[0].Body != "/answer yes" {
+		t.Errorf("unexpected comment: %+v", comments[0])
+	}
+}
+
+func TestAuthorizedComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(githubUser{Login: "axon-bot"})
+	}))
+	defer server.Close()
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}
+
+	tests := []struct {
+		name         string
+		comment      IssueComment
+		users        []string
+		associations []string
+		want         bool
+	}{
+		{name: "Member", comment: IssueComment{Author: "alice", AuthorAssociation: "MEMBER"}, want: true},
+		{name: "Outsider", comment: IssueComment{Author: "mallory", AuthorAssociation: "NONE"}, want: false},
+		{name: "Spawner", comment: IssueComment{Author: "Axon-Bot", AuthorAssociation: "OWNER"}, want: false},
+		{name: "Listed user", comment: IssueComment{Author: "bob", AuthorAssociation: "NONE"}, users: []string{"Bob"}, want: true},
+		{name: "Member not listed", comment: IssueComment{Author: "alice", AuthorAssociation: "MEMBER"}, users: []string{"bob"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.AuthorizedComment(context.Background(), tt.comment, tt.users, tt.associations)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("AuthorizedComment = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	var gotBody map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/issues/7/comments" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "token test-token" {
+			t.Errorf("unexpected Authorization header: %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", Token: "test-token", BaseURL: server.URL}
+
+	if err := s.CreateComment(context.Background(), 7, "Hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody["body"] != "Hello" {
+		t.Errorf("body = %q, want %q", gotBody["body"], "Hello")
+	}
+}
+
+func TestIssueWrites(t *testing.T) {
+	type request struct {
+		method, path string
+		body         map[string]any
+	}
+	var got []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.EscapedPath()}
+		json.NewDecoder(r.Body).Decode(&req.body)
+		got = append(got, req)
+		switch {
+		case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/repos/owner/repo/issues/7/assignees":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}
+	ctx := context.Background()
+
+	if err := s.AddLabels(ctx, 7, []string{"axon/done"}); err != nil {
+		t.Fatalf("AddLabels: %v", err)
+	}
+	if err := s.RemoveLabel(ctx, 7, "axon/in progress"); err != nil {
+		t.Fatalf("RemoveLabel: %v", err)
+	}
+	if err := s.RemoveLabel(ctx, 7, "missing"); err != nil {
+		t.Fatalf("RemoveLabel of a missing label: %v", err)
+	}
+	if err := s.AddAssignees(ctx, 7, []string{"alice"}); err != nil {
+		t.Fatalf("AddAssignees: %v", err)
+	}
+	if err := s.Close(ctx, 7); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := []struct {
+		method, path, field string
+		value               any
+	}{
+		{http.MethodPost, "/repos/owner/repo/issues/7/labels", "labels", []any{"axon/done"}},
+		{http.MethodDelete, "/repos/owner/repo/issues/7/labels/axon%2Fin%20progress", "", nil},
+		{http.MethodDelete, "/repos/owner/repo/issues/7/labels/missing", "", nil},
+		{http.MethodPost, "/repos/owner/repo/issues/7/assignees", "assignees", []any{"alice"}},
+		{http.MethodPatch, "/repos/owner/repo/issues/7", "state", "closed"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d requests, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].method != w.method || got[i].path != w.path {
+			t.Errorf("request %d = %s %s, want %s %s", i, got[i].method, got[i].path, w.method, w.path)
+		}
+		if w.field != "" && fmt.Sprint(got[i].body[w.field]) != fmt.Sprint(w.value) {
+			t.Errorf("request %d %s = %v, want %v", i, w.field, got[i].body[w.field], w.value)
+		}
+	}
+}
+
+func TestIssueWriteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"Validation Failed"}`))
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}
+	err := s.AddAssignees(context.Background(), 7, []string{"nobody"})
+	if err == nil || !strings.Contains(err.Error(), "422") {
+		t.Fatalf("expected a 422 error, got %v", err)
+	}
+}
+
 func containsParam(query, param string) bool {
 	return strings.Contains(query, param)
 }
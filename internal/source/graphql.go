@@ -0,0 +1,234 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// graphQLPageSize is the number of issues per page of a GraphQL search.
+	// It is lower than the page size of REST listings since every issue
+	// comes with its comments. GitHub returns at most 1000 search results,
+	// more than maxPages pages of them.
+	graphQLPageSize = 50
+
+	// graphQLItemFields are the fields fetched for both issues and pull
+	// requests.
+	graphQLItemFields = `
+		number
+		title
+		body
+		url
+		state
+		createdAt
+		updatedAt
+		author { login }
+		authorAssociation
+		labels(first: 100) { nodes { name } }
+		assignees(first: 20) { nodes { login } }
+		milestone { title }
+		comments(first: 100) { nodes { author { login } body createdAt } }`
+
+	searchIssuesQuery = `query($search: String!, $first: Int!, $after: String) {
+	search(type: ISSUE, query: $search, first: $first, after: $after) {
+		pageInfo { hasNextPage endCursor }
+		nodes {
+			__typename
+			... on Issue {` + graphQLItemFields + `
+				closedByPullRequestsReferences(first: 20) { nodes { number } }
+			}
+			... on PullRequest {` + graphQLItemFields + `
+			}
+		}
+	}
+}`
+)
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type graphQLSearchResponse struct {
+	Data struct {
+		Search struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []graphQLItem `json:"nodes"`
+		} `json:"search"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+type graphQLItem struct {
+	Typename          string           `json:"__typename"`
+	Number            int              `json:"number"`
+	Title             string           `json:"title"`
+	Body              string           `json:"body"`
+	URL               string           `json:"url"`
+	State             string           `json:"state"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	UpdatedAt         time.Time        `json:"updatedAt"`
+	Author            *githubUser      `json:"author"`
+	AuthorAssociation string           `json:"authorAssociation"`
+	Milestone         *githubMilestone `json:"milestone"`
+	Labels            struct {
+		Nodes []githubLabel `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []githubUser `json:"nodes"`
+	} `json:"assignees"`
+	Comments struct {
+		Nodes []graphQLComment `json:"nodes"`
+	} `json:"comments"`
+	ClosedByPullRequestsReferences struct {
+		Nodes []graphQLReference `json:"nodes"`
+	} `json:"closedByPullRequestsReferences"`
+}
+
+type graphQLComment struct {
+	Author    *githubUser `json:"author"`
+	Body      string      `json:"body"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+type graphQLReference struct {
+	Number int `json:"number"`
+}
+
+// issue returns the item in the form the REST API returns it in, with its
+// comments.
+func (n *graphQLItem) issue() githubIssue {
+	issue := githubIssue{
+		Number:    n.Number,
+		Title:     n.Title,
+		Body:      n.Body,
+		HTMLURL:   n.URL,
+		State:     strings.ToLower(n.State),
+		Labels:    n.Labels.Nodes,
+		Assignees: n.Assignees.Nodes,
+		Milestone: n.Milestone,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+
+		AuthorAssociation: n.AuthorAssociation,
+	}
+	if n.Author != nil {
+		issue.User = *n.Author
+	}
+	if n.Typename == "PullRequest" {
+		issue.PullRequest = &struct{}{}
+		// The REST API reports merged pull requests as closed
+		if issue.State == "merged" {
+			issue.State = "closed"
+		}
+	}
+	for _, pr := range n.ClosedByPullRequestsReferences.Nodes {
+		issue.LinkedPRs = append(issue.LinkedPRs, pr.Number)
+	}
+	issue.comments = make([]IssueComment, 0, len(n.Comments.Nodes))
+	for _, c := range n.Comments.Nodes {
+		comment := IssueComment{Body: c.Body, CreatedAt: c.CreatedAt}
+		if c.Author != nil {
+			comment.Author = c.Author.Login
+		}
+		issue.comments = append(issue.comments, comment)
+	}
+	return issue
+}
+
+// graphQLURL returns the URL of the GraphQL API. GitHub Enterprise Server
+// serves it at /api/graphql next to the REST API at /api/v3.
+func (s *GitHubSource) graphQLURL() string {
+	base := strings.TrimSuffix(s.baseURL(), "/")
+	if strings.HasSuffix(base, "/api/v3") {
+		return strings.TrimSuffix(base, "/v3") + "/graphql"
+	}
+	return base + "/graphql"
+}
+
+// searchUpdatedIssuesPage returns a page of the issues updated since the
+// given time from the GraphQL API, along with their comments, and the
+// cursor of the next page, if any. An empty cursor requests the first page.
+func (s *GitHubSource) searchUpdatedIssuesPage(ctx context.Context, since time.Time, cursor string) ([]githubIssue, string, error) {
+	vars := map[string]any{
+		"search": s.searchQuery(since) + " sort:updated-asc",
+		"first":  graphQLPageSize,
+	}
+	if cursor != "" {
+		vars["after"] = cursor
+	}
+	payload, err := json.Marshal(graphQLRequest{Query: searchIssuesQuery, Variables: vars})
+	if err != nil {
+		return nil, "", fmt.Errorf("encoding query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.graphQLURL(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.do(req, "search_issues")
+	if err != nil {
+		return nil, "", fmt.Errorf("searching issues: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", githubAPIError(resp)
+	}
+
+	var result graphQLSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("decoding issues: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, "", s.graphQLErrors(result.Errors)
+	}
+
+	search := result.Data.Search
+	issues := make([]githubIssue, 0, len(search.Nodes))
+	for i := range search.Nodes {
+		if search.Nodes[i].Number == 0 {
+			continue
+		}
+		issues = append(issues, search.Nodes[i].issue())
+	}
+
+	next := ""
+	if search.PageInfo.HasNextPage {
+		next = search.PageInfo.EndCursor
+	}
+	return issues, next, nil
+}
+
+// graphQLErrors returns the error for the errors of a GraphQL response. The
+// GraphQL API reports exceeding its rate limit as an error of a successful
+// response.
+func (s *GitHubSource) graphQLErrors(errs []graphQLError) error {
+	var messages []string
+	for _, e := range errs {
+		if e.Type == "RATE_LIMITED" {
+			reset := s.rateLimit.Reset
+			if reset.IsZero() {
+				reset = time.Now().Add(time.Minute)
+			}
+			return &RateLimitError{Reset: reset}
+		}
+		messages = append(messages, e.Message)
+	}
+	return fmt.Errorf("GitHub GraphQL API returned errors: %s", strings.Join(messages, "; "))
+}
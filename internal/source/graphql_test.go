@@ -0,0 +1,296 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGraphQL answers GraphQL searches for the issues of a repository like
+// the GitHub API, filtered by the type, state, label and updated
+// qualifiers of the search query, in the order they were updated in.
+type fakeGraphQL struct {
+	mu       sync.Mutex
+	items    map[int]*graphQLItem
+	pageSize int
+	errors   []graphQLError
+
+	pulls fakePullRequest
+
+	searches []string
+	auth     string
+}
+
+var labelQualifierRe = regexp.MustCompile(`label:"([^"]*)"`)
+
+func newFakeGraphQL(t *testing.T, items ...graphQLItem) (*fakeGraphQL, *httptest.Server) {
+	f := &fakeGraphQL{items: map[int]*graphQLItem{}, pageSize: 100}
+	for i := range items {
+		f.items[items[i].Number] = &items[i]
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeGraphQL) update(item graphQLItem) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[item.Number] = &item
+}
+
+func (f *fakeGraphQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// The details of pull requests are only available from the REST API
+	if f.pulls.serve(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	f.auth = r.Header.Get("Authorization")
+
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(f.errors) > 0 {
+		json.NewEncoder(w).Encode(map[string]any{"errors": f.errors})
+		return
+	}
+
+	search, _ := req.Variables["search"].(string)
+	f.searches = append(f.searches, search)
+	fields := strings.Fields(search)
+
+	var since time.Time
+	for _, field := range fields {
+		if v, ok := strings.CutPrefix(field, "updated:>="); ok {
+			since, _ = time.Parse(time.RFC3339, v)
+		}
+	}
+	var matched []graphQLItem
+	for _, item := range f.items {
+		if !since.IsZero() && item.UpdatedAt.Before(since) {
+			continue
+		}
+		if slices.Contains(fields, "is:issue") && item.Typename != "Issue" ||
+			slices.Contains(fields, "is:pr") && item.Typename != "PullRequest" ||
+			slices.Contains(fields, "is:open") && item.State != "OPEN" ||
+			slices.Contains(fields, "is:closed") && item.State == "OPEN" {
+			continue
+		}
+		ok := true
+		for _, m := range labelQualifierRe.FindAllStringSubmatch(search, -1) {
+			if !slices.ContainsFunc(item.Labels.Nodes, func(l githubLabel) bool { return l.Name == m[1] }) {
+				ok = false
+			}
+		}
+		if ok {
+			matched = append(matched, *item)
+		}
+	}
+	slices.SortFunc(matched, func(a, b graphQLItem) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+
+	start := 0
+	if after, ok := req.Variables["after"].(string); ok {
+		start, _ = strconv.Atoi(after)
+	}
+	end := min(start+f.pageSize, len(matched))
+
+	var resp graphQLSearchResponse
+	resp.Data.Search.Nodes = matched[start:end]
+	resp.Data.Search.PageInfo.HasNextPage = end < len(matched)
+	resp.Data.Search.PageInfo.EndCursor = strconv.Itoa(end)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newGraphQLIssue(number int, state string, updatedAt time.Time, labels ...string) graphQLItem {
+	item := graphQLItem{
+		Typename:  "Issue",
+		Number:    number,
+		Title:     "Issue " + strconv.Itoa(number),
+		URL:       "https://github.com/owner/repo/issues/" + strconv.Itoa(number),
+		State:     state,
+		UpdatedAt: updatedAt,
+	}
+	for _, l := range labels {
+		item.Labels.Nodes = append(item.Labels.Nodes, githubLabel{Name: l})
+	}
+	return item
+}
+
+func TestDiscoverGraphQL(t *testing.T) {
+	old := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	created := old.Add(-time.Hour)
+
+	issue := newGraphQLIssue(1, "OPEN", old, "bug")
+	issue.Body = "Details"
+	issue.Author = &githubUser{Login: "alice"}
+	issue.Assignees.Nodes = []githubUser{{Login: "bob"}}
+	issue.Milestone = &githubMilestone{Title: "v1.0"}
+	issue.Comments.Nodes = []graphQLComment{
+		{Author: &githubUser{Login: "bob"}, Body: "First comment", CreatedAt: created},
+		{Body: "Comment by a deleted user", CreatedAt: created.Add(time.Minute)},
+	}
+	issue.ClosedByPullRequestsReferences.Nodes = []graphQLReference{{Number: 3}}
+
+	pr := newGraphQLIssue(3, "MERGED", old, "bug")
+	pr.Typename = "PullRequest"
+
+	fake, srv := newFakeGraphQL(t,
+		issue,
+		newGraphQLIssue(2, "OPEN", old),
+		pr,
+	)
+
+	s := &GitHubSource{
+		Owner:   "owner",
+		Repo:    "repo",
+		Types:   []string{"issues", "pulls"},
+		Labels:  []string{"bug"},
+		State:   "all",
+		Token:   "secret",
+		BaseURL: srv.URL,
+		GraphQL: true,
+	}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := itemNumbers(items); !slices.Equal(got, []int{3, 1}) {
+		t.Fatalf("discovered %v, want [3 1]", got)
+	}
+	if len(fake.searches) != 1 {
+		t.Errorf("expected a single query, got %d", len(fake.searches))
+	}
+	if want := `repo:owner/repo label:"bug" sort:updated-asc`; fake.searches[0] != want {
+		t.Errorf("search = %q, want %q", fake.searches[0], want)
+	}
+	if fake.auth != "token secret" {
+		t.Errorf("expected the token to be sent, got %q", fake.auth)
+	}
+
+	if items[0].Kind != "PR" {
+		t.Errorf("expected #3 to be a PR, got %q", items[0].Kind)
+	}
+	got := items[1]
+	if got.Kind != "Issue" || got.Title != "Issue 1" || got.Body != "Details" || got.URL != "https://github.com/owner/repo/issues/1" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if got.Author != "alice" || !slices.Equal(got.Assignees, []string{"bob"}) || got.Milestone != "v1.0" {
+		t.Errorf("unexpected author, assignees or milestone: %+v", got)
+	}
+	if !slices.Equal(got.LinkedPRs, []int{3}) {
+		t.Errorf("expected linked PR #3, got %v", got.LinkedPRs)
+	}
+	if got.Comments != "First comment\n---\nComment by a deleted user" {
+		t.Errorf("unexpected comments %q", got.Comments)
+	}
+	wantComments := []IssueComment{
+		{Author: "bob", Body: "First comment", CreatedAt: created},
+		{Body: "Comment by a deleted user", CreatedAt: created.Add(time.Minute)},
+	}
+	if !slices.EqualFunc(got.CommentList, wantComments, func(a, b IssueComment) bool {
+		return a.Author == b.Author && a.Body == b.Body && a.CreatedAt.Equal(b.CreatedAt)
+	}) {
+		t.Errorf("CommentList = %+v, want %+v", got.CommentList, wantComments)
+	}
+}
+
+func TestDiscoverGraphQLIncremental(t *testing.T) {
+	old := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fake, srv := newFakeGraphQL(t,
+		newGraphQLIssue(1, "OPEN", old, "bug"),
+		newGraphQLIssue(2, "OPEN", old.Add(time.Second), "bug"),
+		newGraphQLIssue(3, "OPEN", old.Add(2*time.Second), "bug"),
+	)
+	fake.pageSize = 2
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", Labels: []string{"bug"}, BaseURL: srv.URL, GraphQL: true, Index: NewItemIndex()}
+
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Fatalf("first discovery = %v, want [3 2 1]", got)
+	}
+	if len(fake.searches) != 2 {
+		t.Errorf("expected 2 pages, got %d", len(fake.searches))
+	}
+	if want := `repo:owner/repo is:issue is:open label:"bug" sort:updated-asc`; fake.searches[0] != want {
+		t.Errorf("search = %q, want %q", fake.searches[0], want)
+	}
+
+	// Close #2 and comment on #3
+	now := time.Now().UTC()
+	fake.update(newGraphQLIssue(2, "CLOSED", now, "bug"))
+	commented := newGraphQLIssue(3, "OPEN", now.Add(time.Second), "bug")
+	commented.Comments.Nodes = append(commented.Comments.Nodes, graphQLComment{Body: "New comment", CreatedAt: now})
+	fake.update(commented)
+
+	items, err = s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{3, 1}) {
+		t.Fatalf("second discovery = %v, want [3 1]", got)
+	}
+	if items[0].Comments != "New comment" {
+		t.Errorf("expected the new comment, got %q", items[0].Comments)
+	}
+	last := fake.searches[len(fake.searches)-1]
+	if !strings.Contains(last, "updated:>=") || strings.Contains(last, "is:open") || strings.Contains(last, "label:") {
+		t.Errorf("expected an incremental search, got %q", last)
+	}
+}
+
+func TestDiscoverGraphQLErrors(t *testing.T) {
+	fake, srv := newFakeGraphQL(t)
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: srv.URL, GraphQL: true}
+
+	fake.errors = []graphQLError{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}}
+	_, err := s.Discover(context.Background())
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+
+	fake.errors = []graphQLError{{Message: "Something went wrong"}}
+	_, err = s.Discover(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Something went wrong") {
+		t.Fatalf("expected the GraphQL error, got %v", err)
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"", "https://api.github.com/graphql"},
+		{"https://github.example.com/api/v3", "https://github.example.com/api/graphql"},
+		{"https://github.example.com/api/v3/", "https://github.example.com/api/graphql"},
+	}
+	for _, tt := range tests {
+		s := &GitHubSource{BaseURL: tt.baseURL}
+		if got := s.graphQLURL(); got != tt.want {
+			t.Errorf("graphQLURL() with base URL %q = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Pagination schemes of an HTTPSource.
+const (
+	// PaginationLink follows the URL of the Link header with rel="next".
+	PaginationLink = "link"
+	// PaginationNextURL follows the URL the pagination path selects in
+	// the response.
+	PaginationNextURL = "nextURL"
+	// PaginationCursor sends the cursor the pagination path selects in the
+	// response as the pagination parameter.
+	PaginationCursor = "cursor"
+	// PaginationPage counts the pagination parameter up from 1 until a
+	// page has no items.
+	PaginationPage = "page"
+)
+
+// HTTPHeaderEnvPrefix prefixes the environment variables of the spawner
+// that hold the values of the headers that come from Secrets, followed by
+// the index of the header. The controller sets them in the spawner's
+// Deployment.
+const HTTPHeaderEnvPrefix = "HTTP_HEADER_"
+
+// HTTPSource discovers items from a JSON API. Every element of the response
+// that ItemsPath selects is a WorkItem, whose fields are extracted with the
+// JSONPath expressions of Fields.
+type HTTPSource struct {
+	URL     string
+	Headers http.Header
+	Client  *http.Client
+
+	// ItemsPath selects the items in the response, like "{.issues[*]}".
+	// The response itself is the array of items if it is empty.
+	ItemsPath string
+	// Fields are the JSONPath expressions extracting the fields of an
+	// item.
+	Fields HTTPFields
+
+	// Pagination is the pagination scheme, if any. PaginationPath selects
+	// the next URL or the cursor in the response, and PaginationParam is
+	// the query parameter the cursor or page number is sent as.
+	Pagination      string
+	PaginationPath  string
+	PaginationParam string
+}
+
+// HTTPFields are the JSONPath expressions extracting the fields of an item
+// of an HTTPSource. ID is required; the other fields are left empty if they
+// have no expression.
+type HTTPFields struct {
+	ID     string
+	Number string
+	Title  string
+	Body   string
+	URL    string
+	Labels string
+}
+
+// ParseJSONPath parses a JSONPath expression like "{.fields.summary}". The
+// braces may be left out.
+func ParseJSONPath(expr string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New("").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, fmt.Errorf("parsing JSONPath %q: %w", expr, err)
+	}
+	return jp, nil
+}
+
+// Discover fetches the pages of items and returns them as WorkItems.
+func (s *HTTPSource) Discover(ctx context.Context) ([]WorkItem, error) {
+	paths, err := s.parsePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []WorkItem
+	pageURL := s.URL
+	page := 1
+	if s.Pagination == PaginationPage {
+		pageURL = withQueryParam(s.URL, s.PaginationParam, "1")
+	}
+	for range maxPages {
+		body, header, err := s.fetch(ctx, pageURL)
+		if err != nil {
+			return nil, err
+		}
+
+		elements, err := s.selectItems(paths.items, body)
+		if err != nil {
+			return nil, err
+		}
+		for i, element := range elements {
+			item, err := paths.workItem(element)
+			if err != nil {
+				return nil, fmt.Errorf("item %d of %s: %w", i, pageURL, err)
+			}
+			items = append(items, item)
+		}
+
+		switch s.Pagination {
+		case PaginationLink, PaginationNextURL:
+			next := parseNextLink(header.Get("Link"))
+			if s.Pagination == PaginationNextURL {
+				if next, err = findString(paths.next, body); err != nil {
+					return nil, fmt.Errorf("finding the next page: %w", err)
+				}
+			}
+			if next != "" {
+				// Relative to the page it is on
+				if next, err = resolveURL(pageURL, next); err != nil {
+					return nil, err
+				}
+				// The headers, which may hold credentials, are only sent
+				// to the API itself
+				if err := s.checkSameOrigin(next); err != nil {
+					return nil, err
+				}
+			}
+			pageURL = next
+		case PaginationCursor:
+			cursor, err := findString(paths.next, body)
+			if err != nil {
+				return nil, fmt.Errorf("finding the next cursor: %w", err)
+			}
+			pageURL = ""
+			if cursor != "" {
+				pageURL = withQueryParam(s.URL, s.PaginationParam, cursor)
+			}
+		case PaginationPage:
+			pageURL = ""
+			if len(elements) > 0 {
+				page++
+				pageURL = withQueryParam(s.URL, s.PaginationParam, strconv.Itoa(page))
+			}
+		default:
+			pageURL = ""
+		}
+		if pageURL == "" {
+			break
+		}
+	}
+	return items, nil
+}
+
+// httpPaths are the parsed JSONPath expressions of an HTTPSource. Those
+// that are not set are nil.
+type httpPaths struct {
+	items, next                          *jsonpath.JSONPath
+	id, number, title, body, url, labels *jsonpath.JSONPath
+}
+
+func (s *HTTPSource) parsePaths() (*httpPaths, error) {
+	if s.Fields.ID == "" {
+		return nil, fmt.Errorf("no JSONPath for the ID of items")
+	}
+	p := &httpPaths{}
+	for _, f := range []struct {
+		expr string
+		jp   **jsonpath.JSONPath
+	}{
+		{s.ItemsPath, &p.items},
+		{s.PaginationPath, &p.next},
+		{s.Fields.ID, &p.id},
+		{s.Fields.Number, &p.number},
+		{s.Fields.Title, &p.title},
+		{s.Fields.Body, &p.body},
+		{s.Fields.URL, &p.url},
+		{s.Fields.Labels, &p.labels},
+	} {
+		if f.expr == "" {
+			continue
+		}
+		jp, err := ParseJSONPath(f.expr)
+		if err != nil {
+			return nil, err
+		}
+		*f.jp = jp
+	}
+	return p, nil
+}
+
+// workItem extracts the fields of the item.
+func (p *httpPaths) workItem(element any) (WorkItem, error) {
+	var item WorkItem
+	var err error
+	if item.ID, err = findString(p.id, element); err != nil {
+		return WorkItem{}, fmt.Errorf("finding the ID: %w", err)
+	}
+	if item.ID == "" {
+		return WorkItem{}, fmt.Errorf("no ID")
+	}
+	number, err := findString(p.number, element)
+	if err != nil {
+		return WorkItem{}, fmt.Errorf("finding the number: %w", err)
+	}
+	if number != "" {
+		if item.Number, err = strconv.Atoi(number); err != nil {
+			return WorkItem{}, fmt.Errorf("parsing the number: %w", err)
+		}
+	}
+	for _, f := range []struct {
+		jp    *jsonpath.JSONPath
+		field *string
+	}{
+		{p.title, &item.Title},
+		{p.body, &item.Body},
+		{p.url, &item.URL},
+	} {
+		if *f.field, err = findString(f.jp, element); err != nil {
+			return WorkItem{}, err
+		}
+	}
+	if item.Labels, err = findStrings(p.labels, element); err != nil {
+		return WorkItem{}, fmt.Errorf("finding the labels: %w", err)
+	}
+	return item, nil
+}
+
+// fetch returns the decoded JSON response of the URL, and its header.
+func (s *HTTPSource) fetch(ctx context.Context, pageURL string) (any, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating request: %w", err)
+	}
+	for name, values := range s.Headers {
+		req.Header[name] = values
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, nil, fmt.Errorf("%s returned status %d: %s", pageURL, resp.StatusCode, string(body))
+	}
+
+	// Numbers are kept as they are, so that large IDs are not rounded
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	var body any
+	if err := dec.Decode(&body); err != nil {
+		return nil, nil, fmt.Errorf("decoding the response of %s: %w", pageURL, err)
+	}
+	return body, resp.Header, nil
+}
+
+// selectItems returns the elements of the response the path selects. A
+// single array selected is the array of items.
+func (s *HTTPSource) selectItems(jp *jsonpath.JSONPath, body any) ([]any, error) {
+	if jp == nil {
+		elements, ok := body.([]any)
+		if !ok {
+			return nil, fmt.Errorf("the response is not an array of items; set the path of the items in it")
+		}
+		return elements, nil
+	}
+
+	results, err := jp.FindResults(body)
+	if err != nil {
+		return nil, fmt.Errorf("finding the items: %w", err)
+	}
+	var elements []any
+	for _, result := range results {
+		for _, v := range result {
+			elements = append(elements, v.Interface())
+		}
+	}
+	if len(elements) == 1 {
+		if array, ok := elements[0].([]any); ok {
+			return array, nil
+		}
+	}
+	return elements, nil
+}
+
+// findString returns the values the path selects in the element, joined by
+// spaces, or "" if the path is nil or selects nothing.
+func findString(jp *jsonpath.JSONPath, element any) (string, error) {
+	values, err := findStrings(jp, element)
+	return strings.Join(values, " "), err
+}
+
+// findStrings returns the values the path selects in the element. Arrays
+// are flattened into their elements.
+func findStrings(jp *jsonpath.JSONPath, element any) ([]string, error) {
+	if jp == nil {
+		return nil, nil
+	}
+	results, err := jp.FindResults(element)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, result := range results {
+		for _, v := range result {
+			values = appendValue(values, v)
+		}
+	}
+	return values, nil
+}
+
+func appendValue(values []string, v reflect.Value) []string {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		// null
+		return values
+	}
+	switch x := v.Interface().(type) {
+	case string:
+		return append(values, x)
+	case json.Number:
+		return append(values, x.String())
+	case []any:
+		for _, e := range x {
+			values = appendValue(values, reflect.ValueOf(e))
+		}
+		return values
+	case map[string]any:
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(x)
+		return append(values, strings.TrimSpace(buf.String()))
+	default:
+		return append(values, fmt.Sprint(x))
+	}
+}
+
+// withQueryParam returns the URL with the query parameter set.
+func withQueryParam(rawURL, param, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set(param, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// checkSameOrigin returns an error if the URL has another scheme or host
+// than the URL of the source.
+func (s *HTTPSource) checkSameOrigin(rawURL string) error {
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("parsing URL %q: %w", s.URL, err)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing the next URL %q: %w", rawURL, err)
+	}
+	if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return fmt.Errorf("the next page %s is not on %s://%s", rawURL, base.Scheme, base.Host)
+	}
+	return nil
+}
+
+// resolveURL resolves the reference relative to the base URL.
+func resolveURL(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL %q: %w", base, err)
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("parsing the next URL %q: %w", ref, err)
+	}
+	return b.ResolveReference(r).String(), nil
+}
@@ -0,0 +1,286 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ticket is an item of the fake ticket system.
+type ticket struct {
+	Key    string `json:"key"`
+	Number int64  `json:"number"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Labels      []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"fields"`
+	Link string `json:"link"`
+}
+
+func newTicket(n int) ticket {
+	t := ticket{Key: fmt.Sprintf("PROJ-%d", n), Number: int64(n), Link: fmt.Sprintf("https://tickets.example.com/PROJ-%d", n)}
+	t.Fields.Summary = fmt.Sprintf("Ticket %d", n)
+	t.Fields.Description = "Something is broken"
+	t.Fields.Labels = append(t.Fields.Labels, struct {
+		Name string `json:"name"`
+	}{Name: "bug"})
+	return t
+}
+
+var ticketFields = HTTPFields{
+	ID:     "{.key}",
+	Number: ".number",
+	Title:  "{.fields.summary}",
+	Body:   "{.fields.description}",
+	URL:    "{.link}",
+	Labels: "{.fields.labels[*].name}",
+}
+
+func TestDiscoverHTTP(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(map[string]any{"issues": []ticket{newTicket(1), newTicket(2)}})
+	}))
+	defer server.Close()
+
+	s := &HTTPSource{
+		URL:       server.URL + "/search",
+		Headers:   http.Header{"Authorization": {"Bearer secret"}},
+		ItemsPath: "{.issues}",
+		Fields:    ticketFields,
+	}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected the Authorization header, got %q", auth)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	got := items[0]
+	if got.ID != "PROJ-1" || got.Number != 1 || got.Title != "Ticket 1" || got.Body != "Something is broken" ||
+		got.URL != "https://tickets.example.com/PROJ-1" || !slices.Equal(got.Labels, []string{"bug"}) {
+		t.Errorf("unexpected item: %+v", got)
+	}
+}
+
+func TestDiscoverHTTPPagination(t *testing.T) {
+	const pages = 3
+	tests := []struct {
+		name       string
+		pagination string
+		path       string
+		param      string
+		serve      func(w http.ResponseWriter, r *http.Request, page int, items []ticket)
+		page       func(r *http.Request) int
+	}{
+		{
+			name:       "link",
+			pagination: PaginationLink,
+			page:       func(r *http.Request) int { n, _ := strconv.Atoi(r.URL.Query().Get("p")); return max(n, 1) },
+			serve: func(w http.ResponseWriter, r *http.Request, page int, items []ticket) {
+				if page < pages {
+					w.Header().Set("Link", fmt.Sprintf(`<http://%s/tickets?state=open&p=%d>; rel="next"`, r.Host, page+1))
+				}
+				json.NewEncoder(w).Encode(items)
+			},
+		},
+		{
+			name:       "next URL",
+			pagination: PaginationNextURL,
+			path:       "{.next}",
+			page:       func(r *http.Request) int { n, _ := strconv.Atoi(r.URL.Query().Get("p")); return max(n, 1) },
+			serve: func(w http.ResponseWriter, r *http.Request, page int, items []ticket) {
+				next := ""
+				if page < pages {
+					next = fmt.Sprintf("/tickets?state=open&p=%d", page+1)
+				}
+				json.NewEncoder(w).Encode(map[string]any{"items": items, "next": next})
+			},
+		},
+		{
+			name:       "cursor",
+			pagination: PaginationCursor,
+			path:       "{.meta.cursor}",
+			param:      "after",
+			page: func(r *http.Request) int {
+				n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Query().Get("after"), "c"))
+				return n + 1
+			},
+			serve: func(w http.ResponseWriter, r *http.Request, page int, items []ticket) {
+				meta := map[string]any{}
+				if page < pages {
+					meta["cursor"] = fmt.Sprintf("c%d", page)
+				}
+				json.NewEncoder(w).Encode(map[string]any{"items": items, "meta": meta})
+			},
+		},
+		{
+			name:       "page",
+			pagination: PaginationPage,
+			param:      "page",
+			page:       func(r *http.Request) int { n, _ := strconv.Atoi(r.URL.Query().Get("page")); return n },
+			serve: func(w http.ResponseWriter, r *http.Request, page int, items []ticket) {
+				if page > pages {
+					items = []ticket{}
+				}
+				json.NewEncoder(w).Encode(map[string]any{"items": items})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.URL.String())
+				if r.URL.Query().Get("state") != "open" {
+					t.Errorf("expected the query of the URL to be kept, got %s", r.URL)
+				}
+				page := tt.page(r)
+				tt.serve(w, r, page, []ticket{newTicket(page)})
+			}))
+			defer server.Close()
+
+			s := &HTTPSource{
+				URL:             server.URL + "/tickets?state=open",
+				Fields:          ticketFields,
+				Pagination:      tt.pagination,
+				PaginationPath:  tt.path,
+				PaginationParam: tt.param,
+			}
+			if tt.pagination != PaginationLink {
+				s.ItemsPath = "{.items[*]}"
+			}
+			items, err := s.Discover(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ids []string
+			for _, item := range items {
+				ids = append(ids, item.ID)
+			}
+			if !slices.Equal(ids, []string{"PROJ-1", "PROJ-2", "PROJ-3"}) {
+				t.Errorf("expected the items of all pages, got %v after requests %v", ids, requests)
+			}
+		})
+	}
+}
+
+func TestDiscoverHTTPErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		source  HTTPSource
+		wantErr string
+	}{
+		{
+			name:    "Error status",
+			status:  http.StatusUnauthorized,
+			body:    `{"error":"unauthorized"}`,
+			source:  HTTPSource{Fields: ticketFields},
+			wantErr: "returned status 401",
+		},
+		{
+			name:    "Not an array",
+			body:    `{"issues":[]}`,
+			source:  HTTPSource{Fields: ticketFields},
+			wantErr: "not an array",
+		},
+		{
+			name:    "Item without ID",
+			body:    `[{"title":"No key"}]`,
+			source:  HTTPSource{Fields: ticketFields},
+			wantErr: "item 0",
+		},
+		{
+			name:    "Invalid JSONPath",
+			body:    `[]`,
+			source:  HTTPSource{Fields: HTTPFields{ID: "{.key"}},
+			wantErr: "parsing JSONPath",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			s := tt.source
+			s.URL = server.URL
+			_, err := s.Discover(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDiscoverHTTPCrossOriginNextPage(t *testing.T) {
+	var leaked []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = append(leaked, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"issues":[]}`)
+	}))
+	defer other.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/search?page=2>; rel="next"`, other.URL))
+		json.NewEncoder(w).Encode(map[string]any{"issues": []ticket{newTicket(1)}, "next": other.URL + "/search?page=2"})
+	}))
+	defer server.Close()
+
+	for _, pagination := range []string{PaginationLink, PaginationNextURL} {
+		t.Run(pagination, func(t *testing.T) {
+			s := &HTTPSource{
+				URL:            server.URL + "/search",
+				Headers:        http.Header{"Authorization": {"Bearer secret"}},
+				ItemsPath:      "{.issues}",
+				Fields:         ticketFields,
+				Pagination:     pagination,
+				PaginationPath: "{.next}",
+			}
+			_, err := s.Discover(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "is not on "+server.URL) {
+				t.Errorf("expected an error about the next page on another host, got %v", err)
+			}
+			if len(leaked) > 0 {
+				t.Errorf("expected no request to the other host, got %d", len(leaked))
+			}
+		})
+	}
+}
+
+func TestDiscoverHTTPLargeNumbers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":9007199254740993,"tags":["a",["b"]],"title":null}]`)
+	}))
+	defer server.Close()
+
+	s := &HTTPSource{URL: server.URL, Fields: HTTPFields{ID: "{.id}", Title: "{.title}", Labels: "{.tags}"}}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "9007199254740993" || items[0].Title != "" || !slices.Equal(items[0].Labels, []string{"a", "b"}) {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
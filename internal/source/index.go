@@ -0,0 +1,262 @@
+package source
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sinceOverlap is subtracted from the time a complete listing started to
+// get the time the next listing asks for updates since, so that issues
+// updated while listing, or on a server whose clock is ahead, are not
+// missed.
+const sinceOverlap = time.Minute
+
+// ItemIndex holds the issues a GitHubSource discovered so far and their
+// comments. After the first full listing, discovery only fetches the issues
+// updated since the last one, and merges them into the index. It is safe
+// for concurrent use.
+type ItemIndex struct {
+	mu   sync.Mutex
+	data indexData
+}
+
+type indexData struct {
+	// Query identifies the repository and filters the index was built
+	// for. The index is rebuilt when they change.
+	Query string `json:"query"`
+
+	// Since is the time the next listing asks for updates since. It is
+	// zero until a first listing completed.
+	Since time.Time `json:"since,omitzero"`
+
+	Items map[int]*indexedIssue `json:"items"`
+}
+
+type indexedIssue struct {
+	Issue    githubIssue    `json:"issue"`
+	Comments []IssueComment `json:"comments,omitempty"`
+}
+
+// NewItemIndex returns an empty ItemIndex.
+func NewItemIndex() *ItemIndex {
+	return &ItemIndex{data: indexData{Items: map[int]*indexedIssue{}}}
+}
+
+// Len returns the number of indexed issues.
+func (x *ItemIndex) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.data.Items)
+}
+
+// MarshalBinary encodes the index as gzipped JSON. The encoding of the
+// same index is always the same.
+func (x *ItemIndex) MarshalBinary() ([]byte, error) {
+	x.mu.Lock()
+	data, err := json.Marshal(x.data)
+	x.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("encoding item index: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("compressing item index: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressing item index: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the index with one encoded by MarshalBinary.
+func (x *ItemIndex) UnmarshalBinary(data []byte) error {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decompressing item index: %w", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return fmt.Errorf("decompressing item index: %w", err)
+	}
+
+	d := indexData{}
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return fmt.Errorf("decoding item index: %w", err)
+	}
+	if d.Items == nil {
+		d.Items = map[int]*indexedIssue{}
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.data = d
+	return nil
+}
+
+// indexQuery identifies the repository and the filters applied by the
+// GitHub API. The client-side filters are applied to the indexed issues
+// instead, so changing them does not require a new listing. The GraphQL
+// API also filters by type.
+func (s *GitHubSource) indexQuery() string {
+	labels := slices.Clone(s.Labels)
+	slices.Sort(labels)
+	query := fmt.Sprintf("%s/%s/%s?state=%s&labels=%s", s.baseURL(), s.Owner, s.Repo, s.state(), strings.Join(labels, ","))
+	if assignee := s.assignee(); assignee != "" {
+		query += "&assignee=" + assignee
+	}
+	if s.Author != "" {
+		query += "&creator=" + s.Author
+	}
+	if s.Milestone != "" {
+		query += "&milestone=" + s.Milestone
+	}
+	if s.GraphQL {
+		types := slices.Sorted(maps.Keys(s.resolvedTypes()))
+		query += "&types=" + strings.Join(types, ",") + "&api=graphql"
+	}
+	return query
+}
+
+// discoverIncremental fetches the issues updated since the last listing,
+// merges them into the index and returns the indexed issues that pass the
+// client-side filters, newest first.
+//
+// Issues are listed in the order they were updated in, so if a listing is
+// cut short by maxPages, the next one continues after the last issue it
+// got instead of the remaining issues being missed.
+func (s *GitHubSource) discoverIncremental(ctx context.Context, x *ItemIndex) ([]WorkItem, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if query := s.indexQuery(); x.data.Query != query {
+		x.data = indexData{Query: query, Items: map[int]*indexedIssue{}}
+	}
+
+	listPage := s.listUpdatedIssuesPage
+	switch {
+	case s.GraphQL:
+		listPage = s.searchUpdatedIssuesPage
+	case s.Query != "":
+		listPage = s.searchIssuesPage
+	}
+
+	start := time.Now()
+	incremental := !x.data.Since.IsZero()
+	cursor := ""
+	var last time.Time
+	for page := 0; ; page++ {
+		if page == maxPages {
+			// Continue after the last issue in the next discovery
+			x.data.Since = last
+			break
+		}
+		issues, next, err := listPage(ctx, x.data.Since, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for i := range issues {
+			issue := &issues[i]
+			last = issue.UpdatedAt
+			// The updates of an incremental listing are not filtered by
+			// the API, so that issues that no longer match are removed
+			if incremental && !s.matchesQuery(issue) {
+				delete(x.data.Items, issue.Number)
+				continue
+			}
+			if known, ok := x.data.Items[issue.Number]; ok && known.Issue.UpdatedAt.Equal(issue.UpdatedAt) {
+				continue
+			}
+			// New comments update the issue, so the comments of an issue
+			// that did not change are still up to date
+			comments := issue.comments
+			if !s.GraphQL {
+				comments, err = s.ListComments(ctx, issue.Number, time.Time{})
+				if err != nil {
+					return nil, fmt.Errorf("fetching comments for issue #%d: %w", issue.Number, err)
+				}
+			}
+			x.data.Items[issue.Number] = &indexedIssue{Issue: *issue, Comments: comments}
+		}
+		if next == "" {
+			x.data.Since = start.Add(-sinceOverlap)
+			break
+		}
+		cursor = next
+	}
+
+	numbers := make([]int, 0, len(x.data.Items))
+	issues := make([]githubIssue, 0, len(x.data.Items))
+	for n := range x.data.Items {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+	slices.Reverse(numbers)
+	for _, n := range numbers {
+		issues = append(issues, x.data.Items[n].Issue)
+	}
+
+	var items []WorkItem
+	for _, issue := range s.filterItems(issues) {
+		items = append(items, issue.workItem(x.data.Items[issue.Number].Comments))
+	}
+	if err := s.addPullRequestDetails(ctx, items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// listUpdatedIssuesPage returns a page of the issues updated since the given
+// time from the REST API, and the URL of the next page, if any. An empty
+// pageURL requests the first page.
+func (s *GitHubSource) listUpdatedIssuesPage(ctx context.Context, since time.Time, pageURL string) ([]githubIssue, string, error) {
+	if pageURL == "" {
+		pageURL = s.buildUpdatedIssuesURL(since)
+	}
+	return s.fetchIssuesPage(ctx, pageURL)
+}
+
+// buildUpdatedIssuesURL returns the URL listing the issues in the order
+// they were updated in. Without a since time, the API filters them;
+// otherwise all issues updated since are listed.
+func (s *GitHubSource) buildUpdatedIssuesURL(since time.Time) string {
+	u := fmt.Sprintf("%s/repos/%s/%s/issues", s.baseURL(), s.Owner, s.Repo)
+
+	params := url.Values{}
+	params.Set("per_page", "100")
+	params.Set("sort", "updated")
+	params.Set("direction", "asc")
+	if since.IsZero() {
+		s.setListFilters(params)
+	} else {
+		params.Set("state", "all")
+		params.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	return u + "?" + params.Encode()
+}
+
+// matchesQuery reports whether the issue passes the state and label filters
+// otherwise applied by the API. The other filters applied by the API are
+// also applied by filterItems.
+func (s *GitHubSource) matchesQuery(issue *githubIssue) bool {
+	if state := s.state(); state != "all" && issue.State != state {
+		return false
+	}
+	for _, want := range s.Labels {
+		if !slices.ContainsFunc(issue.Labels, func(l githubLabel) bool { return l.Name == want }) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRepo serves the issues of a repository like the GitHub API, filtered
+// by state, labels and since, in the order they were updated in.
+type fakeRepo struct {
+	mu       sync.Mutex
+	issues   map[int]*githubIssue
+	pageSize int
+
+	listings        []string
+	commentsFetched []int
+	baseURL         string
+}
+
+func newFakeRepo(t *testing.T, issues ...githubIssue) (*fakeRepo, *httptest.Server) {
+	r := &fakeRepo{issues: map[int]*githubIssue{}, pageSize: 100}
+	for i := range issues {
+		r.issues[issues[i].Number] = &issues[i]
+	}
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	r.baseURL = srv.URL
+	return r, srv
+}
+
+func (r *fakeRepo) update(issue githubIssue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.issues[issue.Number] = &issue
+}
+
+func (r *fakeRepo) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if strings.HasSuffix(req.URL.Path, "/comments") {
+		parts := strings.Split(req.URL.Path, "/")
+		n, _ := strconv.Atoi(parts[len(parts)-2])
+		r.commentsFetched = append(r.commentsFetched, n)
+		json.NewEncoder(w).Encode([]githubComment{{Body: fmt.Sprintf("Comment on #%d", n)}})
+		return
+	}
+
+	q := req.URL.Query()
+	if q.Get("page") == "" {
+		r.listings = append(r.listings, req.URL.RawQuery)
+	}
+	since, _ := time.Parse(time.RFC3339, q.Get("since"))
+	var matched []githubIssue
+	for _, issue := range r.issues {
+		if !since.IsZero() && issue.UpdatedAt.Before(since) {
+			continue
+		}
+		if state := q.Get("state"); state != "all" && issue.State != state {
+			continue
+		}
+		if labels := q.Get("labels"); labels != "" {
+			ok := true
+			for _, want := range strings.Split(labels, ",") {
+				if !slices.ContainsFunc(issue.Labels, func(l githubLabel) bool { return l.Name == want }) {
+					ok = false
+				}
+			}
+			if !ok {
+				continue
+			}
+		}
+		matched = append(matched, *issue)
+	}
+	slices.SortFunc(matched, func(a, b githubIssue) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	page = max(page, 1)
+	start := min((page-1)*r.pageSize, len(matched))
+	end := min(start+r.pageSize, len(matched))
+	if end < len(matched) {
+		q.Set("page", strconv.Itoa(page+1))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?%s>; rel="next"`, r.baseURL, req.URL.Path, q.Encode()))
+	}
+	json.NewEncoder(w).Encode(matched[start:end])
+}
+
+func itemNumbers(items []WorkItem) []int {
+	var numbers []int
+	for _, item := range items {
+		numbers = append(numbers, item.Number)
+	}
+	return numbers
+}
+
+func TestDiscoverIncremental(t *testing.T) {
+	old := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	bug := []githubLabel{{Name: "bug"}}
+	repo, srv := newFakeRepo(t,
+		githubIssue{Number: 1, Title: "One", State: "open", Labels: bug, UpdatedAt: old},
+		githubIssue{Number: 2, Title: "Two", State: "open", Labels: bug, UpdatedAt: old},
+		githubIssue{Number: 3, Title: "Three", State: "open", Labels: bug, UpdatedAt: old},
+		githubIssue{Number: 5, Title: "Not a bug", State: "open", UpdatedAt: old},
+	)
+
+	index := NewItemIndex()
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: srv.URL, Labels: []string{"bug"}, Index: index}
+
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Fatalf("first discovery = %v, want [3 2 1]", got)
+	}
+	if items[0].Comments != "Comment on #3" {
+		t.Errorf("unexpected comments %q", items[0].Comments)
+	}
+	if strings.Contains(repo.listings[0], "since=") {
+		t.Errorf("expected a full listing first, got %s", repo.listings[0])
+	}
+
+	// Close #2, remove the label of #3, open #4, and comment on #1
+	now := time.Now().UTC()
+	repo.update(githubIssue{Number: 2, Title: "Two", State: "closed", Labels: bug, UpdatedAt: now})
+	repo.update(githubIssue{Number: 3, Title: "Three", State: "open", UpdatedAt: now})
+	repo.update(githubIssue{Number: 4, Title: "Four", State: "open", Labels: bug, UpdatedAt: now})
+	repo.update(githubIssue{Number: 1, Title: "One", State: "open", Labels: bug, UpdatedAt: now.Add(time.Second)})
+	repo.commentsFetched = nil
+
+	items, err = s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{4, 1}) {
+		t.Fatalf("second discovery = %v, want [4 1]", got)
+	}
+	if !strings.Contains(repo.listings[1], "since=") || !strings.Contains(repo.listings[1], "state=all") {
+		t.Errorf("expected an incremental listing, got %s", repo.listings[1])
+	}
+	slices.Sort(repo.commentsFetched)
+	if !slices.Equal(repo.commentsFetched, []int{1, 4}) {
+		t.Errorf("expected comments of the updated matching issues only, got %v", repo.commentsFetched)
+	}
+
+	// Nothing changed since
+	repo.commentsFetched = nil
+	items, err = s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{4, 1}) {
+		t.Fatalf("third discovery = %v, want [4 1]", got)
+	}
+	if len(repo.commentsFetched) != 0 {
+		t.Errorf("expected no comments to be fetched, got %v", repo.commentsFetched)
+	}
+}
+
+func TestDiscoverIncrementalBeyondMaxPages(t *testing.T) {
+	base := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	var issues []githubIssue
+	for n := 1; n <= maxPages+2; n++ {
+		issues = append(issues, githubIssue{Number: n, State: "open", UpdatedAt: base.Add(time.Duration(n) * time.Minute)})
+	}
+	repo, srv := newFakeRepo(t, issues...)
+	repo.pageSize = 1
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: srv.URL, Index: NewItemIndex()}
+
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != maxPages {
+		t.Fatalf("expected the first discovery to stop after %d pages, got %d items", maxPages, len(items))
+	}
+
+	items, err = s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != maxPages+2 {
+		t.Fatalf("expected the second discovery to continue where the first stopped, got %d items", len(items))
+	}
+}
+
+func TestDiscoverIncrementalQueryChange(t *testing.T) {
+	old := time.Now().Add(-time.Hour).UTC()
+	repo, srv := newFakeRepo(t,
+		githubIssue{Number: 1, State: "open", Labels: []githubLabel{{Name: "bug"}}, UpdatedAt: old},
+		githubIssue{Number: 2, State: "open", UpdatedAt: old},
+	)
+	index := NewItemIndex()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: srv.URL, Labels: []string{"bug"}, Index: index}
+	if _, err := s.Discover(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Persist and restore the index, like a restarted spawner
+	data, err := index.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored := NewItemIndex()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.Len() != 1 {
+		t.Fatalf("expected 1 restored issue, got %d", restored.Len())
+	}
+
+	s = &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: srv.URL, Index: restored}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{2, 1}) {
+		t.Fatalf("discovery without label filter = %v, want [2 1]", got)
+	}
+	if strings.Contains(repo.listings[1], "since=") {
+		t.Errorf("expected a full listing after the filters changed, got %s", repo.listings[1])
+	}
+}
@@ -0,0 +1,221 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jiraFields are the fields of the issues a JiraSource requests.
+const jiraFields = "summary,description,labels,components,comment,reporter,assignee"
+
+// JiraSource discovers the issues of a Jira site that match a JQL query.
+// Descriptions and comments are converted to Markdown, from the Atlassian
+// Document Format of version 3 of the REST API of Jira Cloud, or from the
+// wiki markup of version 2 of Jira Server and Data Center.
+type JiraSource struct {
+	// BaseURL is the URL of the Jira site, like
+	// "https://example.atlassian.net".
+	BaseURL string
+	// JQL selects the issues to discover.
+	JQL string
+	// APIVersion is the version of the REST API, "2" or "3". Defaults to
+	// "3".
+	APIVersion string
+
+	// User and Token authenticate the requests: with basic authentication
+	// if User is set, like with the email address and API token of a Jira
+	// Cloud account, and with the Token as bearer token otherwise, like a
+	// personal access token of Jira Server.
+	User  string
+	Token string
+
+	Client *http.Client
+}
+
+type jiraSearchResult struct {
+	Issues []jiraIssue `json:"issues"`
+
+	// Version 3 pages with tokens
+	NextPageToken string `json:"nextPageToken"`
+	IsLast        bool   `json:"isLast"`
+
+	// Version 2 pages with offsets
+	StartAt int `json:"startAt"`
+	Total   int `json:"total"`
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"`
+		Labels      []string        `json:"labels"`
+		Components  []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Reporter *jiraUser `json:"reporter"`
+		Assignee *jiraUser `json:"assignee"`
+		Comment  struct {
+			Comments []jiraComment `json:"comments"`
+		} `json:"comment"`
+	} `json:"fields"`
+}
+
+type jiraUser struct {
+	DisplayName string `json:"displayName"`
+}
+
+type jiraComment struct {
+	Author  *jiraUser       `json:"author"`
+	Body    json.RawMessage `json:"body"`
+	Created string          `json:"created"`
+}
+
+// jiraTimeLayout is the layout of the times in Jira API responses.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Discover fetches the issues matching the JQL query and returns them as
+// WorkItems.
+func (s *JiraSource) Discover(ctx context.Context) ([]WorkItem, error) {
+	var items []WorkItem
+	var token string
+	startAt := 0
+	for page := 0; page < maxPages; page++ {
+		result, err := s.search(ctx, token, startAt)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result.Issues {
+			items = append(items, s.workItem(&result.Issues[i]))
+		}
+
+		if s.apiVersion() == "2" {
+			startAt = result.StartAt + len(result.Issues)
+			if len(result.Issues) == 0 || startAt >= result.Total {
+				break
+			}
+		} else {
+			token = result.NextPageToken
+			if result.IsLast || token == "" {
+				break
+			}
+		}
+	}
+	return items, nil
+}
+
+func (s *JiraSource) apiVersion() string {
+	if s.APIVersion == "" {
+		return "3"
+	}
+	return s.APIVersion
+}
+
+// search fetches a page of the issues matching the JQL query. Version 3 of
+// the API continues after the page of the token, version 2 at the offset.
+func (s *JiraSource) search(ctx context.Context, token string, startAt int) (*jiraSearchResult, error) {
+	params := url.Values{}
+	params.Set("jql", s.JQL)
+	params.Set("fields", jiraFields)
+	params.Set("maxResults", "100")
+	path := "/rest/api/3/search/jql"
+	if s.apiVersion() == "2" {
+		path = "/rest/api/2/search"
+		params.Set("startAt", strconv.Itoa(startAt))
+	} else if token != "" {
+		params.Set("nextPageToken", token)
+	}
+	u := strings.TrimSuffix(s.BaseURL, "/") + path + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case s.User != "":
+		req.SetBasicAuth(s.User, s.Token)
+	case s.Token != "":
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("searching Jira issues: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, jiraAPIError(resp)
+	}
+
+	var result jiraSearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding Jira issues: %w", err)
+	}
+	return &result, nil
+}
+
+// jiraAPIError returns the error of the response. Jira Cloud rate limits
+// requests with 429 Too Many Requests and the time to wait in Retry-After.
+func jiraAPIError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if after, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return &RateLimitError{Reset: time.Now().Add(time.Duration(after) * time.Second)}
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("Jira API returned status %d: %s", resp.StatusCode, string(body))
+}
+
+// workItem returns the issue as a WorkItem. Only the first maxCommentBytes
+// of comments are included.
+func (s *JiraSource) workItem(issue *jiraIssue) WorkItem {
+	item := WorkItem{
+		ID:     issue.Key,
+		Title:  issue.Fields.Summary,
+		Body:   jiraMarkdown(issue.Fields.Description),
+		URL:    strings.TrimSuffix(s.BaseURL, "/") + "/browse/" + issue.Key,
+		Labels: issue.Fields.Labels,
+		Kind:   "JiraIssue",
+	}
+	for _, c := range issue.Fields.Components {
+		item.Components = append(item.Components, c.Name)
+	}
+	if issue.Fields.Reporter != nil {
+		item.Author = issue.Fields.Reporter.DisplayName
+	}
+	if issue.Fields.Assignee != nil {
+		item.Assignees = []string{issue.Fields.Assignee.DisplayName}
+	}
+
+	var parts []string
+	totalBytes := 0
+	for _, c := range issue.Fields.Comment.Comments {
+		comment := IssueComment{Body: jiraMarkdown(c.Body)}
+		if c.Author != nil {
+			comment.Author = c.Author.DisplayName
+		}
+		comment.CreatedAt, _ = time.Parse(jiraTimeLayout, c.Created)
+
+		totalBytes += len(comment.Body)
+		if totalBytes > maxCommentBytes {
+			break
+		}
+		parts = append(parts, comment.Body)
+		item.CommentList = append(item.CommentList, comment)
+	}
+	item.Comments = strings.Join(parts, "\n---\n")
+	return item
+}
@@ -0,0 +1,267 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// jiraMarkdown converts a description or comment body of a Jira API
+// response to Markdown: a document in the Atlassian Document Format, as
+// version 3 of the API returns, or a string of wiki markup, as version 2
+// returns.
+func jiraMarkdown(raw json.RawMessage) string {
+	var wiki string
+	if err := json.Unmarshal(raw, &wiki); err == nil {
+		return wikiToMarkdown(wiki)
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	writeADFBlocks(&b, doc.Content, "")
+	return strings.TrimSpace(b.String())
+}
+
+// adfNode is a node of a document in the Atlassian Document Format.
+type adfNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Attrs   map[string]any `json:"attrs"`
+	Marks   []adfMark      `json:"marks"`
+	Content []adfNode      `json:"content"`
+}
+
+type adfMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs"`
+}
+
+func (n *adfNode) attr(name string) string {
+	if v, ok := n.Attrs[name]; ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// writeADFBlocks writes the block nodes, separated by blank lines, with
+// every line prefixed, like "> " in a quote.
+func writeADFBlocks(b *strings.Builder, blocks []adfNode, prefix string) {
+	for i := range blocks {
+		if i > 0 {
+			b.WriteString(strings.TrimRight(prefix, " ") + "\n")
+		}
+		writeADFBlock(b, &blocks[i], prefix)
+	}
+}
+
+func writeADFBlock(b *strings.Builder, n *adfNode, prefix string) {
+	switch n.Type {
+	case "paragraph":
+		writePrefixed(b, adfInline(n.Content), prefix)
+	case "heading":
+		level := 1
+		fmt.Sscan(n.attr("level"), &level)
+		writePrefixed(b, strings.Repeat("#", level)+" "+adfInline(n.Content), prefix)
+	case "codeBlock":
+		writePrefixed(b, "```"+n.attr("language")+"\n"+adfInline(n.Content)+"\n```", prefix)
+	case "blockquote", "panel":
+		writeADFBlocks(b, n.Content, prefix+"> ")
+	case "bulletList", "orderedList":
+		for i := range n.Content {
+			marker := "- "
+			if n.Type == "orderedList" {
+				marker = fmt.Sprintf("%d. ", i+1)
+			}
+			writeADFListItem(b, &n.Content[i], prefix, marker)
+		}
+	case "rule":
+		writePrefixed(b, "---", prefix)
+	case "table":
+		for i, row := range n.Content {
+			var cells []string
+			for _, cell := range row.Content {
+				var c strings.Builder
+				writeADFBlocks(&c, cell.Content, "")
+				cells = append(cells, strings.ReplaceAll(strings.TrimSpace(c.String()), "\n", " "))
+			}
+			writePrefixed(b, "| "+strings.Join(cells, " | ")+" |", prefix)
+			if i == 0 {
+				writePrefixed(b, strings.Repeat("| --- ", len(cells))+"|", prefix)
+			}
+		}
+	case "mediaSingle", "mediaGroup":
+		// Attachments are not part of the text
+	default:
+		if len(n.Content) > 0 && n.Content[0].Type == "text" {
+			writePrefixed(b, adfInline(n.Content), prefix)
+		} else {
+			writeADFBlocks(b, n.Content, prefix)
+		}
+	}
+}
+
+// writeADFListItem writes the item with the marker, and its nested blocks
+// indented below it.
+func writeADFListItem(b *strings.Builder, item *adfNode, prefix, marker string) {
+	var c strings.Builder
+	writeADFBlocks(&c, item.Content, "")
+	indent := strings.Repeat(" ", len(marker))
+	for i, line := range strings.Split(strings.TrimSpace(c.String()), "\n") {
+		if i == 0 {
+			writePrefixed(b, marker+line, prefix)
+		} else if line == "" {
+			writePrefixed(b, "", prefix)
+		} else {
+			writePrefixed(b, indent+line, prefix)
+		}
+	}
+}
+
+// writePrefixed writes the text as lines with the prefix.
+func writePrefixed(b *strings.Builder, text, prefix string) {
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+}
+
+// adfInline returns the inline nodes as Markdown.
+func adfInline(nodes []adfNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(adfMarks(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString(n.attr("text"))
+		case "emoji":
+			if text := n.attr("text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(n.attr("shortName"))
+			}
+		case "inlineCard", "blockCard":
+			b.WriteString("<" + n.attr("url") + ">")
+		case "date":
+			b.WriteString(n.attr("timestamp"))
+		case "status":
+			b.WriteString("[" + n.attr("text") + "]")
+		default:
+			b.WriteString(adfInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+func adfMarks(text string, marks []adfMark) string {
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "code":
+			text = "`" + text + "`"
+		case "link":
+			if href, ok := m.Attrs["href"].(string); ok {
+				text = "[" + text + "](" + href + ")"
+			}
+		}
+	}
+	return text
+}
+
+var (
+	wikiHeadingRe   = regexp.MustCompile(`^h([1-6])\.\s*`)
+	wikiListRe      = regexp.MustCompile(`^([*#-]+)\s+`)
+	wikiCodeStartRe = regexp.MustCompile(`^\{(code|noformat)(?::([^}|]*))?[^}]*\}`)
+	wikiBoldRe      = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*\S)?)\*([^\w*]|$)`)
+	wikiItalicRe    = regexp.MustCompile(`(^|[^\w_])_(\S(?:[^_]*\S)?)_([^\w_]|$)`)
+	wikiMonoRe      = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiLinkRe      = regexp.MustCompile(`\[([^|\]]+)\|([^\]]+)\]`)
+	wikiURLRe       = regexp.MustCompile(`\[((?:https?|mailto):[^\]]+)\]`)
+)
+
+// wikiToMarkdown converts the wiki markup of Jira Server to Markdown: the
+// headings, lists, code blocks, quotes, tables, links and text effects.
+func wikiToMarkdown(wiki string) string {
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(wiki, "\r\n", "\n"), "\n") {
+		if inCode {
+			if strings.HasPrefix(strings.TrimSpace(line), "{code}") || strings.HasPrefix(strings.TrimSpace(line), "{noformat}") {
+				out = append(out, "```")
+				inCode = false
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+		if m := wikiCodeStartRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			language := ""
+			if m[1] == "code" {
+				language = m[2]
+			}
+			out = append(out, "```"+language)
+			inCode = true
+			continue
+		}
+
+		switch {
+		case wikiHeadingRe.MatchString(line):
+			m := wikiHeadingRe.FindStringSubmatch(line)
+			line = strings.Repeat("#", int(m[1][0]-'0')) + " " + wikiInline(line[len(m[0]):])
+		case strings.HasPrefix(line, "bq. "):
+			line = "> " + wikiInline(strings.TrimPrefix(line, "bq. "))
+		case strings.TrimSpace(line) == "----":
+			line = "---"
+		case strings.HasPrefix(line, "||"):
+			// Links are converted first, their "|" does not separate cells
+			cells := strings.Split(strings.Trim(wikiInline(line), "|"), "||")
+			line = "| " + strings.Join(cells, " | ") + " |\n" + strings.Repeat("| --- ", len(cells)) + "|"
+		case strings.HasPrefix(line, "|"):
+			cells := strings.Split(strings.Trim(wikiInline(line), "|"), "|")
+			line = "| " + strings.Join(cells, " | ") + " |"
+		case wikiListRe.MatchString(line):
+			m := wikiListRe.FindStringSubmatch(line)
+			marker := "- "
+			if strings.HasSuffix(m[1], "#") {
+				marker = "1. "
+			}
+			line = strings.Repeat("  ", len(m[1])-1) + marker + wikiInline(line[len(m[0]):])
+		default:
+			line = wikiInline(line)
+		}
+		out = append(out, line)
+	}
+	if inCode {
+		out = append(out, "```")
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// wikiInline converts the links and text effects of a line of wiki markup.
+func wikiInline(line string) string {
+	// Code spans first, their content is not markup
+	var spans []string
+	line = wikiMonoRe.ReplaceAllStringFunc(line, func(s string) string {
+		spans = append(spans, "`"+wikiMonoRe.FindStringSubmatch(s)[1]+"`")
+		return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+	})
+
+	line = wikiLinkRe.ReplaceAllString(line, "[$1]($2)")
+	line = wikiURLRe.ReplaceAllString(line, "<$1>")
+	line = wikiBoldRe.ReplaceAllString(line, "$1**$2**$3")
+	line = wikiItalicRe.ReplaceAllString(line, "$1*$2*$3")
+
+	for i, span := range spans {
+		line = strings.Replace(line, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return line
+}
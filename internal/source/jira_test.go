@@ -0,0 +1,265 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeJiraIssue returns issue n as the search API of version 3 returns it,
+// with its description in the Atlassian Document Format.
+func fakeJiraIssue(n int) map[string]any {
+	return map[string]any{
+		"key": fmt.Sprintf("PROJ-%d", n),
+		"fields": map[string]any{
+			"summary": fmt.Sprintf("Issue %d", n),
+			"description": map[string]any{
+				"type":    "doc",
+				"version": 1,
+				"content": []any{map[string]any{
+					"type": "paragraph",
+					"content": []any{
+						map[string]any{"type": "text", "text": "Something is "},
+						map[string]any{"type": "text", "text": "broken", "marks": []any{map[string]any{"type": "strong"}}},
+					},
+				}},
+			},
+			"labels":     []string{"bug"},
+			"components": []any{map[string]any{"name": "api"}},
+			"reporter":   map[string]any{"displayName": "Alice"},
+			"assignee":   map[string]any{"displayName": "Bob"},
+			"comment": map[string]any{"comments": []any{map[string]any{
+				"author":  map[string]any{"displayName": "Carol"},
+				"created": "2024-05-01T10:00:00.000+0200",
+				"body": map[string]any{"type": "doc", "content": []any{map[string]any{
+					"type":    "paragraph",
+					"content": []any{map[string]any{"type": "text", "text": "Same here"}},
+				}}},
+			}}},
+		},
+	}
+}
+
+func TestDiscoverJira(t *testing.T) {
+	var tokens []string
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("jql") != "project = PROJ" {
+			t.Errorf("unexpected JQL %q", r.URL.Query().Get("jql"))
+		}
+		user, password, _ = r.BasicAuth()
+		token := r.URL.Query().Get("nextPageToken")
+		tokens = append(tokens, token)
+		if token == "" {
+			json.NewEncoder(w).Encode(map[string]any{"issues": []any{fakeJiraIssue(1)}, "nextPageToken": "page-2"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"issues": []any{fakeJiraIssue(2)}, "isLast": true})
+	}))
+	defer server.Close()
+
+	s := &JiraSource{BaseURL: server.URL + "/", JQL: "project = PROJ", User: "alice@example.com", Token: "secret"}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(tokens, []string{"", "page-2"}) {
+		t.Errorf("expected the pages to be fetched with their tokens, got %q", tokens)
+	}
+	if user != "alice@example.com" || password != "secret" {
+		t.Errorf("expected basic authentication, got %q:%q", user, password)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	got := items[0]
+	if got.ID != "PROJ-1" || got.Number != 0 || got.Title != "Issue 1" || got.Kind != "JiraIssue" ||
+		got.URL != server.URL+"/browse/PROJ-1" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if got.Body != "Something is **broken**" {
+		t.Errorf("expected the description as Markdown, got %q", got.Body)
+	}
+	if !slices.Equal(got.Labels, []string{"bug"}) || !slices.Equal(got.Components, []string{"api"}) {
+		t.Errorf("unexpected labels %q and components %q", got.Labels, got.Components)
+	}
+	if got.Author != "Alice" || !slices.Equal(got.Assignees, []string{"Bob"}) {
+		t.Errorf("unexpected author %q and assignees %q", got.Author, got.Assignees)
+	}
+	if got.Comments != "Same here" || len(got.CommentList) != 1 {
+		t.Fatalf("unexpected comments %q", got.Comments)
+	}
+	comment := got.CommentList[0]
+	if comment.Author != "Carol" || !comment.CreatedAt.Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected comment: %+v", comment)
+	}
+}
+
+func TestDiscoverJiraServer(t *testing.T) {
+	const total = 5
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/search" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		// Pages of 2, smaller than asked for
+		var issues []any
+		for n := startAt + 1; n <= min(startAt+2, total); n++ {
+			issue := fakeJiraIssue(n)
+			issue["fields"].(map[string]any)["description"] = "h2. Steps\n# Run *it*\n# See {{error}}"
+			issues = append(issues, issue)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"issues": issues, "startAt": startAt, "total": total})
+	}))
+	defer server.Close()
+
+	s := &JiraSource{BaseURL: server.URL, JQL: "project = PROJ", APIVersion: "2", Token: "pat"}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer pat" {
+		t.Errorf("expected the token as bearer token, got %q", auth)
+	}
+	if len(items) != total {
+		t.Fatalf("expected %d items, got %d", total, len(items))
+	}
+	if items[4].ID != "PROJ-5" {
+		t.Errorf("expected the last issue to be PROJ-5, got %s", items[4].ID)
+	}
+	if want := "## Steps\n1. Run **it**\n1. See `error`"; items[0].Body != want {
+		t.Errorf("expected the wiki markup as Markdown %q, got %q", want, items[0].Body)
+	}
+}
+
+func TestDiscoverJiraRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	s := &JiraSource{BaseURL: server.URL, JQL: "project = PROJ"}
+	_, err := s.Discover(context.Background())
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+	if wait := time.Until(rateLimitErr.Reset); wait < 25*time.Second || wait > 30*time.Second {
+		t.Errorf("expected to wait about 30s, got %s", wait)
+	}
+}
+
+func TestDiscoverJiraError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorMessages":["Error in the JQL Query"]}`))
+	}))
+	defer server.Close()
+
+	s := &JiraSource{BaseURL: server.URL, JQL: "project = "}
+	if _, err := s.Discover(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestJiraMarkdownADF(t *testing.T) {
+	doc := `{"type": "doc", "version": 1, "content": [
+		{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Steps"}]},
+		{"type": "orderedList", "content": [
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [
+				{"type": "text", "text": "Run "},
+				{"type": "text", "text": "make test", "marks": [{"type": "code"}]}
+			]}]},
+			{"type": "listItem", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "See the "}, {"type": "text", "text": "docs", "marks": [{"type": "link", "attrs": {"href": "https://example.com"}}]}]},
+				{"type": "bulletList", "content": [{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "nested"}]}]}]}
+			]}
+		]},
+		{"type": "codeBlock", "attrs": {"language": "go"}, "content": [{"type": "text", "text": "panic(err)"}]},
+		{"type": "blockquote", "content": [{"type": "paragraph", "content": [
+			{"type": "mention", "attrs": {"text": "@Alice"}},
+			{"type": "text", "text": " said so"},
+			{"type": "hardBreak"},
+			{"type": "text", "text": "twice"}
+		]}]},
+		{"type": "table", "content": [
+			{"type": "tableRow", "content": [
+				{"type": "tableHeader", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Env"}]}]},
+				{"type": "tableHeader", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Status"}]}]}
+			]},
+			{"type": "tableRow", "content": [
+				{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "prod"}]}]},
+				{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "down", "marks": [{"type": "em"}]}]}]}
+			]}
+		]}
+	]}`
+	want := "## Steps\n" +
+		"\n" +
+		"1. Run `make test`\n" +
+		"2. See the [docs](https://example.com)\n" +
+		"\n" +
+		"   - nested\n" +
+		"\n" +
+		"```go\n" +
+		"panic(err)\n" +
+		"```\n" +
+		"\n" +
+		"> @Alice said so\n" +
+		"> twice\n" +
+		"\n" +
+		"| Env | Status |\n" +
+		"| --- | --- |\n" +
+		"| prod | *down* |"
+	if got := jiraMarkdown(json.RawMessage(doc)); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestJiraMarkdownWiki(t *testing.T) {
+	tests := []struct {
+		name string
+		wiki string
+		want string
+	}{
+		{"heading", "h1. Title", "# Title"},
+		{"effects", "*bold*, _italic_ and {{mono_code*}}", "**bold**, *italic* and `mono_code*`"},
+		{"words", "snake_case_name and 2*3*4", "snake_case_name and 2*3*4"},
+		{"links", "[docs|https://example.com] and [https://example.org]", "[docs](https://example.com) and <https://example.org>"},
+		{"lists", "* one\n** nested\n# first", "- one\n  - nested\n1. first"},
+		{"code", "{code:java}\nint *x* = 1;\n{code}", "```java\nint *x* = 1;\n```"},
+		{"noformat", "{noformat}\n*raw*\n{noformat}", "```\n*raw*\n```"},
+		{"quote", "bq. quoted", "> quoted"},
+		{"table", "||Env||Status||\n|prod|[down|https://status.example.com]|", "| Env | Status |\n| --- | --- |\n| prod | [down](https://status.example.com) |"},
+		{"line endings", "one\r\ntwo", "one\ntwo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(tt.wiki)
+			if got := jiraMarkdown(raw); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJiraMarkdownEmpty(t *testing.T) {
+	for _, raw := range []string{"", "null"} {
+		if got := jiraMarkdown(json.RawMessage(raw)); got != "" {
+			t.Errorf("expected no Markdown for %q, got %q", raw, got)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLinearURL is the URL of Linear's GraphQL API.
+	DefaultLinearURL = "https://api.linear.app/graphql"
+
+	// linearPageSize is the number of issues per page. Every issue comes
+	// with its comments.
+	linearPageSize = 50
+
+	linearIssuesQuery = `query($filter: IssueFilter, $first: Int!, $after: String) {
+	issues(filter: $filter, first: $first, after: $after) {
+		pageInfo { hasNextPage endCursor }
+		nodes {
+			identifier
+			title
+			description
+			url
+			creator { name }
+			assignee { name }
+			labels(first: 100) { nodes { name } }
+			comments(first: 100) { nodes { body createdAt user { name } } }
+		}
+	}
+}`
+
+	linearIssueStatesQuery = `query($id: String!, $state: String!) {
+	issue(id: $id) {
+		id
+		team { states(filter: { name: { eqIgnoreCase: $state } }) { nodes { id } } }
+	}
+}`
+
+	linearIssueIDQuery = `query($id: String!) {
+	issue(id: $id) { id }
+}`
+
+	linearUpdateStateMutation = `mutation($id: String!, $stateId: String!) {
+	issueUpdate(id: $id, input: { stateId: $stateId }) { success }
+}`
+
+	linearCreateCommentMutation = `mutation($issueId: String!, $body: String!) {
+	commentCreate(input: { issueId: $issueId, body: $body }) { success }
+}`
+)
+
+// LinearSource discovers the issues of a Linear team. Issues are identified
+// by their identifiers, like "ENG-123", and can be moved to another
+// workflow state and commented on once their Task finished.
+type LinearSource struct {
+	// Team is the key of the team, like "ENG".
+	Team string
+	// States are the names of the workflow states of the issues to
+	// discover. Defaults to the states that are neither completed nor
+	// canceled.
+	States []string
+	// Labels are the names of labels the issues must all have.
+	Labels []string
+	// Assignee is the email address of the user the issues are assigned
+	// to. "none" selects unassigned issues.
+	Assignee string
+
+	// APIKey is sent as is in the Authorization header: a personal API
+	// key, or an OAuth access token prefixed with "Bearer ".
+	APIKey string
+	// BaseURL is the URL of the GraphQL API. Defaults to DefaultLinearURL.
+	BaseURL string
+	Client  *http.Client
+}
+
+type linearIssue struct {
+	Identifier  string      `json:"identifier"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	URL         string      `json:"url"`
+	Creator     *linearUser `json:"creator"`
+	Assignee    *linearUser `json:"assignee"`
+	Labels      struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Comments struct {
+		Nodes []linearComment `json:"nodes"`
+	} `json:"comments"`
+}
+
+type linearUser struct {
+	Name string `json:"name"`
+}
+
+type linearComment struct {
+	Body      string      `json:"body"`
+	CreatedAt time.Time   `json:"createdAt"`
+	User      *linearUser `json:"user"`
+}
+
+type linearError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+// Discover fetches the issues of the team that match the filters and
+// returns them as WorkItems.
+func (s *LinearSource) Discover(ctx context.Context) ([]WorkItem, error) {
+	var items []WorkItem
+	vars := map[string]any{
+		"filter": s.filter(),
+		"first":  linearPageSize,
+	}
+	for range maxPages {
+		var data struct {
+			Issues struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []linearIssue `json:"nodes"`
+			} `json:"issues"`
+		}
+		if err := s.query(ctx, linearIssuesQuery, vars, &data); err != nil {
+			return nil, fmt.Errorf("listing Linear issues: %w", err)
+		}
+		for i := range data.Issues.Nodes {
+			items = append(items, data.Issues.Nodes[i].workItem())
+		}
+		if !data.Issues.PageInfo.HasNextPage {
+			break
+		}
+		vars["after"] = data.Issues.PageInfo.EndCursor
+	}
+	return items, nil
+}
+
+// filter returns the IssueFilter of the team, states, labels and assignee.
+func (s *LinearSource) filter() map[string]any {
+	filter := map[string]any{
+		"team": map[string]any{"key": map[string]any{"eq": s.Team}},
+	}
+	if len(s.States) > 0 {
+		filter["state"] = map[string]any{"name": map[string]any{"in": s.States}}
+	} else {
+		filter["state"] = map[string]any{"type": map[string]any{"nin": []string{"completed", "canceled"}}}
+	}
+	switch s.Assignee {
+	case "":
+	case "none":
+		filter["assignee"] = map[string]any{"null": true}
+	default:
+		filter["assignee"] = map[string]any{"email": map[string]any{"eq": s.Assignee}}
+	}
+	var labels []any
+	for _, l := range s.Labels {
+		labels = append(labels, map[string]any{"labels": map[string]any{"some": map[string]any{"name": map[string]any{"eqIgnoreCase": l}}}})
+	}
+	if len(labels) > 0 {
+		filter["and"] = labels
+	}
+	return filter
+}
+
+// workItem returns the issue as a WorkItem. Comments are ordered oldest
+// first, and only the first maxCommentBytes of them are included.
+func (issue *linearIssue) workItem() WorkItem {
+	item := WorkItem{
+		ID:    issue.Identifier,
+		Title: issue.Title,
+		Body:  issue.Description,
+		URL:   issue.URL,
+		Kind:  "LinearIssue",
+	}
+	for _, l := range issue.Labels.Nodes {
+		item.Labels = append(item.Labels, l.Name)
+	}
+	if issue.Creator != nil {
+		item.Author = issue.Creator.Name
+	}
+	if issue.Assignee != nil {
+		item.Assignees = []string{issue.Assignee.Name}
+	}
+
+	comments := issue.Comments.Nodes
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+	var parts []string
+	totalBytes := 0
+	for _, c := range comments {
+		totalBytes += len(c.Body)
+		if totalBytes > maxCommentBytes {
+			break
+		}
+		comment := IssueComment{Body: c.Body, CreatedAt: c.CreatedAt}
+		if c.User != nil {
+			comment.Author = c.User.Name
+		}
+		parts = append(parts, c.Body)
+		item.CommentList = append(item.CommentList, comment)
+	}
+	item.Comments = strings.Join(parts, "\n---\n")
+	return item
+}
+
+// SetState moves the issue with the given identifier to the workflow state
+// of its team with the given name.
+func (s *LinearSource) SetState(ctx context.Context, identifier, state string) error {
+	var data struct {
+		Issue struct {
+			ID   string `json:"id"`
+			Team struct {
+				States struct {
+					Nodes []struct {
+						ID string `json:"id"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	}
+	if err := s.query(ctx, linearIssueStatesQuery, map[string]any{"id": identifier, "state": state}, &data); err != nil {
+		return fmt.Errorf("fetching the workflow states of %s: %w", identifier, err)
+	}
+	states := data.Issue.Team.States.Nodes
+	if len(states) == 0 {
+		return fmt.Errorf("the team of %s has no workflow state %q", identifier, state)
+	}
+
+	var result struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+	if err := s.query(ctx, linearUpdateStateMutation, map[string]any{"id": data.Issue.ID, "stateId": states[0].ID}, &result); err != nil {
+		return fmt.Errorf("moving %s to %s: %w", identifier, state, err)
+	}
+	if !result.IssueUpdate.Success {
+		return fmt.Errorf("moving %s to %s failed", identifier, state)
+	}
+	return nil
+}
+
+// CreateComment posts a comment on the issue with the given identifier.
+func (s *LinearSource) CreateComment(ctx context.Context, identifier, body string) error {
+	var data struct {
+		Issue struct {
+			ID string `json:"id"`
+		} `json:"issue"`
+	}
+	if err := s.query(ctx, linearIssueIDQuery, map[string]any{"id": identifier}, &data); err != nil {
+		return fmt.Errorf("fetching %s: %w", identifier, err)
+	}
+
+	var result struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	if err := s.query(ctx, linearCreateCommentMutation, map[string]any{"issueId": data.Issue.ID, "body": body}, &result); err != nil {
+		return fmt.Errorf("commenting on %s: %w", identifier, err)
+	}
+	if !result.CommentCreate.Success {
+		return fmt.Errorf("commenting on %s failed", identifier)
+	}
+	return nil
+}
+
+// query sends the GraphQL query or mutation and decodes the data of the
+// response into v.
+func (s *LinearSource) query(ctx context.Context, query string, vars map[string]any, v any) error {
+	payload, err := json.Marshal(graphQLRequest{Query: query, Variables: vars})
+	if err != nil {
+		return fmt.Errorf("encoding query: %w", err)
+	}
+
+	u := s.BaseURL
+	if u == "" {
+		u = DefaultLinearURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", s.APIKey)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Errors, including exceeding the rate limit, come with 400 Bad
+	// Request and are described in the body
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []linearError   `json:"errors"`
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return &RateLimitError{Reset: linearRateLimitReset(resp.Header)}
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Linear API returned status %d: %s", resp.StatusCode, string(body[:min(len(body), 1024)]))
+		}
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		var messages []string
+		for _, e := range result.Errors {
+			if e.Extensions.Code == "RATELIMITED" {
+				return &RateLimitError{Reset: linearRateLimitReset(resp.Header)}
+			}
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("Linear API returned errors: %s", strings.Join(messages, "; "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Linear API returned status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(result.Data, v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// linearRateLimitReset returns when the request rate limit resets, from the
+// time in milliseconds since the epoch Linear reports it at, or in a minute
+// if it is not reported.
+func linearRateLimitReset(header http.Header) time.Time {
+	if ms, err := strconv.ParseInt(header.Get("X-RateLimit-Requests-Reset"), 10, 64); err == nil {
+		return time.UnixMilli(ms)
+	}
+	return time.Now().Add(time.Minute)
+}
@@ -0,0 +1,249 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLinear answers the queries and mutations of a LinearSource like
+// Linear's GraphQL API, for the issues of a team with the workflow states
+// "Todo" and "Done".
+type fakeLinear struct {
+	mu       sync.Mutex
+	issues   []map[string]any
+	pageSize int
+
+	filters  []map[string]any
+	auth     string
+	states   map[string]string
+	comments map[string][]string
+}
+
+func newFakeLinear(t *testing.T, issues ...map[string]any) (*fakeLinear, *httptest.Server) {
+	f := &fakeLinear{issues: issues, pageSize: 100, states: map[string]string{}, comments: map[string][]string{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func fakeLinearIssue(n int) map[string]any {
+	return map[string]any{
+		"identifier":  fmt.Sprintf("ENG-%d", n),
+		"title":       fmt.Sprintf("Issue %d", n),
+		"description": "Something is **broken**",
+		"url":         fmt.Sprintf("https://linear.app/acme/issue/ENG-%d", n),
+		"creator":     map[string]any{"name": "Alice"},
+		"assignee":    nil,
+		"labels":      map[string]any{"nodes": []any{map[string]any{"name": "Agent-ready"}}},
+		"comments": map[string]any{"nodes": []any{
+			map[string]any{"body": "Second", "createdAt": "2024-05-02T10:00:00.000Z", "user": map[string]any{"name": "Carol"}},
+			map[string]any{"body": "First", "createdAt": "2024-05-01T10:00:00.000Z", "user": map[string]any{"name": "Bob"}},
+		}},
+	}
+}
+
+func (f *fakeLinear) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth = r.Header.Get("Authorization")
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, _ := req.Variables["id"].(string)
+	// Issues are looked up by identifier, and updated by ID
+	uuid := "uuid-" + id
+
+	var data any
+	switch {
+	case strings.Contains(req.Query, "issues("):
+		filter, _ := req.Variables["filter"].(map[string]any)
+		f.filters = append(f.filters, filter)
+		start := 0
+		if after, ok := req.Variables["after"].(string); ok {
+			start, _ = strconv.Atoi(after)
+		}
+		end := min(start+f.pageSize, len(f.issues))
+		data = map[string]any{"issues": map[string]any{
+			"pageInfo": map[string]any{"hasNextPage": end < len(f.issues), "endCursor": strconv.Itoa(end)},
+			"nodes":    f.issues[start:end],
+		}}
+	case strings.Contains(req.Query, "states("):
+		var states []any
+		if name := req.Variables["state"].(string); strings.EqualFold(name, "done") || strings.EqualFold(name, "todo") {
+			states = append(states, map[string]any{"id": "state-" + strings.ToLower(name)})
+		}
+		data = map[string]any{"issue": map[string]any{"id": uuid, "team": map[string]any{"states": map[string]any{"nodes": states}}}}
+	case strings.Contains(req.Query, "issueUpdate("):
+		f.states[req.Variables["id"].(string)] = req.Variables["stateId"].(string)
+		data = map[string]any{"issueUpdate": map[string]any{"success": true}}
+	case strings.Contains(req.Query, "commentCreate("):
+		issueID := req.Variables["issueId"].(string)
+		f.comments[issueID] = append(f.comments[issueID], req.Variables["body"].(string))
+		data = map[string]any{"commentCreate": map[string]any{"success": true}}
+	case strings.Contains(req.Query, "issue("):
+		data = map[string]any{"issue": map[string]any{"id": uuid}}
+	default:
+		http.Error(w, "unexpected query "+req.Query, http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func TestDiscoverLinear(t *testing.T) {
+	f, srv := newFakeLinear(t, fakeLinearIssue(1), fakeLinearIssue(2), fakeLinearIssue(3))
+	f.pageSize = 2
+
+	s := &LinearSource{
+		Team:     "ENG",
+		Labels:   []string{"Agent-ready"},
+		Assignee: "none",
+		APIKey:   "lin_api_secret",
+		BaseURL:  srv.URL,
+	}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.auth != "lin_api_secret" {
+		t.Errorf("expected the API key in the Authorization header, got %q", f.auth)
+	}
+	if len(items) != 3 || len(f.filters) != 2 {
+		t.Fatalf("expected 3 items in 2 pages, got %d items in %d pages", len(items), len(f.filters))
+	}
+
+	got := items[0]
+	if got.ID != "ENG-1" || got.Number != 0 || got.Title != "Issue 1" || got.Body != "Something is **broken**" ||
+		got.URL != "https://linear.app/acme/issue/ENG-1" || got.Kind != "LinearIssue" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if !slices.Equal(got.Labels, []string{"Agent-ready"}) || got.Author != "Alice" || len(got.Assignees) != 0 {
+		t.Errorf("unexpected labels %q, author %q or assignees %q", got.Labels, got.Author, got.Assignees)
+	}
+	if got.Comments != "First\n---\nSecond" {
+		t.Errorf("expected the comments oldest first, got %q", got.Comments)
+	}
+	if len(got.CommentList) != 2 || got.CommentList[0].Author != "Bob" ||
+		!got.CommentList[0].CreatedAt.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected comment list: %+v", got.CommentList)
+	}
+
+	filter, _ := json.Marshal(f.filters[0])
+	want := `{"and":[{"labels":{"some":{"name":{"eqIgnoreCase":"Agent-ready"}}}}],"assignee":{"null":true},` +
+		`"state":{"type":{"nin":["completed","canceled"]}},"team":{"key":{"eq":"ENG"}}}`
+	if string(filter) != want {
+		t.Errorf("expected the filter %s, got %s", want, filter)
+	}
+}
+
+func TestLinearFilter(t *testing.T) {
+	s := &LinearSource{Team: "ENG", States: []string{"Todo", "In Review"}, Assignee: "alice@example.com"}
+	filter, _ := json.Marshal(s.filter())
+	want := `{"assignee":{"email":{"eq":"alice@example.com"}},"state":{"name":{"in":["Todo","In Review"]}},"team":{"key":{"eq":"ENG"}}}`
+	if string(filter) != want {
+		t.Errorf("expected the filter %s, got %s", want, filter)
+	}
+}
+
+func TestDiscoverLinearErrors(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second).Truncate(time.Millisecond)
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		body   string
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name:   "rate limited",
+			status: http.StatusBadRequest,
+			header: http.Header{"X-Ratelimit-Requests-Reset": {strconv.FormatInt(reset.UnixMilli(), 10)}},
+			body:   `{"errors":[{"message":"Rate limit exceeded","extensions":{"code":"RATELIMITED"}}]}`,
+			check: func(t *testing.T, err error) {
+				var rateLimitErr *RateLimitError
+				if !errors.As(err, &rateLimitErr) {
+					t.Fatalf("expected a RateLimitError, got %v", err)
+				}
+				if !rateLimitErr.Reset.Equal(reset) {
+					t.Errorf("expected the reset %s, got %s", reset, rateLimitErr.Reset)
+				}
+			},
+		},
+		{
+			name:   "GraphQL error",
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"message":"Unknown argument","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}]}`,
+			check: func(t *testing.T, err error) {
+				if err == nil || !strings.Contains(err.Error(), "Unknown argument") {
+					t.Errorf("expected the GraphQL error, got %v", err)
+				}
+			},
+		},
+		{
+			name:   "unauthenticated",
+			status: http.StatusUnauthorized,
+			body:   "Unauthorized",
+			check: func(t *testing.T, err error) {
+				if err == nil || !strings.Contains(err.Error(), "401") {
+					t.Errorf("expected the status in the error, got %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, values := range tt.header {
+					w.Header()[name] = values
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			s := &LinearSource{Team: "ENG", BaseURL: srv.URL}
+			_, err := s.Discover(context.Background())
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestLinearSetState(t *testing.T) {
+	f, srv := newFakeLinear(t)
+	s := &LinearSource{Team: "ENG", BaseURL: srv.URL}
+
+	if err := s.SetState(context.Background(), "ENG-1", "Done"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.states["uuid-ENG-1"] != "state-done" {
+		t.Errorf("expected ENG-1 to be moved to Done, got %v", f.states)
+	}
+
+	if err := s.SetState(context.Background(), "ENG-1", "Shipped"); err == nil || !strings.Contains(err.Error(), `"Shipped"`) {
+		t.Errorf("expected an error for an unknown state, got %v", err)
+	}
+}
+
+func TestLinearCreateComment(t *testing.T) {
+	f, srv := newFakeLinear(t)
+	s := &LinearSource{Team: "ENG", BaseURL: srv.URL}
+
+	if err := s.CreateComment(context.Background(), "ENG-1", "Task succeeded"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(f.comments["uuid-ENG-1"], []string{"Task succeeded"}) {
+		t.Errorf("expected a comment on ENG-1, got %v", f.comments)
+	}
+}
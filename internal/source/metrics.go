@@ -0,0 +1,46 @@
+package source
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	githubRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "axon_github_api_requests_total",
+		Help: "Number of GitHub API responses by operation and status code.",
+	}, []string{"operation", "code"})
+
+	githubErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "axon_github_api_errors_total",
+		Help: "Number of GitHub API requests that failed or returned an error status.",
+	}, []string{"operation"})
+
+	githubRateLimitRemaining = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "axon_github_rate_limit_remaining",
+		Help: "Number of requests remaining in the current GitHub API rate limit window.",
+	})
+)
+
+// Collectors returns the collectors of the metrics recorded by sources.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{githubRequests, githubErrors, githubRateLimitRemaining}
+}
+
+// recordGitHubResponse records a GitHub API response, or the error that
+// prevented one, in the metrics.
+func recordGitHubResponse(operation string, resp *http.Response, err error) {
+	if err != nil {
+		githubErrors.WithLabelValues(operation).Inc()
+		return
+	}
+	githubRequests.WithLabelValues(operation, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode >= http.StatusBadRequest {
+		githubErrors.WithLabelValues(operation).Inc()
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		githubRateLimitRemaining.Set(float64(remaining))
+	}
+}
@@ -0,0 +1,45 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGitHubMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode([]githubIssue{})
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}
+
+	listed := testutil.ToFloat64(githubRequests.WithLabelValues("list_issues", "200"))
+	failed := testutil.ToFloat64(githubErrors.WithLabelValues("create_comment"))
+
+	if _, err := s.Discover(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.CreateComment(context.Background(), 1, "hello"); err == nil {
+		t.Fatal("expected error for forbidden comment")
+	}
+
+	if got := testutil.ToFloat64(githubRequests.WithLabelValues("list_issues", "200")) - listed; got != 1 {
+		t.Errorf("list_issues requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(githubErrors.WithLabelValues("create_comment")) - failed; got != 1 {
+		t.Errorf("create_comment errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(githubRateLimitRemaining); got != 4321 {
+		t.Errorf("rate limit remaining = %v, want 4321", got)
+	}
+}
@@ -0,0 +1,49 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultPollInterval is how often a source is polled if the TaskSpawner
+// does not set a poll interval.
+const DefaultPollInterval = 5 * time.Minute
+
+// ParsePollInterval parses the poll interval of a TaskSpawner, either a
+// duration such as "5m" or a plain number of seconds. An empty interval is
+// DefaultPollInterval.
+func ParsePollInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultPollInterval, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		n, atoiErr := strconv.Atoi(s)
+		if atoiErr != nil {
+			return 0, fmt.Errorf("invalid poll interval %q: must be a duration such as 5m or a number of seconds", s)
+		}
+		d = time.Duration(n) * time.Second
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid poll interval %q: must be positive", s)
+	}
+	return d, nil
+}
+
+// PaceInterval returns how long to wait before the next poll, given the
+// TaskSpawner's poll interval, the state of the rate limit, and how many
+// requests the last poll cost. Polls are spread over the rest of the rate
+// limit window so that they do not exhaust it, and wait for the window to
+// end if not even one more poll can be afforded.
+func PaceInterval(interval time.Duration, rl RateLimit, cost int, now time.Time) time.Duration {
+	untilReset := rl.Reset.Sub(now)
+	if rl.Limit <= 0 || untilReset <= 0 {
+		return interval
+	}
+	affordable := rl.Remaining / max(cost, 1)
+	if affordable == 0 {
+		return untilReset
+	}
+	return max(interval, untilReset/time.Duration(affordable))
+}
@@ -0,0 +1,67 @@
+package source
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePollInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{interval: "", want: DefaultPollInterval},
+		{interval: "30s", want: 30 * time.Second},
+		{interval: "1h", want: time.Hour},
+		{interval: "90", want: 90 * time.Second},
+		{interval: "5 minutes", wantErr: true},
+		{interval: "0s", wantErr: true},
+		{interval: "-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			got, err := ParsePollInterval(tt.interval)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePollInterval(%q) = %v, want %v", tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaceInterval(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	reset := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		rl   RateLimit
+		cost int
+		want time.Duration
+	}{
+		{name: "Unknown rate limit", rl: RateLimit{}, cost: 300, want: time.Minute},
+		{name: "Window already reset", rl: RateLimit{Limit: 5000, Remaining: 0, Reset: now.Add(-time.Second)}, cost: 300, want: time.Minute},
+		{name: "Plenty left", rl: RateLimit{Limit: 5000, Remaining: 4000, Reset: reset}, cost: 1, want: time.Minute},
+		{name: "Cached poll is free", rl: RateLimit{Limit: 5000, Remaining: 100, Reset: reset}, cost: 0, want: time.Minute},
+		{name: "Spread over the window", rl: RateLimit{Limit: 5000, Remaining: 4800, Reset: reset}, cost: 301, want: 4 * time.Minute},
+		{name: "Not enough for one more poll", rl: RateLimit{Limit: 5000, Remaining: 200, Reset: reset}, cost: 301, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PaceInterval(time.Minute, tt.rl, tt.cost, now); got != tt.want {
+				t.Errorf("PaceInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
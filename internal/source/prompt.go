@@ -7,13 +7,25 @@ import (
 	"text/template"
 )
 
-const defaultPromptTemplate = `{{.Kind}} #{{.Number}}: {{.Title}}
+const defaultPromptTemplate = `{{if .Number}}{{.Kind}} #{{.Number}}: {{else if or (eq .Kind "JiraIssue") (eq .Kind "LinearIssue")}}{{.ID}}: {{end}}{{.Title}}
 
 {{.Body}}
 {{- if .Comments}}
 
 Comments:
 {{.Comments}}
+{{- end}}
+{{- if .Logs}}
+
+Failed jobs:
+{{.FailedChecks}}
+
+Logs:
+{{.Logs}}
+{{- end}}
+{{- if .Args}}
+
+Request from @{{.Requester}}: {{.Args}}
 {{- end}}`
 
 // RenderPrompt renders a prompt for the given work item using the provided template.
@@ -34,24 +46,63 @@ func RenderPrompt(promptTemplate string, item WorkItem) (string, error) {
 		kind = "Issue"
 	}
 
+	var linkedPRs []string
+	for _, n := range item.LinkedPRs {
+		linkedPRs = append(linkedPRs, fmt.Sprintf("#%d", n))
+	}
+
 	data := struct {
-		ID       string
-		Number   int
-		Title    string
-		Body     string
-		URL      string
-		Labels   string
-		Comments string
-		Kind     string
+		ID             string
+		Number         int
+		Title          string
+		Body           string
+		URL            string
+		Labels         string
+		Comments       string
+		Kind           string
+		Author         string
+		Assignees      string
+		Milestone      string
+		LinkedPRs      string
+		CommentList    []IssueComment
+		Branch         string
+		BaseBranch     string
+		Diff           string
+		Reviews        string
+		ReviewComments string
+		FailedChecks   string
+		Workflow       string
+		Commit         string
+		Logs           string
+		Components     string
+		Args           string
+		Requester      string
 	}{
-		ID:       item.ID,
-		Number:   item.Number,
-		Title:    item.Title,
-		Body:     item.Body,
-		URL:      item.URL,
-		Labels:   strings.Join(item.Labels, ", "),
-		Comments: item.Comments,
-		Kind:     kind,
+		ID:             item.ID,
+		Number:         item.Number,
+		Title:          item.Title,
+		Body:           item.Body,
+		URL:            item.URL,
+		Labels:         strings.Join(item.Labels, ", "),
+		Comments:       item.Comments,
+		Kind:           kind,
+		Author:         item.Author,
+		Assignees:      strings.Join(item.Assignees, ", "),
+		Milestone:      item.Milestone,
+		LinkedPRs:      strings.Join(linkedPRs, ", "),
+		CommentList:    item.CommentList,
+		Branch:         item.Branch,
+		BaseBranch:     item.BaseBranch,
+		Diff:           item.Diff,
+		Reviews:        item.Reviews,
+		ReviewComments: item.ReviewComments,
+		FailedChecks:   item.FailedChecks,
+		Workflow:       item.Workflow,
+		Commit:         item.Commit,
+		Logs:           item.Logs,
+		Components:     strings.Join(item.Components, ", "),
+		Args:           item.Args,
+		Requester:      item.Requester,
 	}
 
 	var buf bytes.Buffer
@@ -142,6 +142,76 @@ func TestRenderPromptAllVariables(t *testing.T) {
 	}
 }
 
+func TestRenderPromptDetails(t *testing.T) {
+	item := WorkItem{
+		Number:    7,
+		Author:    "alice",
+		Assignees: []string{"bob", "carol"},
+		Milestone: "v1.0",
+		LinkedPRs: []int{12, 34},
+		CommentList: []IssueComment{
+			{Author: "bob", Body: "Looking into it"},
+			{Author: "alice", Body: "Thanks"},
+		},
+	}
+
+	tmpl := "#{{.Number}} by {{.Author}}, assigned to {{.Assignees}} in {{.Milestone}}, fixed by {{.LinkedPRs}}\n" +
+		"{{range .CommentList}}{{.Author}}: {{.Body}}\n{{end}}"
+	result, err := RenderPrompt(tmpl, item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "#7 by alice, assigned to bob, carol in v1.0, fixed by #12, #34\nbob: Looking into it\nalice: Thanks\n"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestRenderPromptPullRequest(t *testing.T) {
+	item := WorkItem{
+		Number:         9,
+		Kind:           "PR",
+		Branch:         "fix-flake",
+		BaseBranch:     "main",
+		Reviews:        "@alice (CHANGES_REQUESTED):\nAdd a test",
+		ReviewComments: "@alice on main.go:12:\nHandle the error",
+		FailedChecks:   "test: failure",
+	}
+
+	tmpl := "Fix #{{.Number}} on {{.Branch}} into {{.BaseBranch}}\n{{.Reviews}}\n{{.ReviewComments}}\n{{if .FailedChecks}}Failing:\n{{.FailedChecks}}{{end}}"
+	result, err := RenderPrompt(tmpl, item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Fix #9 on fix-flake into main\n@alice (CHANGES_REQUESTED):\nAdd a test\n@alice on main.go:12:\nHandle the error\nFailing:\ntest: failure"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestRenderPromptDefaultCommand(t *testing.T) {
+	item := WorkItem{
+		Number:    5,
+		Title:     "Flaky test",
+		Body:      "It fails",
+		Kind:      "PR",
+		Args:      "review focus on tests",
+		Requester: "alice",
+	}
+
+	result, err := RenderPrompt("", item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "PR #5: Flaky test\n\nIt fails\n\nRequest from @alice: review focus on tests"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
 func TestRenderPromptInvalidTemplate(t *testing.T) {
 	item := WorkItem{}
 
@@ -150,3 +220,57 @@ func TestRenderPromptInvalidTemplate(t *testing.T) {
 		t.Fatal("expected error for invalid template")
 	}
 }
+
+func TestRenderPromptDefaultWorkflowRun(t *testing.T) {
+	item := WorkItem{
+		ID:           "8",
+		Title:        "CI failed on main",
+		Body:         "Add login",
+		Kind:         "WorkflowRun",
+		FailedChecks: `test: failure at step "Run tests"`,
+		Logs:         "=== test ===\n--- FAIL: TestLogin",
+	}
+
+	result, err := RenderPrompt("", item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "CI failed on main\n\nAdd login\n\nFailed jobs:\ntest: failure at step \"Run tests\"\n\nLogs:\n=== test ===\n--- FAIL: TestLogin"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestRenderPromptDefaultJiraIssue(t *testing.T) {
+	item := WorkItem{
+		ID:         "PROJ-123",
+		Title:      "Login fails",
+		Body:       "Steps to reproduce",
+		Kind:       "JiraIssue",
+		Components: []string{"api", "web"},
+	}
+
+	result, err := RenderPrompt("", item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "PROJ-123: Login fails\n\nSteps to reproduce"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+
+	item.Kind = "LinearIssue"
+	if result, _ := RenderPrompt("", item); result != expected {
+		t.Errorf("expected %q for a Linear issue, got %q", expected, result)
+	}
+
+	item.Kind = "JiraIssue"
+	result, err = RenderPrompt("{{.ID}} in {{.Components}}", item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "PROJ-123 in api, web" {
+		t.Errorf("unexpected prompt %q", result)
+	}
+}
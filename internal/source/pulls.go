@@ -0,0 +1,204 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxDiffBytes limits the size of the diff of a pull request.
+	maxDiffBytes = 64 * 1024
+
+	// githubDiff is the media type of pull requests as a diff.
+	githubDiff = "application/vnd.github.v3.diff"
+)
+
+// failedCheckConclusions are the conclusions of check runs that failed.
+var failedCheckConclusions = []string{"failure", "timed_out", "startup_failure", "action_required"}
+
+type githubPullRequest struct {
+	Head githubRef `json:"head"`
+	Base githubRef `json:"base"`
+}
+
+type githubRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type githubReview struct {
+	User  githubUser `json:"user"`
+	Body  string     `json:"body"`
+	State string     `json:"state"`
+}
+
+type githubReviewComment struct {
+	User         githubUser `json:"user"`
+	Body         string     `json:"body"`
+	Path         string     `json:"path"`
+	Line         *int       `json:"line"`
+	OriginalLine *int       `json:"original_line"`
+}
+
+type githubCheckRuns struct {
+	CheckRuns []githubCheckRun `json:"check_runs"`
+}
+
+type githubCheckRun struct {
+	Name       string `json:"name"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+	Output     struct {
+		Title string `json:"title"`
+	} `json:"output"`
+}
+
+// addPullRequestDetails adds the branches, diff, reviews and failed checks
+// of the pull requests among the items. None of them count as an update of
+// the pull request, a check run finishing in particular, so they are
+// fetched on every discovery; with a Cache, the requests for those that did
+// not change are free.
+func (s *GitHubSource) addPullRequestDetails(ctx context.Context, items []WorkItem) error {
+	for i := range items {
+		if items[i].Kind != "PR" {
+			continue
+		}
+		if err := s.fetchPullRequestDetails(ctx, &items[i]); err != nil {
+			return fmt.Errorf("fetching pull request #%d: %w", items[i].Number, err)
+		}
+	}
+	return nil
+}
+
+func (s *GitHubSource) fetchPullRequestDetails(ctx context.Context, item *WorkItem) error {
+	prPath := fmt.Sprintf("pulls/%d", item.Number)
+
+	var pr githubPullRequest
+	if err := s.get(ctx, prPath, "get_pull", &pr); err != nil {
+		return err
+	}
+	item.Branch = pr.Head.Ref
+	item.BaseBranch = pr.Base.Ref
+
+	diff, err := s.fetchDiff(ctx, prPath)
+	if err != nil {
+		return err
+	}
+	item.Diff = diff
+
+	var reviews []githubReview
+	if err := s.get(ctx, prPath+"/reviews?per_page=100", "list_reviews", &reviews); err != nil {
+		return err
+	}
+	var parts []string
+	for _, r := range reviews {
+		if r.Body == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("@%s (%s):\n%s", r.User.Login, r.State, r.Body))
+	}
+	item.Reviews = strings.Join(parts, "\n---\n")
+
+	var comments []githubReviewComment
+	if err := s.get(ctx, prPath+"/comments?per_page=100", "list_review_comments", &comments); err != nil {
+		return err
+	}
+	parts = nil
+	totalBytes := 0
+	for _, c := range comments {
+		totalBytes += len(c.Body)
+		if totalBytes > maxCommentBytes {
+			break
+		}
+		// Comments on lines the pull request no longer changes are
+		// outdated and only have the line they were made on
+		line := c.Line
+		if line == nil {
+			line = c.OriginalLine
+		}
+		location := c.Path
+		if line != nil {
+			location = fmt.Sprintf("%s:%d", c.Path, *line)
+		}
+		parts = append(parts, fmt.Sprintf("@%s on %s:\n%s", c.User.Login, location, c.Body))
+	}
+	item.ReviewComments = strings.Join(parts, "\n---\n")
+
+	var checks githubCheckRuns
+	if err := s.get(ctx, fmt.Sprintf("commits/%s/check-runs?filter=latest&per_page=100", pr.Head.SHA), "list_check_runs", &checks); err != nil {
+		return err
+	}
+	parts = nil
+	for _, c := range checks.CheckRuns {
+		if !slices.Contains(failedCheckConclusions, c.Conclusion) {
+			continue
+		}
+		line := fmt.Sprintf("%s: %s", c.Name, c.Conclusion)
+		if c.Output.Title != "" {
+			line += " - " + c.Output.Title
+		}
+		if c.HTMLURL != "" {
+			line += " (" + c.HTMLURL + ")"
+		}
+		parts = append(parts, line)
+	}
+	item.FailedChecks = strings.Join(parts, "\n")
+	return nil
+}
+
+// fetchDiff returns the diff of the pull request, cut at maxDiffBytes.
+func (s *GitHubSource) fetchDiff(ctx context.Context, prPath string) (string, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/%s", s.baseURL(), s.Owner, s.Repo, prPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", githubDiff)
+
+	resp, err := s.do(req, "get_pull_diff")
+	if err != nil {
+		return "", fmt.Errorf("fetching diff: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", githubAPIError(resp)
+	}
+
+	diff, err := io.ReadAll(io.LimitReader(resp.Body, maxDiffBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("reading diff: %w", err)
+	}
+	if len(diff) > maxDiffBytes {
+		return string(diff[:maxDiffBytes]) + "\n[diff truncated]", nil
+	}
+	return string(diff), nil
+}
+
+// get fetches the JSON resource at the path below the repository into v.
+func (s *GitHubSource) get(ctx context.Context, path, operation string, v any) error {
+	u := fmt.Sprintf("%s/repos/%s/%s/%s", s.baseURL(), s.Owner, s.Repo, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, operation)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return githubAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakePullRequest serves the details of pull requests like the GitHub API:
+// the pull request as JSON or as a diff, its reviews, its review comments
+// and the check runs of its head commit. Every pull request has the branch
+// "feature-<number>" and the head commit "sha<number>".
+type fakePullRequest struct {
+	diff           string
+	reviews        []githubReview
+	reviewComments []githubReviewComment
+	checkRuns      []githubCheckRun
+}
+
+// serve answers the request if it is for the details of a pull request, and
+// reports whether it was.
+func (f *fakePullRequest) serve(w http.ResponseWriter, r *http.Request) bool {
+	path, ok := strings.CutPrefix(r.URL.Path, "/repos/owner/repo/")
+	if !ok {
+		return false
+	}
+	parts := strings.Split(path, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "pulls":
+		// The Accept header is part of the cache key, so the ETags of
+		// both representations must differ
+		etag := fmt.Sprintf(`"%s-%s"`, parts[1], r.Header.Get("Accept"))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+		if r.Header.Get("Accept") == githubDiff {
+			fmt.Fprint(w, f.diff)
+			return true
+		}
+		json.NewEncoder(w).Encode(githubPullRequest{
+			Head: githubRef{Ref: "feature-" + parts[1], SHA: "sha" + parts[1]},
+			Base: githubRef{Ref: "main", SHA: "base"},
+		})
+	case len(parts) == 3 && parts[0] == "pulls" && parts[2] == "reviews":
+		json.NewEncoder(w).Encode(orEmpty(f.reviews))
+	case len(parts) == 3 && parts[0] == "pulls" && parts[2] == "comments":
+		json.NewEncoder(w).Encode(orEmpty(f.reviewComments))
+	case len(parts) == 3 && parts[0] == "commits" && parts[2] == "check-runs":
+		json.NewEncoder(w).Encode(githubCheckRuns{CheckRuns: orEmpty(f.checkRuns)})
+	default:
+		return false
+	}
+	return true
+}
+
+// orEmpty returns s, or an empty slice if it is nil, so that it is
+// encoded as an empty JSON array.
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestDiscoverPullRequestDetails(t *testing.T) {
+	issues := []githubIssue{
+		{Number: 1, Title: "Bug", HTMLURL: "https://github.com/o/r/issues/1"},
+		{Number: 2, Title: "Fix bug", HTMLURL: "https://github.com/o/r/pull/2", PullRequest: &struct{}{}},
+	}
+	pr := &fakePullRequest{
+		diff: "diff --git a/main.go b/main.go\n",
+		reviews: []githubReview{
+			{User: githubUser{Login: "alice"}, State: "CHANGES_REQUESTED", Body: "Please add a test"},
+			{User: githubUser{Login: "bob"}, State: "COMMENTED"},
+		},
+		reviewComments: []githubReviewComment{
+			{User: githubUser{Login: "alice"}, Path: "main.go", Line: intPtr(12), Body: "Handle the error"},
+			{User: githubUser{Login: "bob"}, Path: "old.go", OriginalLine: intPtr(3), Body: "Outdated"},
+		},
+		checkRuns: []githubCheckRun{
+			{Name: "lint", Conclusion: "success"},
+			{Name: "test", Conclusion: "failure", HTMLURL: "https://github.com/o/r/runs/1", Output: struct {
+				Title string `json:"title"`
+			}{Title: "2 tests failed"}},
+			{Name: "e2e", Conclusion: "timed_out"},
+			{Name: "build"},
+		},
+	}
+	var checkRunPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/owner/repo/issues":
+			json.NewEncoder(w).Encode(issues)
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/") && strings.HasSuffix(r.URL.Path, "/comments"):
+			json.NewEncoder(w).Encode([]githubComment{})
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			checkRunPaths = append(checkRunPaths, r.URL.Path)
+			pr.serve(w, r)
+		case pr.serve(w, r):
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", Types: []string{"issues", "pulls"}, BaseURL: server.URL}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if issue := items[0]; issue.Branch != "" || issue.Diff != "" || issue.FailedChecks != "" {
+		t.Errorf("expected no pull request details on an issue, got %+v", issue)
+	}
+
+	got := items[1]
+	if got.Branch != "feature-2" || got.BaseBranch != "main" {
+		t.Errorf("expected branch feature-2 into main, got %q into %q", got.Branch, got.BaseBranch)
+	}
+	if got.Diff != pr.diff {
+		t.Errorf("expected the diff, got %q", got.Diff)
+	}
+	if want := "@alice (CHANGES_REQUESTED):\nPlease add a test"; got.Reviews != want {
+		t.Errorf("Reviews = %q, want %q", got.Reviews, want)
+	}
+	if want := "@alice on main.go:12:\nHandle the error\n---\n@bob on old.go:3:\nOutdated"; got.ReviewComments != want {
+		t.Errorf("ReviewComments = %q, want %q", got.ReviewComments, want)
+	}
+	if want := "test: failure - 2 tests failed (https://github.com/o/r/runs/1)\ne2e: timed_out"; got.FailedChecks != want {
+		t.Errorf("FailedChecks = %q, want %q", got.FailedChecks, want)
+	}
+	if len(checkRunPaths) != 1 || checkRunPaths[0] != "/repos/owner/repo/commits/sha2/check-runs" {
+		t.Errorf("expected the check runs of the head commit, got %v", checkRunPaths)
+	}
+}
+
+func TestDiscoverPullRequestDiffTruncated(t *testing.T) {
+	pr := &fakePullRequest{diff: strings.Repeat("+", maxDiffBytes+100)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !pr.serve(w, r) {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}
+	items := []WorkItem{{Number: 2, Kind: "PR"}}
+	if err := s.addPullRequestDetails(context.Background(), items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(items[0].Diff, "\n[diff truncated]") || len(items[0].Diff) != maxDiffBytes+len("\n[diff truncated]") {
+		t.Errorf("expected the diff to be truncated, got %d bytes", len(items[0].Diff))
+	}
+}
+
+func TestPullRequestDetailsCached(t *testing.T) {
+	pr := &fakePullRequest{diff: "diff --git a/main.go b/main.go\n"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !pr.serve(w, r) {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL, Cache: NewResponseCache()}
+	for i := range 2 {
+		items := []WorkItem{{Number: 2, Kind: "PR"}}
+		if err := s.addPullRequestDetails(context.Background(), items); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// The pull request and its diff are cached apart, so the cached
+		// JSON is never returned for the diff nor the other way around
+		if items[0].Branch != "feature-2" || items[0].Diff != pr.diff {
+			t.Errorf("discovery %d: unexpected details %+v", i, items[0])
+		}
+	}
+}
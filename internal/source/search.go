@@ -0,0 +1,103 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// searchQuery returns the search query for the issues updated since the
+// given time, followed by the Query. Like REST listings, without a since
+// time the issues are filtered by state, labels, assignee, author and
+// milestone; otherwise all issues updated since are searched.
+func (s *GitHubSource) searchQuery(since time.Time) string {
+	terms := []string{fmt.Sprintf("repo:%s/%s", s.Owner, s.Repo)}
+
+	types := s.resolvedTypes()
+	_, issues := types["issues"]
+	_, pulls := types["pulls"]
+	switch {
+	case issues && !pulls:
+		terms = append(terms, "is:issue")
+	case pulls && !issues:
+		terms = append(terms, "is:pr")
+	}
+
+	if since.IsZero() {
+		if state := s.state(); state != "all" {
+			terms = append(terms, "is:"+state)
+		}
+		for _, l := range s.Labels {
+			terms = append(terms, fmt.Sprintf("label:%q", l))
+		}
+		// Search has no qualifier for items assigned to anyone or with
+		// any milestone, those are filtered client-side
+		switch assignee := s.assignee(); assignee {
+		case "", "*":
+		case "none":
+			terms = append(terms, "no:assignee")
+		default:
+			terms = append(terms, "assignee:"+assignee)
+		}
+		if s.Author != "" {
+			terms = append(terms, "author:"+s.Author)
+		}
+		switch s.Milestone {
+		case "", "*":
+		case "none":
+			terms = append(terms, "no:milestone")
+		default:
+			terms = append(terms, fmt.Sprintf("milestone:%q", s.Milestone))
+		}
+	} else {
+		terms = append(terms, "updated:>="+since.UTC().Format(time.RFC3339))
+	}
+
+	if s.Query != "" {
+		terms = append(terms, s.Query)
+	}
+	return strings.Join(terms, " ")
+}
+
+type githubSearchResult struct {
+	Items []githubIssue `json:"items"`
+}
+
+// searchIssuesPage returns a page of the issues updated since the given
+// time from the REST search API, and the URL of the next page, if any. An
+// empty pageURL requests the first page.
+func (s *GitHubSource) searchIssuesPage(ctx context.Context, since time.Time, pageURL string) ([]githubIssue, string, error) {
+	if pageURL == "" {
+		params := url.Values{}
+		params.Set("q", s.searchQuery(since))
+		params.Set("sort", "updated")
+		params.Set("order", "asc")
+		params.Set("per_page", "100")
+		pageURL = s.baseURL() + "/search/issues?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, "search_issues")
+	if err != nil {
+		return nil, "", fmt.Errorf("searching issues: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", githubAPIError(resp)
+	}
+
+	var result githubSearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("decoding issues: %w", err)
+	}
+	return result.Items, parseNextLink(resp.Header.Get("Link")), nil
+}
@@ -0,0 +1,130 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiscoverSearchQuery(t *testing.T) {
+	alice := githubUser{Login: "alice"}
+	var queries []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/issues":
+			if r.URL.Query().Get("page") == "2" {
+				json.NewEncoder(w).Encode(githubSearchResult{Items: []githubIssue{{Number: 2, Title: "Two", User: alice, AuthorAssociation: "MEMBER"}}})
+				return
+			}
+			queries = append(queries, r.URL.Query().Get("q"))
+			if r.URL.Query().Get("sort") != "updated" || r.URL.Query().Get("order") != "asc" {
+				t.Errorf("expected results sorted by update, got %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/search/issues?page=2>; rel="next"`, server.URL))
+			json.NewEncoder(w).Encode(githubSearchResult{Items: []githubIssue{
+				{Number: 1, Title: "One", User: alice, AuthorAssociation: "MEMBER"},
+				{Number: 3, Title: "Three", User: alice, AuthorAssociation: "NONE"},
+			}})
+		case "/repos/owner/repo/issues/1/comments":
+			json.NewEncoder(w).Encode([]githubComment{{Body: "A comment"}})
+		default:
+			json.NewEncoder(w).Encode([]githubComment{})
+		}
+	}))
+	defer server.Close()
+
+	s := &GitHubSource{
+		Owner:              "owner",
+		Repo:               "repo",
+		Labels:             []string{"bug"},
+		Author:             "alice",
+		AuthorAssociations: []string{"MEMBER", "NONE"},
+		Query:              "-label:wontfix in:title crash",
+		BaseURL:            server.URL,
+		Index:              NewItemIndex(),
+	}
+
+	for range 2 {
+		items, err := s.Discover(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := itemNumbers(items); !slices.Equal(got, []int{3, 2, 1}) {
+			t.Fatalf("discovered %v, want [3 2 1]", got)
+		}
+		if items[2].Comments != "A comment" {
+			t.Errorf("unexpected comments %q", items[2].Comments)
+		}
+	}
+
+	// Every discovery searches anew
+	want := `repo:owner/repo is:issue is:open label:"bug" author:alice -label:wontfix in:title crash`
+	if len(queries) != 2 || queries[0] != want || queries[1] != want {
+		t.Errorf("queries = %q, want %q twice", queries, want)
+	}
+
+	s.AuthorAssociations = []string{"MEMBER"}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := itemNumbers(items); !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("discovered %v, want only the issues by members", got)
+	}
+}
+
+func TestSearchQueryFilters(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name   string
+		source GitHubSource
+		since  time.Time
+		want   string
+	}{
+		{
+			name:   "defaults",
+			source: GitHubSource{},
+			want:   "repo:o/r is:issue is:open",
+		},
+		{
+			name:   "pull requests in any state",
+			source: GitHubSource{Types: []string{"pulls"}, State: "all"},
+			want:   "repo:o/r is:pr",
+		},
+		{
+			name:   "unassigned without milestone",
+			source: GitHubSource{Types: []string{"issues", "pulls"}, Assignee: "none", Milestone: "none"},
+			want:   "repo:o/r is:open no:assignee no:milestone",
+		},
+		{
+			name:   "any assignee and milestone are filtered client-side",
+			source: GitHubSource{Assignee: "*", Milestone: "*"},
+			want:   "repo:o/r is:issue is:open",
+		},
+		{
+			name:   "assignee and milestone title",
+			source: GitHubSource{Assignee: "bob", Milestone: "Release 1"},
+			want:   `repo:o/r is:issue is:open assignee:bob milestone:"Release 1"`,
+		},
+		{
+			name:   "updates since",
+			source: GitHubSource{Labels: []string{"bug"}, Author: "alice"},
+			since:  since,
+			want:   "repo:o/r is:issue updated:>=2026-01-02T03:04:05Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.source.Owner, tt.source.Repo = "o", "r"
+			if got := tt.source.searchQuery(tt.since); got != tt.want {
+				t.Errorf("searchQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
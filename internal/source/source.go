@@ -1,6 +1,12 @@
 package source
 
-import "context"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
 
 // WorkItem represents a discovered work item from an external source.
 type WorkItem struct {
@@ -11,10 +17,88 @@ type WorkItem struct {
 	URL      string
 	Labels   []string
 	Comments string
-	Kind     string // "Issue" or "PR"
+	Kind     string // "Issue", "PR", "WorkflowRun", "JiraIssue" or "LinearIssue"
+
+	// Author is the login of the user who opened the item.
+	Author string
+	// Assignees are the logins of the users the item is assigned to.
+	Assignees []string
+	// Milestone is the title of the item's milestone, if any.
+	Milestone string
+	// LinkedPRs are the numbers of the pull requests that close the issue.
+	// Only the GitHub GraphQL API reports them.
+	LinkedPRs []int
+	// CommentList holds the comments joined in Comments, oldest first,
+	// with their authors and creation times.
+	CommentList []IssueComment
+
+	// Branch and BaseBranch are the head and base branches of a pull
+	// request.
+	Branch     string
+	BaseBranch string
+	// Diff is the diff of a pull request, cut at 64KiB.
+	Diff string
+	// Reviews are the summaries of the reviews of a pull request, and
+	// ReviewComments the comments made on lines of its diff.
+	Reviews        string
+	ReviewComments string
+	// FailedChecks lists the check runs that failed on the head commit of
+	// a pull request, or the jobs of a workflow run that failed, one per
+	// line.
+	FailedChecks string
+
+	// Workflow is the name of the workflow of a workflow run, and Commit
+	// the commit it ran on.
+	Workflow string
+	Commit   string
+	// Logs holds the end of the logs of the failed jobs of a workflow run.
+	Logs string
+
+	// Components are the components of a Jira issue.
+	Components []string
+
+	// Args are the arguments of the command the item was discovered for,
+	// if it was discovered for a command in a comment.
+	Args string
+	// Requester is the login of the user who made the command.
+	Requester string
 }
 
 // Source discovers work items from an external system.
 type Source interface {
 	Discover(ctx context.Context) ([]WorkItem, error)
 }
+
+// Acknowledger is implemented by sources that need to be told that a Task
+// was created for an item, so that they do not discover it again.
+type Acknowledger interface {
+	Acknowledge(ctx context.Context, item WorkItem) error
+}
+
+// maxNameSuffixLength limits the length of NameSuffix, so that the names
+// of Tasks stay short enough for the label values they are used in.
+const maxNameSuffixLength = 40
+
+var invalidNameCharsRe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// NameSuffix returns the ID of a work item as it can end the name of the
+// Task spawned for it. IDs that are valid names, like "42", are returned as
+// they are. Other IDs are lowercased, stripped of the characters names
+// cannot have, shortened and get a hash of the ID, so that IDs that differ
+// only in case, like "aB3" and "Ab3", stay unique: the Jira key "PROJ-123"
+// becomes "proj-123-" followed by the hash.
+func NameSuffix(id string) string {
+	cleaned := strings.Trim(invalidNameCharsRe.ReplaceAllString(strings.ToLower(id), "-"), "-")
+	if cleaned == id && len(id) <= maxNameSuffixLength {
+		return id
+	}
+	sum := sha256.Sum256([]byte(id))
+	hash := hex.EncodeToString(sum[:4])
+	if len(cleaned) > maxNameSuffixLength-len(hash)-1 {
+		cleaned = strings.TrimRight(cleaned[:maxNameSuffixLength-len(hash)-1], "-")
+	}
+	if cleaned == "" {
+		return hash
+	}
+	return cleaned + "-" + hash
+}
@@ -0,0 +1,37 @@
+package source
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNameSuffix(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"42", "42"},
+		{"PROJ-123", "proj-123"},
+		{"ops/incident 7", "ops-incident-7-"},
+		{"-leading", "leading-"},
+		{"日本", ""},
+		{strings.Repeat("a", 60), strings.Repeat("a", 31) + "-"},
+	}
+	for _, tt := range tests {
+		got := NameSuffix(tt.id)
+		if !strings.HasPrefix(got, tt.want) || len(got) > maxNameSuffixLength || invalidNameCharsRe.MatchString(got) ||
+			strings.HasPrefix(got, "-") || strings.HasSuffix(got, "-") {
+			t.Errorf("NameSuffix(%q) = %q, want a valid name suffix starting with %q", tt.id, got, tt.want)
+		}
+	}
+
+	if NameSuffix("a/b") == NameSuffix("a-b") {
+		t.Errorf("expected IDs that differ in replaced characters to differ")
+	}
+	if NameSuffix("aB3") == NameSuffix("Ab3") || NameSuffix("aB3") == NameSuffix("ab3") {
+		t.Errorf("expected IDs that differ in case to differ")
+	}
+	if got := NameSuffix("proj-123"); got != "proj-123" {
+		t.Errorf("expected a valid name suffix to be kept, got %q", got)
+	}
+}
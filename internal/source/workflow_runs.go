@@ -0,0 +1,326 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxJobLogBytes limits the log of a failed job to its end, where the
+	// errors usually are.
+	maxJobLogBytes = 16 * 1024
+
+	// maxJobLogs limits the number of failed jobs whose logs are fetched
+	// for a run.
+	maxJobLogs = 4
+)
+
+// logTimestampRe matches the timestamps GitHub Actions prefixes log lines
+// with.
+var logTimestampRe = regexp.MustCompile(`(?m)^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(\.\d+)?Z `)
+
+// GitHubWorkflowRunsSource discovers failing GitHub Actions workflows. For
+// every workflow and branch, the latest run that succeeded or failed is
+// looked at; if it failed, it is a WorkItem identified by the ID and the
+// attempt of the run, like "123-2", with the failed jobs and the end of their
+// logs. A re-run that fails again is a new WorkItem. Runs that were
+// cancelled or skipped are passed over.
+type GitHubWorkflowRunsSource struct {
+	*GitHubSource
+
+	// Workflows are the file names or IDs of the workflows to watch, like
+	// "ci.yaml". All workflows are watched if none are set.
+	Workflows []string
+	// Branches are the branches to watch. Defaults to the default branch of
+	// the repository.
+	Branches []string
+
+	// Runs, if set, holds the items of the failed runs discovered before.
+	// A run does not change once it completed, so its jobs and logs are
+	// only fetched once. Discover drops the runs it no longer discovers.
+	Runs map[string]WorkItem
+}
+
+type githubWorkflowRuns struct {
+	WorkflowRuns []githubWorkflowRun `json:"workflow_runs"`
+}
+
+type githubWorkflowRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	WorkflowID int64  `json:"workflow_id"`
+	HeadBranch string `json:"head_branch"`
+	HeadSHA    string `json:"head_sha"`
+	Conclusion string `json:"conclusion"`
+	RunAttempt int    `json:"run_attempt"`
+	HTMLURL    string `json:"html_url"`
+	HeadCommit struct {
+		Message string `json:"message"`
+	} `json:"head_commit"`
+}
+
+type githubJobs struct {
+	Jobs []githubJob `json:"jobs"`
+}
+
+type githubJob struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+	Steps      []struct {
+		Name       string `json:"name"`
+		Conclusion string `json:"conclusion"`
+	} `json:"steps"`
+}
+
+// Discover returns the latest runs of the workflows on the branches that
+// failed.
+func (s *GitHubWorkflowRunsSource) Discover(ctx context.Context) ([]WorkItem, error) {
+	branches := s.Branches
+	if len(branches) == 0 {
+		branch, err := s.defaultBranch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		branches = []string{branch}
+	}
+	workflows := s.Workflows
+	if len(workflows) == 0 {
+		// All workflows
+		workflows = []string{""}
+	}
+
+	var failed []githubWorkflowRun
+	for _, workflow := range workflows {
+		for _, branch := range branches {
+			runs, err := s.listCompletedRuns(ctx, workflow, branch)
+			if err != nil {
+				return nil, err
+			}
+			failed = append(failed, latestFailedRuns(runs)...)
+		}
+	}
+
+	var items []WorkItem
+	known := map[string]bool{}
+	for _, run := range failed {
+		// The same run of another attempt may have failed differently
+		key := runItemID(&run)
+		known[key] = true
+		if item, ok := s.Runs[key]; ok {
+			items = append(items, item)
+			continue
+		}
+
+		item, err := s.runWorkItem(ctx, &run)
+		if err != nil {
+			return nil, fmt.Errorf("fetching workflow run %d: %w", run.ID, err)
+		}
+		if s.Runs != nil {
+			s.Runs[key] = item
+		}
+		items = append(items, item)
+	}
+
+	for key := range s.Runs {
+		if !known[key] {
+			delete(s.Runs, key)
+		}
+	}
+	return items, nil
+}
+
+// runItemID returns the ID of the WorkItem of the run's attempt.
+func runItemID(run *githubWorkflowRun) string {
+	return fmt.Sprintf("%d-%d", run.ID, run.RunAttempt)
+}
+
+// latestFailedRuns returns the latest of the runs of every workflow that
+// succeeded or failed, if it failed. The runs are ordered newest first.
+func latestFailedRuns(runs []githubWorkflowRun) []githubWorkflowRun {
+	var failed []githubWorkflowRun
+	seen := map[int64]bool{}
+	for _, run := range runs {
+		if seen[run.WorkflowID] {
+			continue
+		}
+		switch {
+		case run.Conclusion == "success":
+			seen[run.WorkflowID] = true
+		case slices.Contains(failedCheckConclusions, run.Conclusion):
+			seen[run.WorkflowID] = true
+			failed = append(failed, run)
+		}
+	}
+	return failed
+}
+
+// listCompletedRuns returns the latest page of the completed runs of the
+// workflow, or of all workflows if it is empty, on the branch, newest
+// first.
+func (s *GitHubWorkflowRunsSource) listCompletedRuns(ctx context.Context, workflow, branch string) ([]githubWorkflowRun, error) {
+	params := url.Values{}
+	params.Set("branch", branch)
+	params.Set("status", "completed")
+	params.Set("per_page", "100")
+	path := "actions/runs?" + params.Encode()
+	if workflow != "" {
+		path = fmt.Sprintf("actions/workflows/%s/runs?%s", url.PathEscape(workflow), params.Encode())
+	}
+
+	var runs githubWorkflowRuns
+	if err := s.get(ctx, path, "list_workflow_runs", &runs); err != nil {
+		if workflow == "" {
+			return nil, fmt.Errorf("listing workflow runs on %s: %w", branch, err)
+		}
+		return nil, fmt.Errorf("listing runs of workflow %s on %s: %w", workflow, branch, err)
+	}
+	return runs.WorkflowRuns, nil
+}
+
+// runWorkItem returns the failed run as a WorkItem, with its failed jobs
+// and the end of their logs.
+func (s *GitHubWorkflowRunsSource) runWorkItem(ctx context.Context, run *githubWorkflowRun) (WorkItem, error) {
+	item := WorkItem{
+		ID:       runItemID(run),
+		Title:    fmt.Sprintf("%s failed on %s", run.Name, run.HeadBranch),
+		Body:     run.HeadCommit.Message,
+		URL:      run.HTMLURL,
+		Kind:     "WorkflowRun",
+		Branch:   run.HeadBranch,
+		Workflow: run.Name,
+		Commit:   run.HeadSHA,
+	}
+
+	var jobs githubJobs
+	if err := s.get(ctx, fmt.Sprintf("actions/runs/%d/jobs?filter=latest&per_page=100", run.ID), "list_workflow_jobs", &jobs); err != nil {
+		return WorkItem{}, err
+	}
+
+	var checks, logs []string
+	for _, job := range jobs.Jobs {
+		if !slices.Contains(failedCheckConclusions, job.Conclusion) {
+			continue
+		}
+		line := fmt.Sprintf("%s: %s", job.Name, job.Conclusion)
+		for _, step := range job.Steps {
+			if slices.Contains(failedCheckConclusions, step.Conclusion) {
+				line += fmt.Sprintf(" at step %q", step.Name)
+				break
+			}
+		}
+		if job.HTMLURL != "" {
+			line += " (" + job.HTMLURL + ")"
+		}
+		checks = append(checks, line)
+
+		if len(logs) == maxJobLogs {
+			continue
+		}
+		log, err := s.fetchJobLog(ctx, job.ID)
+		if err != nil {
+			return WorkItem{}, fmt.Errorf("fetching the log of job %s: %w", job.Name, err)
+		}
+		logs = append(logs, fmt.Sprintf("=== %s ===\n%s", job.Name, log))
+	}
+	item.FailedChecks = strings.Join(checks, "\n")
+	item.Logs = strings.Join(logs, "\n")
+	return item, nil
+}
+
+// fetchJobLog returns the end of the log of the job, without timestamps.
+func (s *GitHubWorkflowRunsSource) fetchJobLog(ctx context.Context, jobID int64) (string, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/actions/jobs/%d/logs", s.baseURL(), s.Owner, s.Repo, jobID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	// The API redirects to where the log can be downloaded from
+	resp, err := s.do(req, "get_job_logs")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", githubAPIError(resp)
+	}
+
+	log, truncated, err := readTail(resp.Body, maxJobLogBytes)
+	if err != nil {
+		return "", fmt.Errorf("reading log: %w", err)
+	}
+	log = logTimestampRe.ReplaceAllString(log, "")
+	if truncated {
+		// Start at a whole line
+		if i := strings.IndexByte(log, '\n'); i >= 0 {
+			log = log[i+1:]
+		}
+		log = "[log truncated]\n" + log
+	}
+	return log, nil
+}
+
+// readTail returns the last n bytes read from r, and whether there were
+// more.
+func readTail(r io.Reader, n int) (string, bool, error) {
+	buf := make([]byte, 0, 2*n)
+	truncated := false
+	chunk := make([]byte, 32*1024)
+	for {
+		k, err := r.Read(chunk)
+		buf = append(buf, chunk[:k]...)
+		if len(buf) > 2*n {
+			copy(buf, buf[len(buf)-n:])
+			buf = buf[:n]
+			truncated = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false, err
+		}
+	}
+	if len(buf) > n {
+		buf = buf[len(buf)-n:]
+		truncated = true
+	}
+	return string(buf), truncated, nil
+}
+
+// defaultBranch returns the default branch of the repository.
+func (s *GitHubWorkflowRunsSource) defaultBranch(ctx context.Context) (string, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s", s.baseURL(), s.Owner, s.Repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.do(req, "get_repo")
+	if err != nil {
+		return "", fmt.Errorf("fetching repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", githubAPIError(resp)
+	}
+
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return "", fmt.Errorf("decoding repository: %w", err)
+	}
+	return repo.DefaultBranch, nil
+}
@@ -0,0 +1,222 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeActions serves the workflow runs of a repository, their jobs and the
+// logs of the jobs like the GitHub API, which redirects to the logs.
+type fakeActions struct {
+	mu   sync.Mutex
+	runs []githubWorkflowRun
+	jobs map[int64][]githubJob
+	logs map[int64]string
+
+	listed  []string
+	jobRuns []int64
+	logJobs []int64
+}
+
+func (f *fakeActions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo")
+	var id int64
+	switch {
+	case path == "":
+		json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+	case path == "/actions/runs" || strings.HasPrefix(path, "/actions/workflows/"):
+		f.listed = append(f.listed, path+"?"+r.URL.RawQuery)
+		workflow := strings.TrimSuffix(strings.TrimPrefix(path, "/actions/workflows/"), "/runs")
+		var runs []githubWorkflowRun
+		for _, run := range f.runs {
+			if run.HeadBranch != r.URL.Query().Get("branch") || path != "/actions/runs" && fmt.Sprint(run.WorkflowID) != workflow {
+				continue
+			}
+			runs = append(runs, run)
+		}
+		json.NewEncoder(w).Encode(githubWorkflowRuns{WorkflowRuns: runs})
+	case scan(path, "/actions/runs/%d/jobs", &id):
+		f.jobRuns = append(f.jobRuns, id)
+		json.NewEncoder(w).Encode(githubJobs{Jobs: f.jobs[id]})
+	case scan(path, "/actions/jobs/%d/logs", &id):
+		f.logJobs = append(f.logJobs, id)
+		http.Redirect(w, r, fmt.Sprintf("/blobs/%d", id), http.StatusFound)
+	case scan(r.URL.Path, "/blobs/%d", &id):
+		fmt.Fprint(w, f.logs[id])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// scan reports whether the path is the format with an ID, and sets it.
+func scan(path, format string, id *int64) bool {
+	_, err := fmt.Sscanf(path, format, id)
+	return err == nil && fmt.Sprintf(format, *id) == path
+}
+
+func failedJob(id int64, name, step string) githubJob {
+	job := githubJob{ID: id, Name: name, Conclusion: "failure", HTMLURL: fmt.Sprintf("https://github.com/owner/repo/actions/runs/1/job/%d", id)}
+	job.Steps = append(job.Steps,
+		struct {
+			Name       string `json:"name"`
+			Conclusion string `json:"conclusion"`
+		}{Name: "Checkout", Conclusion: "success"},
+		struct {
+			Name       string `json:"name"`
+			Conclusion string `json:"conclusion"`
+		}{Name: step, Conclusion: "failure"},
+	)
+	return job
+}
+
+func TestDiscoverWorkflowRuns(t *testing.T) {
+	run := func(id, workflow int64, name, branch, conclusion string) githubWorkflowRun {
+		r := githubWorkflowRun{ID: id, WorkflowID: workflow, Name: name, HeadBranch: branch, HeadSHA: fmt.Sprintf("sha%d", id), Conclusion: conclusion, RunAttempt: 1, HTMLURL: fmt.Sprintf("https://github.com/owner/repo/actions/runs/%d", id)}
+		r.HeadCommit.Message = "Commit " + fmt.Sprint(id)
+		return r
+	}
+	actions := &fakeActions{
+		// Newest first
+		runs: []githubWorkflowRun{
+			run(9, 1, "CI", "main", "cancelled"),
+			run(8, 1, "CI", "main", "failure"),
+			run(7, 1, "CI", "main", "success"),
+			run(6, 2, "Lint", "main", "success"),
+			run(5, 2, "Lint", "main", "failure"),
+			run(4, 1, "CI", "feature", "failure"),
+		},
+		jobs: map[int64][]githubJob{
+			8: {
+				{ID: 80, Name: "build", Conclusion: "success"},
+				failedJob(81, "test", "Run tests"),
+			},
+		},
+		logs: map[int64]string{
+			81: "2024-05-01T12:00:00.1234567Z Running tests\n2024-05-01T12:00:01.0000000Z --- FAIL: TestLogin\n",
+		},
+	}
+	server := httptest.NewServer(actions)
+	defer server.Close()
+
+	s := &GitHubWorkflowRunsSource{
+		GitHubSource: &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL},
+		Runs:         map[string]WorkItem{},
+	}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the failing CI on the default branch; Lint passes again
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %+v", items)
+	}
+	got := items[0]
+	if got.ID != "8-1" || got.Number != 0 || got.Kind != "WorkflowRun" || got.Title != "CI failed on main" ||
+		got.Body != "Commit 8" || got.URL != "https://github.com/owner/repo/actions/runs/8" ||
+		got.Branch != "main" || got.Workflow != "CI" || got.Commit != "sha8" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if want := `test: failure at step "Run tests" (https://github.com/owner/repo/actions/runs/1/job/81)`; got.FailedChecks != want {
+		t.Errorf("FailedChecks = %q, want %q", got.FailedChecks, want)
+	}
+	if want := "=== test ===\nRunning tests\n--- FAIL: TestLogin\n"; got.Logs != want {
+		t.Errorf("Logs = %q, want %q", got.Logs, want)
+	}
+	if len(actions.listed) != 1 || !strings.Contains(actions.listed[0], "status=completed") {
+		t.Errorf("expected the completed runs to be listed once, got %v", actions.listed)
+	}
+
+	// The jobs and logs of a run are fetched once
+	if _, err := s.Discover(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actions.jobRuns) != 1 || len(actions.logJobs) != 1 {
+		t.Errorf("expected the jobs and logs to be fetched once, got jobs of %v and logs of %v", actions.jobRuns, actions.logJobs)
+	}
+
+	// Re-run and failed again
+	actions.runs[1].RunAttempt = 2
+	items, err = s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "8-2" || len(s.Runs) != 1 {
+		t.Errorf("expected a new item for the failed attempt, got %+v", items)
+	}
+
+	// Fixed
+	actions.runs = append([]githubWorkflowRun{run(10, 1, "CI", "main", "success")}, actions.runs...)
+	items, err = s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 0 || len(s.Runs) != 0 {
+		t.Errorf("expected no items once fixed, got %+v", items)
+	}
+}
+
+func TestDiscoverWorkflowRunsOfWorkflowsAndBranches(t *testing.T) {
+	actions := &fakeActions{
+		runs: []githubWorkflowRun{
+			{ID: 3, WorkflowID: 1, Name: "CI", HeadBranch: "release", Conclusion: "timed_out", RunAttempt: 1},
+			{ID: 2, WorkflowID: 2, Name: "Lint", HeadBranch: "main", Conclusion: "failure", RunAttempt: 1},
+			{ID: 1, WorkflowID: 1, Name: "CI", HeadBranch: "main", Conclusion: "failure", RunAttempt: 2},
+		},
+	}
+	server := httptest.NewServer(actions)
+	defer server.Close()
+
+	s := &GitHubWorkflowRunsSource{
+		GitHubSource: &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL},
+		Workflows:    []string{"1"},
+		Branches:     []string{"main", "release"},
+	}
+	items, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if strings.Join(ids, ",") != "1-2,3-1" {
+		t.Errorf("expected the runs of CI on main and release, got %v", ids)
+	}
+	if len(actions.listed) != 2 || !strings.HasPrefix(actions.listed[0], "/actions/workflows/1/runs?") {
+		t.Errorf("expected the runs of the workflow to be listed per branch, got %v", actions.listed)
+	}
+}
+
+func TestFetchJobLogTruncated(t *testing.T) {
+	var log strings.Builder
+	for i := 0; log.Len() < 3*maxJobLogBytes; i++ {
+		fmt.Fprintf(&log, "2024-05-01T12:00:00.0000000Z line %d\n", i)
+	}
+	log.WriteString("2024-05-01T12:00:00.0000000Z Error: the end\n")
+	actions := &fakeActions{logs: map[int64]string{1: log.String()}}
+	server := httptest.NewServer(actions)
+	defer server.Close()
+
+	s := &GitHubWorkflowRunsSource{GitHubSource: &GitHubSource{Owner: "owner", Repo: "repo", BaseURL: server.URL}}
+	got, err := s.fetchJobLog(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "[log truncated]\nline ") || !strings.HasSuffix(got, "Error: the end\n") {
+		t.Errorf("expected the end of the log from a whole line on, got %q...%q", got[:40], got[len(got)-40:])
+	}
+	if len(got) > maxJobLogBytes {
+		t.Errorf("expected at most %d bytes, got %d", maxJobLogBytes, len(got))
+	}
+}
@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// certValidity is how long generated certificates are valid for.
+	certValidity = 10 * 365 * 24 * time.Hour
+
+	// certRenewBefore is how long before it expires a certificate is
+	// replaced when the controller starts.
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+// +kubebuilder:rbac:groups="",namespace=axon-system,resources=secrets,verbs=get;create;update
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;update
+
+// CertProvisioner provisions the serving certificate of the webhook server
+// without depending on cert-manager. The certificate and the self-signed CA
+// that issued it are kept in a Secret so that every replica of the
+// controller serves the same certificate, and the CA is injected into the
+// webhook configurations so that the API server trusts it.
+type CertProvisioner struct {
+	// Client must not be backed by the Manager's cache, which is not
+	// started yet when the certificate is provisioned.
+	Client client.Client
+
+	// Namespace is the namespace of the Secret and the Service.
+	Namespace string
+
+	// SecretName is the name of the Secret holding the certificates.
+	SecretName string
+
+	// CertDir is the directory the webhook server reads tls.crt and
+	// tls.key from.
+	CertDir string
+}
+
+// Provision ensures a valid certificate exists, writes it to CertDir and
+// injects its CA into the webhook configurations.
+func (p *CertProvisioner) Provision(ctx context.Context) error {
+	secret, err := p.ensureSecret(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(p.CertDir, 0o700); err != nil {
+		return fmt.Errorf("creating webhook certificate directory: %w", err)
+	}
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if err := os.WriteFile(filepath.Join(p.CertDir, key), secret.Data[key], 0o600); err != nil {
+			return fmt.Errorf("writing webhook certificate: %w", err)
+		}
+	}
+
+	return p.injectCABundle(ctx, secret.Data[corev1.ServiceAccountRootCAKey])
+}
+
+// ensureSecret returns the Secret holding the certificates, generating
+// new ones if they are missing, about to expire, or issued for another
+// Service.
+func (p *CertProvisioner) ensureSecret(ctx context.Context, now time.Time) (*corev1.Secret, error) {
+	logger := log.FromContext(ctx)
+
+	var secret corev1.Secret
+	err := p.Client.Get(ctx, client.ObjectKey{Namespace: p.Namespace, Name: p.SecretName}, &secret)
+	exists := err == nil
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("getting webhook certificate Secret: %w", err)
+	case p.validCert(secret.Data, now):
+		return &secret, nil
+	}
+
+	data, err := p.generateCerts(now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: p.Namespace, Name: p.SecretName},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		if err := p.Client.Create(ctx, &secret); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica created the Secret first, so use its
+				// certificates
+				return p.ensureSecret(ctx, now)
+			}
+			return nil, fmt.Errorf("creating webhook certificate Secret: %w", err)
+		}
+		logger.Info("Generated webhook certificate", "secret", p.SecretName)
+		return &secret, nil
+	}
+
+	secret.Data = data
+	if err := p.Client.Update(ctx, &secret); err != nil {
+		if apierrors.IsConflict(err) {
+			return p.ensureSecret(ctx, now)
+		}
+		return nil, fmt.Errorf("updating webhook certificate Secret: %w", err)
+	}
+	logger.Info("Renewed webhook certificate", "secret", p.SecretName)
+	return &secret, nil
+}
+
+// dnsNames returns the names the webhook Service is reached at.
+func (p *CertProvisioner) dnsNames() []string {
+	return []string{
+		ServiceName,
+		ServiceName + "." + p.Namespace,
+		ServiceName + "." + p.Namespace + ".svc",
+		ServiceName + "." + p.Namespace + ".svc.cluster.local",
+	}
+}
+
+// validCert reports whether the Secret data holds a certificate for the
+// webhook Service, issued by the CA in the data, that does not expire soon.
+func (p *CertProvisioner) validCert(data map[string][]byte, now time.Time) bool {
+	pair, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data[corev1.ServiceAccountRootCAKey]) {
+		return false
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     p.dnsNames()[2],
+		Roots:       roots,
+		CurrentTime: now.Add(certRenewBefore),
+	})
+	return err == nil
+}
+
+// generateCerts generates a self-signed CA and a serving certificate for
+// the webhook Service issued by it.
+func (p *CertProvisioner) generateCerts(now time.Time) (map[string][]byte, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating CA key: %w", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          serialNumber(now),
+		Subject:               pkix.Name{CommonName: "axon-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating CA certificate: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, fmt.Errorf("parsing CA certificate: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating serving key: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(now.Add(time.Nanosecond)),
+		Subject:      pkix.Name{CommonName: p.dnsNames()[2]},
+		DNSNames:     p.dnsNames(),
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating serving certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("encoding serving key: %w", err)
+	}
+
+	return map[string][]byte{
+		corev1.ServiceAccountRootCAKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		corev1.TLSCertKey:              pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		corev1.TLSPrivateKeyKey:        pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// serialNumber returns a certificate serial number derived from t.
+func serialNumber(t time.Time) *big.Int {
+	return big.NewInt(t.UnixNano())
+}
+
+// injectCABundle sets the CA bundle of every webhook in the webhook
+// configurations.
+func (p *CertProvisioner) injectCABundle(ctx context.Context, caBundle []byte) error {
+	var mutating admissionregistrationv1.MutatingWebhookConfiguration
+	if err := p.Client.Get(ctx, client.ObjectKey{Name: MutatingWebhookConfigurationName}, &mutating); err != nil {
+		return fmt.Errorf("getting MutatingWebhookConfiguration: %w", err)
+	}
+	changed := false
+	for i := range mutating.Webhooks {
+		if !bytes.Equal(mutating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			mutating.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if changed {
+		if err := p.Client.Update(ctx, &mutating); err != nil {
+			return fmt.Errorf("injecting CA into MutatingWebhookConfiguration: %w", err)
+		}
+	}
+
+	var validating admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := p.Client.Get(ctx, client.ObjectKey{Name: ValidatingWebhookConfigurationName}, &validating); err != nil {
+		return fmt.Errorf("getting ValidatingWebhookConfiguration: %w", err)
+	}
+	changed = false
+	for i := range validating.Webhooks {
+		if !bytes.Equal(validating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			validating.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if changed {
+		if err := p.Client.Update(ctx, &validating); err != nil {
+			return fmt.Errorf("injecting CA into ValidatingWebhookConfiguration: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateCerts(t *testing.T) {
+	p := &CertProvisioner{Namespace: "axon-system"}
+	now := time.Now()
+
+	data, err := p.generateCerts(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !p.validCert(data, now) {
+		t.Error("expected the generated certificate to be valid")
+	}
+	if p.validCert(data, now.Add(certValidity-certRenewBefore/2)) {
+		t.Error("expected the certificate to be renewed shortly before it expires")
+	}
+
+	other := &CertProvisioner{Namespace: "other"}
+	if other.validCert(data, now) {
+		t.Error("expected the certificate to be invalid for a Service in another namespace")
+	}
+}
+
+func TestValidCertMissingData(t *testing.T) {
+	p := &CertProvisioner{Namespace: "axon-system"}
+	data, err := p.generateCerts(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key := range data {
+		t.Run(key, func(t *testing.T) {
+			partial := map[string][]byte{}
+			for k, v := range data {
+				if k != key {
+					partial[k] = v
+				}
+			}
+			if p.validCert(partial, time.Now()) {
+				t.Errorf("expected the certificate to be invalid without %s", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// MutatingWebhookConfigurationName is the name of the
+	// MutatingWebhookConfiguration in install.yaml.
+	MutatingWebhookConfigurationName = "axon-mutating-webhook-configuration"
+
+	// ValidatingWebhookConfigurationName is the name of the
+	// ValidatingWebhookConfiguration in install.yaml.
+	ValidatingWebhookConfigurationName = "axon-validating-webhook-configuration"
+
+	// ServiceName is the name of the Service in front of the webhook server.
+	ServiceName = "axon-webhook-service"
+)
+
+// SetupWithManager registers the webhooks of all resources with the Manager.
+func SetupWithManager(mgr ctrl.Manager) error {
+	if err := (&TaskWebhook{}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("setting up Task webhook: %w", err)
+	}
+	if err := (&TaskSpawnerWebhook{}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("setting up TaskSpawner webhook: %w", err)
+	}
+	if err := (&WorkspaceWebhook{}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("setting up Workspace webhook: %w", err)
+	}
+	return nil
+}
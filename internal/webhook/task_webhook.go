@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/controller"
+)
+
+// supportedAgentTypes are the agent types the JobBuilder can build Jobs for.
+var supportedAgentTypes = []string{controller.AgentTypeClaudeCode}
+
+// +kubebuilder:webhook:path=/mutate-axon-io-v1alpha1-task,mutating=true,failurePolicy=fail,sideEffects=None,groups=axon.io,resources=tasks,verbs=create,versions=v1alpha1,name=mtask.axon.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-axon-io-v1alpha1-task,mutating=false,failurePolicy=fail,sideEffects=None,groups=axon.io,resources=tasks,verbs=create;update,versions=v1alpha1,name=vtask.axon.io,admissionReviewVersions=v1
+
+// TaskWebhook defaults and validates Tasks.
+type TaskWebhook struct{}
+
+// SetupWithManager registers the webhook with the Manager.
+func (w *TaskWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &axonv1alpha1.Task{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default implements admission.Defaulter.
+func (w *TaskWebhook) Default(_ context.Context, task *axonv1alpha1.Task) error {
+	if task.Spec.Type == "" {
+		task.Spec.Type = controller.AgentTypeClaudeCode
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.Validator.
+func (w *TaskWebhook) ValidateCreate(_ context.Context, task *axonv1alpha1.Task) (admission.Warnings, error) {
+	return validateTask(task)
+}
+
+// ValidateUpdate implements admission.Validator. Updates that leave the
+// spec unchanged, such as removing a finalizer, are always allowed.
+func (w *TaskWebhook) ValidateUpdate(_ context.Context, old, task *axonv1alpha1.Task) (admission.Warnings, error) {
+	if equality.Semantic.DeepEqual(old.Spec, task.Spec) {
+		return nil, nil
+	}
+	return validateTask(task)
+}
+
+// ValidateDelete implements admission.Validator.
+func (w *TaskWebhook) ValidateDelete(_ context.Context, _ *axonv1alpha1.Task) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateTask(task *axonv1alpha1.Task) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	specPath := field.NewPath("spec")
+	spec := &task.Spec
+
+	errs := validateAgentType(spec.Type, specPath.Child("type"))
+	if strings.TrimSpace(spec.Prompt) == "" {
+		errs = append(errs, field.Required(specPath.Child("prompt"), "must not be empty"))
+	}
+	errs = append(errs, validateCredentials(spec.Credentials, specPath.Child("credentials"))...)
+	if spec.WorkspaceRef != nil && spec.WorkspaceRef.Name == "" {
+		errs = append(errs, field.Required(specPath.Child("workspaceRef", "name"), ""))
+	}
+	if spec.ContinueFrom != nil {
+		if spec.ContinueFrom.Name == "" {
+			errs = append(errs, field.Required(specPath.Child("continueFrom", "name"), ""))
+		} else if spec.ContinueFrom.Name == task.Name {
+			errs = append(errs, field.Invalid(specPath.Child("continueFrom", "name"), spec.ContinueFrom.Name, "a Task cannot continue from itself"))
+		}
+	}
+	if spec.RequireApproval && spec.WorkspaceRef == nil {
+		errs = append(errs, field.Required(specPath.Child("workspaceRef"), "requireApproval requires a Workspace to push the changes to"))
+	}
+	if spec.Approved && spec.ApprovedDiffSHA256 == "" {
+		errs = append(errs, field.Required(specPath.Child("approvedDiffSHA256"), "approving requires the digest of the reviewed diff, as set by 'axon approve'"))
+	}
+	if spec.Approved && !spec.RequireApproval {
+		warnings = append(warnings, "spec.approved has no effect unless spec.requireApproval is set")
+	}
+	if o := spec.PodOverrides; o != nil {
+		for i, e := range o.Env {
+			if controller.ReservedEnvName(e.Name) {
+				errs = append(errs, field.Forbidden(specPath.Child("podOverrides", "env").Index(i).Child("name"),
+					e.Name+" is set by Axon and cannot be overridden"))
+			}
+		}
+	}
+	if a := spec.Artifacts; a != nil {
+		for i, p := range a.Paths {
+			if strings.TrimSpace(p) == "" {
+				errs = append(errs, field.Invalid(specPath.Child("artifacts", "paths").Index(i), p, "must not be empty"))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(axonv1alpha1.GroupVersion.WithKind("Task").GroupKind(), task.Name, errs)
+	}
+	return warnings, nil
+}
+
+// validateAgentType checks that Jobs can be built for the agent type.
+func validateAgentType(agentType string, path *field.Path) field.ErrorList {
+	for _, t := range supportedAgentTypes {
+		if agentType == t {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(path, agentType, supportedAgentTypes)}
+}
+
+// validateCredentials checks that credentials, if set, reference a Secret.
+func validateCredentials(credentials *axonv1alpha1.Credentials, path *field.Path) field.ErrorList {
+	if credentials == nil {
+		return nil
+	}
+	if credentials.SecretRef.Name == "" {
+		return field.ErrorList{field.Required(path.Child("secretRef", "name"), "")}
+	}
+	return nil
+}
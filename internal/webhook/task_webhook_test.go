@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func validTask() *axonv1alpha1.Task {
+	return &axonv1alpha1.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-task"},
+		Spec: axonv1alpha1.TaskSpec{
+			Type:   "claude-code",
+			Prompt: "Fix the bug",
+			Credentials: &axonv1alpha1.Credentials{
+				Type:      axonv1alpha1.CredentialTypeAPIKey,
+				SecretRef: axonv1alpha1.SecretReference{Name: "anthropic-api-key"},
+			},
+		},
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(*axonv1alpha1.Task)
+		wantErr   string
+		wantWarns int
+	}{
+		{
+			name:   "Valid",
+			mutate: func(*axonv1alpha1.Task) {},
+		},
+		{
+			name:    "Unknown type",
+			mutate:  func(task *axonv1alpha1.Task) { task.Spec.Type = "codex" },
+			wantErr: "spec.type",
+		},
+		{
+			name:    "Empty prompt",
+			mutate:  func(task *axonv1alpha1.Task) { task.Spec.Prompt = "  " },
+			wantErr: "spec.prompt",
+		},
+		{
+			name:    "Credentials without secret",
+			mutate:  func(task *axonv1alpha1.Task) { task.Spec.Credentials.SecretRef.Name = "" },
+			wantErr: "spec.credentials.secretRef.name",
+		},
+		{
+			name: "Empty workspace name",
+			mutate: func(task *axonv1alpha1.Task) {
+				task.Spec.WorkspaceRef = &axonv1alpha1.WorkspaceReference{}
+			},
+			wantErr: "spec.workspaceRef.name",
+		},
+		{
+			name: "Continue from itself",
+			mutate: func(task *axonv1alpha1.Task) {
+				task.Spec.ContinueFrom = &axonv1alpha1.TaskReference{Name: task.Name}
+			},
+			wantErr: "spec.continueFrom.name",
+		},
+		{
+			name:    "Require approval without workspace",
+			mutate:  func(task *axonv1alpha1.Task) { task.Spec.RequireApproval = true },
+			wantErr: "spec.workspaceRef",
+		},
+		{
+			name: "Reserved environment variable",
+			mutate: func(task *axonv1alpha1.Task) {
+				task.Spec.PodOverrides = &axonv1alpha1.PodOverrides{Env: []corev1.EnvVar{{Name: "HTTP_PROXY"}, {Name: "GITHUB_TOKEN"}}}
+			},
+			wantErr: "spec.podOverrides.env[1].name",
+		},
+		{
+			name: "Axon environment variable",
+			mutate: func(task *axonv1alpha1.Task) {
+				task.Spec.PodOverrides = &axonv1alpha1.PodOverrides{Env: []corev1.EnvVar{{Name: "AXON_ARTIFACT_BUCKET"}}}
+			},
+			wantErr: "spec.podOverrides.env[0].name",
+		},
+		{
+			name: "Approved without digest",
+			mutate: func(task *axonv1alpha1.Task) {
+				task.Spec.WorkspaceRef = &axonv1alpha1.WorkspaceReference{Name: "ws"}
+				task.Spec.RequireApproval = true
+				task.Spec.Approved = true
+			},
+			wantErr: "spec.approvedDiffSHA256",
+		},
+		{
+			name: "Approved without requiring approval",
+			mutate: func(task *axonv1alpha1.Task) {
+				task.Spec.Approved = true
+				task.Spec.ApprovedDiffSHA256 = strings.Repeat("0", 64)
+			},
+			wantWarns: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := validTask()
+			tt.mutate(task)
+			warnings, err := validateTask(task)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error about %s, got %v", tt.wantErr, err)
+			}
+			if len(warnings) != tt.wantWarns {
+				t.Errorf("got %d warnings, want %d: %v", len(warnings), tt.wantWarns, warnings)
+			}
+		})
+	}
+}
+
+func TestTaskValidateUpdateUnchangedSpec(t *testing.T) {
+	old := validTask()
+	old.Spec.Type = "codex"
+	task := old.DeepCopy()
+	task.Finalizers = nil
+
+	if _, err := (&TaskWebhook{}).ValidateUpdate(context.Background(), old, task); err != nil {
+		t.Errorf("expected an update that leaves the spec unchanged to be allowed, got %v", err)
+	}
+}
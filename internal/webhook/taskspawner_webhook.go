@@ -0,0 +1,286 @@
+package webhook
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/controller"
+	"github.com/axon-core/axon/internal/source"
+)
+
+// +kubebuilder:webhook:path=/mutate-axon-io-v1alpha1-taskspawner,mutating=true,failurePolicy=fail,sideEffects=None,groups=axon.io,resources=taskspawners,verbs=create;update,versions=v1alpha1,name=mtaskspawner.axon.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-axon-io-v1alpha1-taskspawner,mutating=false,failurePolicy=fail,sideEffects=None,groups=axon.io,resources=taskspawners,verbs=create;update,versions=v1alpha1,name=vtaskspawner.axon.io,admissionReviewVersions=v1
+
+// TaskSpawnerWebhook defaults and validates TaskSpawners.
+type TaskSpawnerWebhook struct{}
+
+// SetupWithManager registers the webhook with the Manager.
+func (w *TaskSpawnerWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &axonv1alpha1.TaskSpawner{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default implements admission.Defaulter.
+func (w *TaskSpawnerWebhook) Default(_ context.Context, ts *axonv1alpha1.TaskSpawner) error {
+	if ts.Spec.TaskTemplate.Type == "" {
+		ts.Spec.TaskTemplate.Type = controller.AgentTypeClaudeCode
+	}
+	// A plain number of seconds is accepted for compatibility, but shown
+	// as a duration like every other interval
+	if n, err := strconv.Atoi(ts.Spec.PollInterval); err == nil && n > 0 {
+		ts.Spec.PollInterval = (time.Duration(n) * time.Second).String()
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.Validator.
+func (w *TaskSpawnerWebhook) ValidateCreate(_ context.Context, ts *axonv1alpha1.TaskSpawner) (admission.Warnings, error) {
+	return nil, validateTaskSpawner(ts)
+}
+
+// ValidateUpdate implements admission.Validator. Updates that leave the
+// spec unchanged, such as removing a finalizer, are always allowed.
+func (w *TaskSpawnerWebhook) ValidateUpdate(_ context.Context, old, ts *axonv1alpha1.TaskSpawner) (admission.Warnings, error) {
+	if equality.Semantic.DeepEqual(old.Spec, ts.Spec) {
+		return nil, nil
+	}
+	return nil, validateTaskSpawner(ts)
+}
+
+// ValidateDelete implements admission.Validator.
+func (w *TaskSpawnerWebhook) ValidateDelete(_ context.Context, _ *axonv1alpha1.TaskSpawner) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateTaskSpawner(ts *axonv1alpha1.TaskSpawner) error {
+	specPath := field.NewPath("spec")
+	spec := &ts.Spec
+
+	errs := validateWhen(&spec.When, specPath.Child("when"))
+
+	templatePath := specPath.Child("taskTemplate")
+	errs = append(errs, validateAgentType(spec.TaskTemplate.Type, templatePath.Child("type"))...)
+	errs = append(errs, validateCredentials(spec.TaskTemplate.Credentials, templatePath.Child("credentials"))...)
+	// Rendering an empty work item catches syntax errors as well as
+	// references to fields that work items do not have
+	if _, err := source.RenderPrompt(spec.TaskTemplate.PromptTemplate, source.WorkItem{}); err != nil {
+		errs = append(errs, field.Invalid(templatePath.Child("promptTemplate"), spec.TaskTemplate.PromptTemplate, err.Error()))
+	}
+
+	if _, err := source.ParsePollInterval(spec.PollInterval); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("pollInterval"), spec.PollInterval, err.Error()))
+	}
+
+	linear := spec.When.LinearIssues != nil
+	if name := actionlessSource(&spec.When); name != "" {
+		// Their items are no issues to act on
+		if spec.OnComplete != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("onComplete"), "not supported for "+name+" items"))
+		}
+		if spec.OnFailure != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("onFailure"), "not supported for "+name+" items"))
+		}
+	} else {
+		errs = append(errs, validateCompletionActions(spec.OnComplete, linear, specPath.Child("onComplete"))...)
+		errs = append(errs, validateCompletionActions(spec.OnFailure, linear, specPath.Child("onFailure"))...)
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(axonv1alpha1.GroupVersion.WithKind("TaskSpawner").GroupKind(), ts.Name, errs)
+	}
+	return nil
+}
+
+// validateWhen checks that exactly one source is set.
+func validateWhen(when *axonv1alpha1.When, path *field.Path) field.ErrorList {
+	var sources []string
+	if when.GitHubIssues != nil {
+		sources = append(sources, "githubIssues")
+	}
+	if when.GitHubComments != nil {
+		sources = append(sources, "githubComments")
+	}
+	if when.GitHubWorkflowRuns != nil {
+		sources = append(sources, "githubWorkflowRuns")
+	}
+	if when.HTTP != nil {
+		sources = append(sources, "http")
+	}
+	if when.JiraIssues != nil {
+		sources = append(sources, "jiraIssues")
+	}
+	if when.LinearIssues != nil {
+		sources = append(sources, "linearIssues")
+	}
+
+	switch len(sources) {
+	case 0:
+		return field.ErrorList{field.Required(path, "exactly one source must be set")}
+	case 1:
+	default:
+		return field.ErrorList{field.Forbidden(path, "exactly one source must be set, got "+strings.Join(sources, ", "))}
+	}
+
+	if when.GitHubIssues != nil {
+		return validateGitHubIssues(when.GitHubIssues, path.Child("githubIssues"))
+	}
+	if gc := when.GitHubComments; gc != nil && (gc.WorkspaceRef == nil || gc.WorkspaceRef.Name == "") {
+		return field.ErrorList{field.Required(path.Child("githubComments", "workspaceRef", "name"), "the Workspace defines the repository to discover commands in")}
+	}
+	if wr := when.GitHubWorkflowRuns; wr != nil && (wr.WorkspaceRef == nil || wr.WorkspaceRef.Name == "") {
+		return field.ErrorList{field.Required(path.Child("githubWorkflowRuns", "workspaceRef", "name"), "the Workspace defines the repository to discover workflow runs in")}
+	}
+	if when.HTTP != nil {
+		return validateHTTP(when.HTTP, path.Child("http"))
+	}
+	if ji := when.JiraIssues; ji != nil {
+		if _, err := url.ParseRequestURI(ji.URL); err != nil {
+			return field.ErrorList{field.Invalid(path.Child("jiraIssues", "url"), ji.URL, err.Error())}
+		}
+	}
+	if li := when.LinearIssues; li != nil && (li.SecretRef == nil || li.SecretRef.Name == "") {
+		return field.ErrorList{field.Required(path.Child("linearIssues", "secretRef", "name"), "the Secret holds the LINEAR_API_KEY to query Linear with")}
+	}
+	return nil
+}
+
+// actionlessSource returns the name of the source, if completion actions
+// cannot be applied to its items.
+func actionlessSource(when *axonv1alpha1.When) string {
+	switch {
+	case when.GitHubWorkflowRuns != nil:
+		return "githubWorkflowRuns"
+	case when.HTTP != nil:
+		return "http"
+	case when.JiraIssues != nil:
+		return "jiraIssues"
+	}
+	return ""
+}
+
+// validateHTTP checks that the JSONPath expressions parse, that every
+// header has a value, and that the pagination scheme has the path and
+// parameter it needs.
+func validateHTTP(h *axonv1alpha1.HTTPSource, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if _, err := url.ParseRequestURI(h.URL); err != nil {
+		errs = append(errs, field.Invalid(path.Child("url"), h.URL, err.Error()))
+	}
+	for i, header := range h.Headers {
+		if header.Value == "" && header.SecretKeyRef == nil {
+			errs = append(errs, field.Required(path.Child("headers").Index(i), "value or secretKeyRef must be set"))
+		}
+	}
+
+	if h.Fields.ID == "" {
+		errs = append(errs, field.Required(path.Child("fields", "id"), "items are identified by their ID"))
+	}
+	type jsonPath struct {
+		path *field.Path
+		expr string
+	}
+	exprs := []jsonPath{
+		{path.Child("itemsPath"), h.ItemsPath},
+		{path.Child("fields", "id"), h.Fields.ID},
+		{path.Child("fields", "number"), h.Fields.Number},
+		{path.Child("fields", "title"), h.Fields.Title},
+		{path.Child("fields", "body"), h.Fields.Body},
+		{path.Child("fields", "url"), h.Fields.URL},
+		{path.Child("fields", "labels"), h.Fields.Labels},
+	}
+	if p := h.Pagination; p != nil {
+		exprs = append(exprs, jsonPath{path.Child("pagination", "path"), p.Path})
+
+		needsPath := p.Type == source.PaginationNextURL || p.Type == source.PaginationCursor
+		needsParam := p.Type == source.PaginationCursor || p.Type == source.PaginationPage
+		if needsPath && p.Path == "" {
+			errs = append(errs, field.Required(path.Child("pagination", "path"), "selects the next page of "+p.Type+" pagination"))
+		}
+		if needsParam && p.Param == "" {
+			errs = append(errs, field.Required(path.Child("pagination", "param"), "sends the next page of "+p.Type+" pagination"))
+		}
+	}
+	for _, e := range exprs {
+		if e.expr == "" {
+			continue
+		}
+		if _, err := source.ParseJSONPath(e.expr); err != nil {
+			errs = append(errs, field.Invalid(e.path, e.expr, err.Error()))
+		}
+	}
+	return errs
+}
+
+// searchScopeQualifiers widen a search beyond a single repository.
+var searchScopeQualifiers = []string{"repo:", "org:", "user:"}
+
+// validateGitHubIssues checks that the Workspace is set, that the time
+// windows are positive and that the search query stays in the Workspace's
+// repository.
+func validateGitHubIssues(gh *axonv1alpha1.GitHubIssues, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if gh.WorkspaceRef == nil || gh.WorkspaceRef.Name == "" {
+		errs = append(errs, field.Required(path.Child("workspaceRef", "name"), "the Workspace defines the repository to discover issues in"))
+	}
+	if d := gh.CreatedWithin; d != nil && d.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("createdWithin"), d.Duration.String(), "must be positive"))
+	}
+	if d := gh.UpdatedWithin; d != nil && d.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("updatedWithin"), d.Duration.String(), "must be positive"))
+	}
+	for _, term := range strings.Fields(gh.Query) {
+		term = strings.TrimPrefix(strings.ToLower(term), "-")
+		for _, q := range searchScopeQualifiers {
+			if strings.HasPrefix(term, q) {
+				errs = append(errs, field.Invalid(path.Child("query"), gh.Query, "must not use the "+q+" qualifier, issues are searched in the Workspace's repository"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateCompletionActions checks that the comment template renders, and
+// that the actions are supported by the items of the source: Linear issues
+// are moved to a state and commented on, GitHub items have no state.
+func validateCompletionActions(actions *axonv1alpha1.CompletionActions, linear bool, path *field.Path) field.ErrorList {
+	if actions == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if actions.Comment != "" {
+		if _, err := source.RenderComment(actions.Comment, source.TaskResult{}); err != nil {
+			errs = append(errs, field.Invalid(path.Child("comment"), actions.Comment, err.Error()))
+		}
+	}
+	if !linear {
+		if actions.State != "" {
+			errs = append(errs, field.Forbidden(path.Child("state"), "only Linear issues are moved to a state"))
+		}
+		return errs
+	}
+	if len(actions.AddLabels) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("addLabels"), "not supported for Linear issues"))
+	}
+	if len(actions.RemoveLabels) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("removeLabels"), "not supported for Linear issues"))
+	}
+	if len(actions.Assignees) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("assignees"), "not supported for Linear issues"))
+	}
+	if actions.Close {
+		errs = append(errs, field.Forbidden(path.Child("close"), "not supported for Linear issues, set the state instead"))
+	}
+	return errs
+}
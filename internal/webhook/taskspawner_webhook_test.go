@@ -0,0 +1,315 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func validTaskSpawner() *axonv1alpha1.TaskSpawner {
+	return &axonv1alpha1.TaskSpawner{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-spawner"},
+		Spec: axonv1alpha1.TaskSpawnerSpec{
+			When: axonv1alpha1.When{
+				GitHubIssues: &axonv1alpha1.GitHubIssues{
+					WorkspaceRef: &axonv1alpha1.WorkspaceReference{Name: "test-workspace"},
+				},
+			},
+			TaskTemplate: axonv1alpha1.TaskTemplate{
+				Type:           "claude-code",
+				PromptTemplate: "Fix {{.Kind}} #{{.Number}}: {{.Title}}",
+			},
+			PollInterval: "5m",
+		},
+	}
+}
+
+func TestValidateTaskSpawner(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*axonv1alpha1.TaskSpawner)
+		wantErr string
+	}{
+		{
+			name:   "Valid",
+			mutate: func(*axonv1alpha1.TaskSpawner) {},
+		},
+		{
+			name:    "No source",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.When = axonv1alpha1.When{} },
+			wantErr: "spec.when",
+		},
+		{
+			name:    "GitHub issues without workspace",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.When.GitHubIssues.WorkspaceRef = nil },
+			wantErr: "spec.when.githubIssues.workspaceRef.name",
+		},
+		{
+			name: "GitHub issue filters",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				gh := ts.Spec.When.GitHubIssues
+				gh.Assignee = "@me"
+				gh.AuthorAssociations = []string{"OWNER", "MEMBER"}
+				gh.UpdatedWithin = &metav1.Duration{Duration: 24 * time.Hour}
+				gh.Query = "-label:wontfix in:title crash"
+			},
+		},
+		{
+			name: "Negative time window",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When.GitHubIssues.CreatedWithin = &metav1.Duration{Duration: -time.Hour}
+			},
+			wantErr: "spec.when.githubIssues.createdWithin",
+		},
+		{
+			name:    "Search query in another repository",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.When.GitHubIssues.Query = "is:issue repo:other/repo" },
+			wantErr: "spec.when.githubIssues.query",
+		},
+		{
+			name: "GitHub comments",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{GitHubComments: &axonv1alpha1.GitHubComments{
+					WorkspaceRef: &axonv1alpha1.WorkspaceReference{Name: "test-workspace"},
+				}}
+				ts.Spec.TaskTemplate.PromptTemplate = "{{.Args}} requested by {{.Requester}}"
+			},
+		},
+		{
+			name: "GitHub comments without workspace",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{GitHubComments: &axonv1alpha1.GitHubComments{}}
+			},
+			wantErr: "spec.when.githubComments.workspaceRef.name",
+		},
+		{
+			name: "GitHub workflow runs",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{GitHubWorkflowRuns: &axonv1alpha1.GitHubWorkflowRuns{
+					WorkspaceRef: &axonv1alpha1.WorkspaceReference{Name: "test-workspace"},
+					Workflows:    []string{"ci.yaml"},
+				}}
+				ts.Spec.TaskTemplate.PromptTemplate = "Fix {{.Workflow}} on {{.Branch}}:\n{{.Logs}}"
+			},
+		},
+		{
+			name: "GitHub workflow runs without workspace",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{GitHubWorkflowRuns: &axonv1alpha1.GitHubWorkflowRuns{}}
+			},
+			wantErr: "spec.when.githubWorkflowRuns.workspaceRef.name",
+		},
+		{
+			name: "HTTP",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{HTTP: &axonv1alpha1.HTTPSource{
+					URL: "https://tickets.example.com/api/search?state=open",
+					Headers: []axonv1alpha1.HTTPHeader{
+						{Name: "Authorization", Value: "Bearer ", SecretKeyRef: &axonv1alpha1.SecretKeySelector{Name: "tickets", Key: "token"}},
+					},
+					ItemsPath:  "{.issues[*]}",
+					Fields:     axonv1alpha1.HTTPFields{ID: "{.key}", Title: ".fields.summary", Labels: "{.fields.labels[*].name}"},
+					Pagination: &axonv1alpha1.HTTPPagination{Type: "cursor", Path: "{.next}", Param: "after"},
+				}}
+			},
+		},
+		{
+			name: "HTTP with an invalid JSONPath",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{HTTP: &axonv1alpha1.HTTPSource{
+					URL:    "https://tickets.example.com/api/search",
+					Fields: axonv1alpha1.HTTPFields{ID: "{.key}", Title: "{.fields[}"},
+				}}
+			},
+			wantErr: "spec.when.http.fields.title",
+		},
+		{
+			name: "HTTP header without value",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{HTTP: &axonv1alpha1.HTTPSource{
+					URL:     "https://tickets.example.com/api/search",
+					Headers: []axonv1alpha1.HTTPHeader{{Name: "Authorization"}},
+					Fields:  axonv1alpha1.HTTPFields{ID: "{.key}"},
+				}}
+			},
+			wantErr: "spec.when.http.headers[0]",
+		},
+		{
+			name: "HTTP cursor pagination without parameter",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{HTTP: &axonv1alpha1.HTTPSource{
+					URL:        "https://tickets.example.com/api/search",
+					Fields:     axonv1alpha1.HTTPFields{ID: "{.key}"},
+					Pagination: &axonv1alpha1.HTTPPagination{Type: "cursor", Path: "{.next}"},
+				}}
+			},
+			wantErr: "spec.when.http.pagination.param",
+		},
+		{
+			name: "Jira issues",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{JiraIssues: &axonv1alpha1.JiraIssues{
+					URL:       "https://example.atlassian.net",
+					JQL:       "project = PROJ AND labels = axon",
+					SecretRef: &axonv1alpha1.SecretReference{Name: "jira"},
+				}}
+			},
+		},
+		{
+			name: "Jira issues with an invalid URL",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{JiraIssues: &axonv1alpha1.JiraIssues{
+					URL: "https://jira example.com",
+					JQL: "project = PROJ",
+				}}
+			},
+			wantErr: "spec.when.jiraIssues.url",
+		},
+		{
+			name: "Two sources",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When.GitHubComments = &axonv1alpha1.GitHubComments{
+					WorkspaceRef: &axonv1alpha1.WorkspaceReference{Name: "test-workspace"},
+				}
+			},
+			wantErr: "exactly one source",
+		},
+		{
+			name:    "Unknown type",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.TaskTemplate.Type = "codex" },
+			wantErr: "spec.taskTemplate.type",
+		},
+		{
+			name:    "Unparsable prompt template",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.TaskTemplate.PromptTemplate = "{{.Title" },
+			wantErr: "spec.taskTemplate.promptTemplate",
+		},
+		{
+			name:    "Prompt template with unknown field",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.TaskTemplate.PromptTemplate = "{{.Tittle}}" },
+			wantErr: "spec.taskTemplate.promptTemplate",
+		},
+		{
+			name:    "Invalid poll interval",
+			mutate:  func(ts *axonv1alpha1.TaskSpawner) { ts.Spec.PollInterval = "often" },
+			wantErr: "spec.pollInterval",
+		},
+		{
+			name: "Completion actions",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.OnComplete = &axonv1alpha1.CompletionActions{AddLabels: []string{"axon/done"}, Comment: "Done in {{.Duration}}", Close: true}
+				ts.Spec.OnFailure = &axonv1alpha1.CompletionActions{AddLabels: []string{"axon/needs-input"}}
+			},
+		},
+		{
+			name: "Comment template with unknown field",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.OnFailure = &axonv1alpha1.CompletionActions{Comment: "{{.Title}} failed"}
+			},
+			wantErr: "spec.onFailure.comment",
+		},
+		{
+			name: "Linear issues with completion actions",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{LinearIssues: &axonv1alpha1.LinearIssues{
+					Team:      "ENG",
+					Labels:    []string{"Agent-ready"},
+					SecretRef: &axonv1alpha1.SecretReference{Name: "linear"},
+				}}
+				ts.Spec.OnComplete = &axonv1alpha1.CompletionActions{State: "In Review", Comment: "Done in {{.Duration}}"}
+			},
+		},
+		{
+			name: "Linear issues without secret",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{LinearIssues: &axonv1alpha1.LinearIssues{Team: "ENG"}}
+			},
+			wantErr: "spec.when.linearIssues.secretRef.name",
+		},
+		{
+			name: "Linear issues with labels added on completion",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{LinearIssues: &axonv1alpha1.LinearIssues{
+					Team:      "ENG",
+					SecretRef: &axonv1alpha1.SecretReference{Name: "linear"},
+				}}
+				ts.Spec.OnFailure = &axonv1alpha1.CompletionActions{AddLabels: []string{"agent-failed"}}
+			},
+			wantErr: "spec.onFailure.addLabels",
+		},
+		{
+			name: "Completion actions for workflow runs",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{GitHubWorkflowRuns: &axonv1alpha1.GitHubWorkflowRuns{
+					WorkspaceRef: &axonv1alpha1.WorkspaceReference{Name: "test-workspace"},
+				}}
+				ts.Spec.OnFailure = &axonv1alpha1.CompletionActions{Comment: "Failed"}
+			},
+			wantErr: "spec.onFailure: Forbidden",
+		},
+		{
+			name: "Completion actions for HTTP items",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{HTTP: &axonv1alpha1.HTTPSource{
+					URL:    "https://tickets.example.com/api/search",
+					Fields: axonv1alpha1.HTTPFields{ID: "{.key}"},
+				}}
+				ts.Spec.OnComplete = &axonv1alpha1.CompletionActions{Close: true}
+			},
+			wantErr: "spec.onComplete: Forbidden",
+		},
+		{
+			name: "Completion actions for Jira issues",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.When = axonv1alpha1.When{JiraIssues: &axonv1alpha1.JiraIssues{
+					URL: "https://example.atlassian.net",
+					JQL: "project = PROJ",
+				}}
+				ts.Spec.OnComplete = &axonv1alpha1.CompletionActions{AddLabels: []string{"done"}}
+			},
+			wantErr: "spec.onComplete: Forbidden",
+		},
+		{
+			name: "State on completion of GitHub issues",
+			mutate: func(ts *axonv1alpha1.TaskSpawner) {
+				ts.Spec.OnComplete = &axonv1alpha1.CompletionActions{State: "Done"}
+			},
+			wantErr: "spec.onComplete.state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := validTaskSpawner()
+			tt.mutate(ts)
+			err := validateTaskSpawner(ts)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error about %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTaskSpawnerDefault(t *testing.T) {
+	ts := validTaskSpawner()
+	ts.Spec.TaskTemplate.Type = ""
+	ts.Spec.PollInterval = "90"
+
+	if err := (&TaskSpawnerWebhook{}).Default(context.Background(), ts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ts.Spec.TaskTemplate.Type != "claude-code" {
+		t.Errorf("Type = %q, want claude-code", ts.Spec.TaskTemplate.Type)
+	}
+	if ts.Spec.PollInterval != "1m30s" {
+		t.Errorf("PollInterval = %q, want 1m30s", ts.Spec.PollInterval)
+	}
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-axon-io-v1alpha1-workspace,mutating=true,failurePolicy=fail,sideEffects=None,groups=axon.io,resources=workspaces,verbs=create;update,versions=v1alpha1,name=mworkspace.axon.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-axon-io-v1alpha1-workspace,mutating=false,failurePolicy=fail,sideEffects=None,groups=axon.io,resources=workspaces,verbs=create;update,versions=v1alpha1,name=vworkspace.axon.io,admissionReviewVersions=v1
+
+// WorkspaceWebhook defaults and validates Workspaces.
+type WorkspaceWebhook struct{}
+
+// SetupWithManager registers the webhook with the Manager.
+func (w *WorkspaceWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &axonv1alpha1.Workspace{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default implements admission.Defaulter.
+func (w *WorkspaceWebhook) Default(_ context.Context, ws *axonv1alpha1.Workspace) error {
+	ws.Spec.Repo = strings.TrimSpace(ws.Spec.Repo)
+	ws.Spec.Ref = strings.TrimSpace(ws.Spec.Ref)
+	// Repositories are often copied without a scheme, e.g.
+	// github.com/org/repo, which git cannot clone
+	if ws.Spec.Repo != "" && !strings.Contains(ws.Spec.Repo, "://") && !strings.HasPrefix(ws.Spec.Repo, "git@") {
+		ws.Spec.Repo = "https://" + ws.Spec.Repo
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.Validator.
+func (w *WorkspaceWebhook) ValidateCreate(_ context.Context, ws *axonv1alpha1.Workspace) (admission.Warnings, error) {
+	return nil, validateWorkspace(ws)
+}
+
+// ValidateUpdate implements admission.Validator.
+func (w *WorkspaceWebhook) ValidateUpdate(_ context.Context, old, ws *axonv1alpha1.Workspace) (admission.Warnings, error) {
+	if equality.Semantic.DeepEqual(old.Spec, ws.Spec) {
+		return nil, nil
+	}
+	return nil, validateWorkspace(ws)
+}
+
+// ValidateDelete implements admission.Validator.
+func (w *WorkspaceWebhook) ValidateDelete(_ context.Context, _ *axonv1alpha1.Workspace) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateWorkspace(ws *axonv1alpha1.Workspace) error {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+
+	if msg := invalidRepo(ws.Spec.Repo); msg != "" {
+		errs = append(errs, field.Invalid(specPath.Child("repo"), ws.Spec.Repo, msg))
+	}
+	if msg := invalidRef(ws.Spec.Ref); msg != "" {
+		errs = append(errs, field.Invalid(specPath.Child("ref"), ws.Spec.Ref, msg))
+	}
+	if ws.Spec.SecretRef != nil && ws.Spec.SecretRef.Name == "" {
+		errs = append(errs, field.Required(specPath.Child("secretRef", "name"), ""))
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(axonv1alpha1.GroupVersion.WithKind("Workspace").GroupKind(), ws.Name, errs)
+	}
+	return nil
+}
+
+// invalidRepo describes why repo cannot be cloned, or returns an empty
+// string if it can.
+func invalidRepo(repo string) string {
+	if strings.HasPrefix(repo, "git@") {
+		// scp-like syntax, e.g. git@github.com:org/repo.git
+		host, path, ok := strings.Cut(strings.TrimPrefix(repo, "git@"), ":")
+		if !ok || host == "" || path == "" {
+			return "must have the form git@host:path"
+		}
+		return ""
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil {
+		return err.Error()
+	}
+	switch u.Scheme {
+	case "http", "https", "git":
+	default:
+		return "must be an http, https or git URL, or have the form git@host:path"
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return "must include the host and path of the repository"
+	}
+	return ""
+}
+
+// invalidRef describes why ref is not a valid branch, tag or commit, or
+// returns an empty string if it is. It follows the rules of
+// git check-ref-format that matter when the ref is passed to git clone.
+func invalidRef(ref string) string {
+	switch {
+	case ref == "":
+		return ""
+	case strings.HasPrefix(ref, "-"):
+		return "must not start with a dash"
+	case strings.HasPrefix(ref, "/"), strings.HasSuffix(ref, "/"), strings.HasSuffix(ref, "."), strings.HasSuffix(ref, ".lock"):
+		return "must not start or end with a slash, or end with a dot or .lock"
+	case strings.Contains(ref, ".."), strings.Contains(ref, "@{"), strings.Contains(ref, "//"):
+		return "must not contain .., @{ or //"
+	case strings.ContainsAny(ref, " ~^:?*[\\"):
+		return "must not contain spaces or any of ~^:?*[\\"
+	}
+	for _, r := range ref {
+		if r < 0x20 || r == 0x7f {
+			return "must not contain control characters"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+func TestWorkspaceDefault(t *testing.T) {
+	tests := []struct {
+		repo string
+		want string
+	}{
+		{repo: "https://github.com/org/repo.git", want: "https://github.com/org/repo.git"},
+		{repo: " github.com/org/repo ", want: "https://github.com/org/repo"},
+		{repo: "git@github.com:org/repo.git", want: "git@github.com:org/repo.git"},
+		{repo: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			ws := &axonv1alpha1.Workspace{Spec: axonv1alpha1.WorkspaceSpec{Repo: tt.repo}}
+			if err := (&WorkspaceWebhook{}).Default(context.Background(), ws); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ws.Spec.Repo != tt.want {
+				t.Errorf("Repo = %q, want %q", ws.Spec.Repo, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidRepo(t *testing.T) {
+	tests := []struct {
+		repo    string
+		invalid bool
+	}{
+		{repo: "https://github.com/org/repo.git"},
+		{repo: "http://git.example.com/org/repo"},
+		{repo: "git://example.com/repo.git"},
+		{repo: "git@github.com:org/repo.git"},
+		{repo: "git@github.com", invalid: true},
+		{repo: "ftp://example.com/repo.git", invalid: true},
+		{repo: "https://github.com", invalid: true},
+		{repo: "https:///org/repo", invalid: true},
+		{repo: "", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			msg := invalidRepo(tt.repo)
+			if tt.invalid && msg == "" {
+				t.Errorf("expected %q to be invalid", tt.repo)
+			}
+			if !tt.invalid && msg != "" {
+				t.Errorf("expected %q to be valid, got %q", tt.repo, msg)
+			}
+		})
+	}
+}
+
+func TestInvalidRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		invalid bool
+	}{
+		{ref: ""},
+		{ref: "main"},
+		{ref: "feature/login"},
+		{ref: "v1.2.3"},
+		{ref: "3f2a9c1"},
+		{ref: "-main", invalid: true},
+		{ref: "feature/", invalid: true},
+		{ref: "/main", invalid: true},
+		{ref: "main.", invalid: true},
+		{ref: "main.lock", invalid: true},
+		{ref: "feature..branch", invalid: true},
+		{ref: "main@{1}", invalid: true},
+		{ref: "feature//login", invalid: true},
+		{ref: "my branch", invalid: true},
+		{ref: "main~1", invalid: true},
+		{ref: "refs:main", invalid: true},
+		{ref: "main\x01", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			msg := invalidRef(tt.ref)
+			if tt.invalid && msg == "" {
+				t.Errorf("expected %q to be invalid", tt.ref)
+			}
+			if !tt.invalid && msg != "" {
+				t.Errorf("expected %q to be valid, got %q", tt.ref, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+var _ = Describe("AxonConfig", func() {
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	Context("When a namespace has an AxonConfig with task defaults", func() {
+		It("Should merge the defaults into the Task's Job and status", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-axonconfig-defaults",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating an AxonConfig")
+			deadline := int64(1800)
+			ttl := int32(600)
+			config := &axonv1alpha1.AxonConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-defaults",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.AxonConfigSpec{
+					TaskDefaults: &axonv1alpha1.TaskDefaults{
+						Credentials: &axonv1alpha1.Credentials{
+							Type: axonv1alpha1.CredentialTypeOAuth,
+							SecretRef: axonv1alpha1.SecretReference{
+								Name: "team-credentials",
+							},
+						},
+						Model:                   "opus",
+						ActiveDeadlineSeconds:   &deadline,
+						TTLSecondsAfterFinished: &ttl,
+						DisallowedTools:         []string{"WebFetch"},
+						PodOverrides: &axonv1alpha1.PodOverrides{
+							NodeSelector: map[string]string{"pool": "agents"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, config)).Should(Succeed())
+
+			By("Creating a Task without credentials")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-task",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Fix the bug",
+					Model:  "haiku",
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			By("Verifying a Job is created with the merged spec")
+			jobLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdJob := &batchv1.Job{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, jobLookupKey, createdJob)
+			}, timeout, interval).Should(Succeed())
+
+			container := createdJob.Spec.Template.Spec.Containers[0]
+			Expect(container.Env).To(HaveLen(1))
+			Expect(container.Env[0].Name).To(Equal("CLAUDE_CODE_OAUTH_TOKEN"))
+			Expect(container.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal("team-credentials"))
+			Expect(container.Args).To(ContainElements("--model", "haiku"))
+			Expect(container.Args).To(ContainElements("--disallowedTools", "WebFetch"))
+			Expect(createdJob.Spec.ActiveDeadlineSeconds).NotTo(BeNil())
+			Expect(*createdJob.Spec.ActiveDeadlineSeconds).To(Equal(deadline))
+			Expect(createdJob.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("pool", "agents"))
+
+			By("Verifying the effective spec is recorded in the Task status")
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			Eventually(func() *axonv1alpha1.TaskSpec {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return nil
+				}
+				return createdTask.Status.EffectiveSpec
+			}, timeout, interval).ShouldNot(BeNil())
+
+			effective := createdTask.Status.EffectiveSpec
+			Expect(effective.Model).To(Equal("haiku"))
+			Expect(effective.Credentials).NotTo(BeNil())
+			Expect(effective.Credentials.SecretRef.Name).To(Equal("team-credentials"))
+			Expect(effective.TTLSecondsAfterFinished).NotTo(BeNil())
+			Expect(*effective.TTLSecondsAfterFinished).To(Equal(ttl))
+			Expect(createdTask.Spec.Credentials).To(BeNil())
+		})
+	})
+
+	Context("When a Task has no credentials and no AxonConfig provides them", func() {
+		It("Should fail with a meaningful error", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-axonconfig-missing",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Task without credentials")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-task",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Fix the bug",
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			By("Verifying the Task status is Failed")
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseFailed))
+
+			Expect(createdTask.Status.Message).To(ContainSubstring("AxonConfig"))
+		})
+	})
+})
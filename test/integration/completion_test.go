@@ -66,7 +66,7 @@ var _ = Describe("Completion", func() {
 					Spec: axonv1alpha1.TaskSpec{
 						Type:   "claude-code",
 						Prompt: "test",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeAPIKey,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "test-secret",
@@ -104,7 +104,7 @@ var _ = Describe("Completion", func() {
 				Spec: axonv1alpha1.TaskSpawnerSpec{
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeAPIKey,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "test-secret",
@@ -141,7 +141,7 @@ var _ = Describe("Completion", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "test",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "test-secret",
@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+	"github.com/axon-core/axon/internal/notify"
+)
+
+// notificationReceiver records the webhook notifications it receives.
+type notificationReceiver struct {
+	mu         sync.Mutex
+	status     int
+	bodies     [][]byte
+	signatures []string
+}
+
+func (r *notificationReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+	r.signatures = append(r.signatures, req.Header.Get(notify.SignatureHeader))
+	w.WriteHeader(r.status)
+}
+
+func (r *notificationReceiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+var _ = Describe("Task notifications", func() {
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	// createNotifyingTask creates a namespace with an AxonConfig notifying
+	// the URL, and a Task in it, and completes the Task's Job.
+	createNotifyingTask := func(nsName, url string) types.NamespacedName {
+		By("Creating a namespace")
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nsName,
+			},
+		}
+		Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+		By("Creating the webhook Secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-secret",
+				Namespace: ns.Name,
+			},
+			StringData: map[string]string{
+				"WEBHOOK_SECRET": "s3cret",
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+
+		By("Creating an AxonConfig with a webhook notification")
+		config := &axonv1alpha1.AxonConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "default",
+				Namespace: ns.Name,
+			},
+			Spec: axonv1alpha1.AxonConfigSpec{
+				Notifications: []axonv1alpha1.Notification{
+					{
+						Name: "ops",
+						Webhook: &axonv1alpha1.WebhookNotification{
+							URL:       url,
+							SecretRef: &axonv1alpha1.SecretReference{Name: "webhook-secret"},
+						},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, config)).Should(Succeed())
+
+		By("Creating a Task")
+		task := &axonv1alpha1.Task{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-notify",
+				Namespace: ns.Name,
+			},
+			Spec: axonv1alpha1.TaskSpec{
+				Type:   "claude-code",
+				Prompt: "Create a hello world program",
+				Credentials: &axonv1alpha1.Credentials{
+					Type: axonv1alpha1.CredentialTypeAPIKey,
+					SecretRef: axonv1alpha1.SecretReference{
+						Name: "anthropic-api-key",
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+		By("Simulating Job completion")
+		key := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+		job := &batchv1.Job{}
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, key, job); err != nil {
+				return err
+			}
+			job.Status.Succeeded = 1
+			return k8sClient.Status().Update(ctx, job)
+		}, timeout, interval).Should(Succeed())
+		return key
+	}
+
+	Context("When a Task succeeds", func() {
+		It("Should post a signed notification to the webhook", func() {
+			recv := &notificationReceiver{status: http.StatusOK}
+			srv := httptest.NewServer(recv)
+			defer srv.Close()
+
+			key := createNotifyingTask("test-notify-delivered", srv.URL)
+
+			By("Verifying the notification is delivered")
+			task := &axonv1alpha1.Task{}
+			Eventually(func() []axonv1alpha1.NotificationStatus {
+				if err := k8sClient.Get(ctx, key, task); err != nil {
+					return nil
+				}
+				return task.Status.Notifications
+			}, timeout, interval).Should(ContainElement(And(
+				HaveField("Name", "ops"),
+				HaveField("Phase", axonv1alpha1.TaskPhaseSucceeded),
+				HaveField("State", axonv1alpha1.NotificationStateDelivered),
+				HaveField("Attempts", int32(1)),
+			)))
+
+			By("Verifying the receiver got the signed payload once")
+			Consistently(recv.received, time.Second, interval).Should(Equal(1))
+			recv.mu.Lock()
+			defer recv.mu.Unlock()
+			Expect(recv.signatures[0]).To(Equal(notify.Sign("s3cret", recv.bodies[0])))
+			var event notify.Event
+			Expect(json.Unmarshal(recv.bodies[0], &event)).To(Succeed())
+			Expect(event.Namespace).To(Equal(key.Namespace))
+			Expect(event.Task).To(Equal(key.Name))
+			Expect(event.Phase).To(Equal(string(axonv1alpha1.TaskPhaseSucceeded)))
+		})
+	})
+
+	Context("When the webhook is failing", func() {
+		It("Should record the error and keep the notification pending", func() {
+			recv := &notificationReceiver{status: http.StatusServiceUnavailable}
+			srv := httptest.NewServer(recv)
+			defer srv.Close()
+
+			key := createNotifyingTask("test-notify-pending", srv.URL)
+
+			By("Verifying the failed attempt is recorded")
+			task := &axonv1alpha1.Task{}
+			Eventually(func() []axonv1alpha1.NotificationStatus {
+				if err := k8sClient.Get(ctx, key, task); err != nil {
+					return nil
+				}
+				return task.Status.Notifications
+			}, timeout, interval).Should(ContainElement(And(
+				HaveField("Name", "ops"),
+				HaveField("State", axonv1alpha1.NotificationStatePending),
+				HaveField("Attempts", int32(1)),
+				HaveField("LastError", ContainSubstring("503")),
+			)))
+			Expect(task.Status.Phase).To(Equal(axonv1alpha1.TaskPhaseSucceeded))
+		})
+	})
+})
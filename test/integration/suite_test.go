@@ -1,13 +1,19 @@
 package integration
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"io"
 	"path/filepath"
 	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -15,9 +21,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/yaml"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
 	"github.com/axon-core/axon/internal/controller"
+	"github.com/axon-core/axon/internal/manifests"
+	"github.com/axon-core/axon/internal/webhook"
 )
 
 var (
@@ -42,6 +52,7 @@ var _ = BeforeSuite(func() {
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "..")},
 		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: webhookInstallOptions(),
 	}
 
 	var err error
@@ -57,15 +68,26 @@ var _ = BeforeSuite(func() {
 	Expect(k8sClient).NotTo(BeNil())
 
 	// Start controller manager
+	webhookOpts := &testEnv.WebhookInstallOptions
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme.Scheme,
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Host:    webhookOpts.LocalServingHost,
+			Port:    webhookOpts.LocalServingPort,
+			CertDir: webhookOpts.LocalServingCertDir,
+		}),
 	})
 	Expect(err).NotTo(HaveOccurred())
 
+	err = webhook.SetupWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = (&controller.TaskReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
 		JobBuilder: controller.NewJobBuilder(),
+		Recorder:   mgr.GetEventRecorder("axon-controller"),
+		APIReader:  mgr.GetAPIReader(),
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -73,6 +95,7 @@ var _ = BeforeSuite(func() {
 		Client:            mgr.GetClient(),
 		Scheme:            mgr.GetScheme(),
 		DeploymentBuilder: controller.NewDeploymentBuilder(),
+		Recorder:          mgr.GetEventRecorder("axon-controller"),
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -95,8 +118,45 @@ var _ = BeforeSuite(func() {
 	Eventually(func() error {
 		return k8sClient.List(ctx, &axonv1alpha1.WorkspaceList{})
 	}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	Eventually(func() error {
+		return k8sClient.List(ctx, &axonv1alpha1.AxonConfigList{})
+	}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
 })
 
+// webhookInstallOptions returns the webhook configurations from
+// install.yaml, so that the webhooks are tested as they are deployed. They
+// are renamed so that the install and uninstall tests, which apply and
+// delete install.yaml, leave them in place.
+func webhookInstallOptions() envtest.WebhookInstallOptions {
+	var opts envtest.WebhookInstallOptions
+	reader := yamlutil.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifests.InstallController)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		var typeMeta metav1.TypeMeta
+		Expect(yaml.Unmarshal(doc, &typeMeta)).To(Succeed())
+		switch typeMeta.Kind {
+		case "MutatingWebhookConfiguration":
+			config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+			Expect(yaml.Unmarshal(doc, config)).To(Succeed())
+			config.Name += "-integration"
+			opts.MutatingWebhooks = append(opts.MutatingWebhooks, config)
+		case "ValidatingWebhookConfiguration":
+			config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+			Expect(yaml.Unmarshal(doc, config)).To(Succeed())
+			config.Name += "-integration"
+			opts.ValidatingWebhooks = append(opts.ValidatingWebhooks, config)
+		}
+	}
+	Expect(opts.MutatingWebhooks).NotTo(BeEmpty())
+	Expect(opts.ValidatingWebhooks).NotTo(BeEmpty())
+	return opts
+}
+
 var _ = AfterSuite(func() {
 	cancel()
 	By("tearing down the test environment")
@@ -1,15 +1,22 @@
 package integration
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
 	"github.com/axon-core/axon/internal/controller"
@@ -57,7 +64,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Create a hello world program",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -168,6 +175,10 @@ var _ = Describe("Task Controller", func() {
 			By("Verifying Task has completion time")
 			Expect(createdTask.Status.CompletionTime).NotTo(BeNil())
 
+			By("Verifying the Task's conditions")
+			Expect(meta.IsStatusConditionTrue(createdTask.Status.Conditions, axonv1alpha1.TaskConditionJobCreated)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(createdTask.Status.Conditions, axonv1alpha1.TaskConditionSucceeded)).To(BeTrue())
+
 			By("Deleting the Task")
 			Expect(k8sClient.Delete(ctx, createdTask)).Should(Succeed())
 
@@ -210,7 +221,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Create a hello world program",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeOAuth,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "claude-oauth",
@@ -285,7 +296,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Fix the bug",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -408,7 +419,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Create a PR",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -509,7 +520,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Review the code",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -586,7 +597,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Create a hello world program",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -668,7 +679,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Create a hello world program",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -708,8 +719,111 @@ var _ = Describe("Task Controller", func() {
 		})
 	})
 
+	Context("When the TaskSpawner has yet to apply its completion actions", func() {
+		It("Should delete the Task only once they were applied", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-completion-actions",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a TaskSpawner with completion actions")
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fixer",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpawnerSpec{
+					When: axonv1alpha1.When{
+						GitHubIssues: &axonv1alpha1.GitHubIssues{
+							WorkspaceRef: &axonv1alpha1.WorkspaceReference{
+								Name: "test-workspace",
+							},
+						},
+					},
+					TaskTemplate: axonv1alpha1.TaskTemplate{
+						Type: "claude-code",
+					},
+					OnComplete: &axonv1alpha1.CompletionActions{
+						AddLabels: []string{"axon/done"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			By("Creating a spawned Task with TTL=0")
+			ttl := int32(0)
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fixer-42",
+					Namespace: ns.Name,
+					Labels: map[string]string{
+						"axon.io/taskspawner": ts.Name,
+					},
+					Annotations: map[string]string{
+						"axon.io/source-number":      "42",
+						"axon.io/completion-actions": "Pending",
+					},
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Fix issue #42",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+					TTLSecondsAfterFinished: &ttl,
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			createdJob := &batchv1.Job{}
+
+			By("Simulating Job completion")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdJob); err != nil {
+					return err
+				}
+				createdJob.Status.Succeeded = 1
+				return k8sClient.Status().Update(ctx, createdJob)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task is kept while the actions are pending")
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseSucceeded))
+			Consistently(func() error {
+				return k8sClient.Get(ctx, taskLookupKey, createdTask)
+			}, 2*time.Second, interval).Should(Succeed())
+
+			By("Recording the actions as applied, as the spawner does")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				createdTask.Annotations["axon.io/completion-actions"] = "Applied"
+				return k8sClient.Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task is deleted")
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, taskLookupKey, createdTask)
+				return apierrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
 	Context("When creating a Task without TTL", func() {
-		It("Should not delete the Task after it finishes", func() {
+		It("Should only delete the Task once a TTL is set", func() {
 			By("Creating a namespace")
 			ns := &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
@@ -739,7 +853,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Create a hello world program",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -783,6 +897,22 @@ var _ = Describe("Task Controller", func() {
 			Consistently(func() error {
 				return k8sClient.Get(ctx, taskLookupKey, createdTask)
 			}, 3*time.Second, interval).Should(Succeed())
+
+			By("Setting a TTL on the finished Task")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				ttl := int32(0)
+				createdTask.Spec.TTLSecondsAfterFinished = &ttl
+				return k8sClient.Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task is deleted with the edited TTL")
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, taskLookupKey, createdTask)
+				return apierrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
 		})
 	})
 
@@ -817,7 +947,7 @@ var _ = Describe("Task Controller", func() {
 				Spec: axonv1alpha1.TaskSpec{
 					Type:   "claude-code",
 					Prompt: "Fix the bug",
-					Credentials: axonv1alpha1.Credentials{
+					Credentials: &axonv1alpha1.Credentials{
 						Type: axonv1alpha1.CredentialTypeAPIKey,
 						SecretRef: axonv1alpha1.SecretReference{
 							Name: "anthropic-api-key",
@@ -843,6 +973,870 @@ var _ = Describe("Task Controller", func() {
 			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseFailed))
 
 			Expect(createdTask.Status.Message).To(ContainSubstring("nonexistent-workspace"))
+
+			By("Verifying the WorkspaceReady condition is False")
+			cond := meta.FindStatusCondition(createdTask.Status.Conditions, axonv1alpha1.TaskConditionWorkspaceReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("WorkspaceNotFound"))
+
+			By("Verifying a warning Event is recorded for the Task")
+			Eventually(func() []string {
+				var events corev1.EventList
+				if err := k8sClient.List(ctx, &events, client.InNamespace(ns.Name)); err != nil {
+					return nil
+				}
+				var reasons []string
+				for _, e := range events.Items {
+					if e.InvolvedObject.UID == createdTask.UID && e.Type == corev1.EventTypeWarning {
+						reasons = append(reasons, e.Reason)
+					}
+				}
+				return reasons
+			}, timeout, interval).Should(ContainElement("WorkspaceNotFound"))
+		})
+	})
+
+	Context("When suspending and resuming a running Task", func() {
+		It("Should delete the Job while suspended and create a new Job when resumed", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-suspend",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Task")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-suspend",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Refactor the code",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			jobLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			createdJob := &batchv1.Job{}
+
+			By("Waiting for the Job to be created")
+			Eventually(func() error {
+				return k8sClient.Get(ctx, jobLookupKey, createdJob)
+			}, timeout, interval).Should(Succeed())
+			firstJobUID := createdJob.UID
+
+			By("Simulating Job running")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, jobLookupKey, createdJob); err != nil {
+					return err
+				}
+				createdJob.Status.Active = 1
+				return k8sClient.Status().Update(ctx, createdJob)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseRunning))
+
+			By("Suspending the Task")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				createdTask.Spec.Suspend = true
+				return k8sClient.Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task is Suspended and the Job is deleted")
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseSuspended))
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, jobLookupKey, createdJob)
+				return apierrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+
+			By("Verifying the Task itself is kept")
+			Consistently(func() error {
+				return k8sClient.Get(ctx, taskLookupKey, createdTask)
+			}, 2*time.Second, interval).Should(Succeed())
+			Expect(createdTask.Status.JobName).To(Equal(task.Name))
+
+			By("Resuming the Task")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				createdTask.Spec.Suspend = false
+				return k8sClient.Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying a new Job is created")
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, jobLookupKey, createdJob); err != nil {
+					return false
+				}
+				return createdJob.UID != firstJobUID
+			}, timeout, interval).Should(BeTrue())
+
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhasePending))
+		})
+	})
+
+	Context("When editing the spec of a Task", func() {
+		It("Should reject changes to everything but suspend, approved and TTL", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-immutable",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Task")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-immutable",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Refactor the code",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+
+			// update applies mutate to the latest Task, retrying on conflicts
+			// with the controller
+			update := func(mutate func(*axonv1alpha1.Task)) error {
+				var err error
+				Eventually(func() bool {
+					Expect(k8sClient.Get(ctx, taskLookupKey, createdTask)).To(Succeed())
+					mutate(createdTask)
+					err = k8sClient.Update(ctx, createdTask)
+					return !apierrors.IsConflict(err)
+				}, timeout, interval).Should(BeTrue())
+				return err
+			}
+
+			By("Changing the prompt")
+			err := update(func(t *axonv1alpha1.Task) { t.Spec.Prompt = "Rewrite the code" })
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("prompt is immutable"))
+
+			By("Setting the model")
+			err = update(func(t *axonv1alpha1.Task) { t.Spec.Model = "claude-sonnet-4-20250514" })
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("model is immutable"))
+
+			By("Changing the TTL")
+			Expect(update(func(t *axonv1alpha1.Task) {
+				ttl := int32(3600)
+				t.Spec.TTLSecondsAfterFinished = &ttl
+			})).To(Succeed())
+		})
+	})
+
+	Context("When continuing the session of a previous Task", func() {
+		It("Should mount the previous session volume and pass --continue", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-session",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			credentials := &axonv1alpha1.Credentials{
+				Type: axonv1alpha1.CredentialTypeAPIKey,
+				SecretRef: axonv1alpha1.SecretReference{
+					Name: "anthropic-api-key",
+				},
+			}
+
+			By("Creating a Task that persists its session")
+			first := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-session-first",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:           "claude-code",
+					Prompt:         "Fix the bug",
+					Credentials:    credentials,
+					PersistSession: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, first)).Should(Succeed())
+
+			By("Verifying the session PVC is created")
+			pvc := &corev1.PersistentVolumeClaim{}
+			pvcLookupKey := types.NamespacedName{Name: "test-session-first-session", Namespace: ns.Name}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, pvcLookupKey, pvc)
+			}, timeout, interval).Should(Succeed())
+			Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+
+			By("Verifying the Job mounts the session volume")
+			firstJob := &batchv1.Job{}
+			firstJobLookupKey := types.NamespacedName{Name: first.Name, Namespace: ns.Name}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, firstJobLookupKey, firstJob)
+			}, timeout, interval).Should(Succeed())
+			Expect(firstJob.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(firstJob.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(pvcLookupKey.Name))
+			Expect(firstJob.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath).To(Equal(controller.SessionMountPath))
+
+			By("Creating a Task that continues the first Task")
+			second := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-session-second",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:        "claude-code",
+					Prompt:      "Now also add tests",
+					Credentials: credentials,
+					ContinueFrom: &axonv1alpha1.TaskReference{
+						Name: first.Name,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, second)).Should(Succeed())
+
+			By("Verifying the continuing Task waits while the first Task runs")
+			secondLookupKey := types.NamespacedName{Name: second.Name, Namespace: ns.Name}
+			createdSecond := &axonv1alpha1.Task{}
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, secondLookupKey, createdSecond); err != nil {
+					return ""
+				}
+				return createdSecond.Status.Message
+			}, timeout, interval).Should(ContainSubstring("Waiting for Task"))
+			Expect(createdSecond.Status.Phase).To(Equal(axonv1alpha1.TaskPhasePending))
+
+			secondJob := &batchv1.Job{}
+			Consistently(func() bool {
+				err := k8sClient.Get(ctx, secondLookupKey, secondJob)
+				return apierrors.IsNotFound(err)
+			}, 2*time.Second, interval).Should(BeTrue())
+
+			By("Simulating completion of the first Job")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, firstJobLookupKey, firstJob); err != nil {
+					return err
+				}
+				firstJob.Status.Succeeded = 1
+				return k8sClient.Status().Update(ctx, firstJob)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the continuing Job reuses the session volume")
+			Eventually(func() error {
+				return k8sClient.Get(ctx, secondLookupKey, secondJob)
+			}, 2*timeout, interval).Should(Succeed())
+			logJobSpec(secondJob)
+			Expect(secondJob.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(secondJob.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(pvcLookupKey.Name))
+			Expect(secondJob.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--continue"))
+
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, secondLookupKey, createdSecond); err != nil {
+					return ""
+				}
+				return createdSecond.Status.SessionClaimName
+			}, timeout, interval).Should(Equal(pvcLookupKey.Name))
+
+			By("Verifying the PVC is owned by both Tasks")
+			Expect(k8sClient.Get(ctx, pvcLookupKey, pvc)).To(Succeed())
+			Expect(pvc.OwnerReferences).To(HaveLen(2))
+
+			By("Creating another Task that continues the first Task while the second runs")
+			third := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-session-third",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:        "claude-code",
+					Prompt:      "Also update the docs",
+					Credentials: credentials,
+					ContinueFrom: &axonv1alpha1.TaskReference{
+						Name: first.Name,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, third)).Should(Succeed())
+
+			By("Verifying the second continuation is rejected")
+			createdThird := &axonv1alpha1.Task{}
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: third.Name, Namespace: ns.Name}, createdThird); err != nil {
+					return ""
+				}
+				return createdThird.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseFailed))
+			Expect(createdThird.Status.Message).To(ContainSubstring(second.Name))
+		})
+
+		It("Should fail when the previous Task did not persist its session", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-session-missing",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Task that continues a nonexistent Task")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-session-missing",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Keep going",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+					ContinueFrom: &axonv1alpha1.TaskReference{
+						Name: "does-not-exist",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			By("Verifying the Task fails")
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseFailed))
+			Expect(createdTask.Status.Message).To(ContainSubstring("does-not-exist"))
+		})
+	})
+
+	Context("When the agent of a Task asks for human input", func() {
+		It("Should wait for an answer and apply the default answer after the timeout", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-human-input",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Task with human input enabled")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-human-input",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Migrate the database",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+					HumanInput: &axonv1alpha1.HumanInputPolicy{
+						Timeout:       &metav1.Duration{Duration: 3 * time.Second},
+						DefaultAnswer: "Use Postgres",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			jobLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			createdJob := &batchv1.Job{}
+
+			By("Verifying the Job runs as the agent ServiceAccount")
+			Eventually(func() error {
+				return k8sClient.Get(ctx, jobLookupKey, createdJob)
+			}, timeout, interval).Should(Succeed())
+			agentName := controller.AgentServiceAccountName(task.Name)
+			Expect(createdJob.Spec.Template.Spec.ServiceAccountName).To(Equal(agentName))
+			Expect(createdJob.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--mcp-config"))
+
+			By("Verifying the agent ServiceAccount, Role and RoleBinding of the Task are created")
+			agentKey := types.NamespacedName{Name: agentName, Namespace: ns.Name}
+			Expect(k8sClient.Get(ctx, agentKey, &corev1.ServiceAccount{})).To(Succeed())
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, agentKey, role)).To(Succeed())
+			for _, rule := range role.Rules {
+				Expect(rule.ResourceNames).To(ConsistOf(Or(Equal(task.Name), Equal(task.Name+"-diff"))))
+			}
+			Expect(role.OwnerReferences).To(HaveLen(1))
+			Expect(role.OwnerReferences[0].Name).To(Equal(task.Name))
+			rb := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, agentKey, rb)).To(Succeed())
+			Expect(rb.RoleRef.Kind).To(Equal("Role"))
+			Expect(rb.RoleRef.Name).To(Equal(agentName))
+
+			By("Simulating Job running")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, jobLookupKey, createdJob); err != nil {
+					return err
+				}
+				createdJob.Status.Active = 1
+				return k8sClient.Status().Update(ctx, createdJob)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseRunning))
+
+			By("Simulating the agent asking a question")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				createdTask.Status.InputRequest = &axonv1alpha1.InputRequest{
+					ID:          "q1",
+					Question:    "Which database should I use?",
+					RequestedAt: metav1.Now(),
+				}
+				return k8sClient.Status().Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task is AwaitingInput")
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseAwaitingInput))
+			Expect(createdTask.Status.Message).To(ContainSubstring("Which database should I use?"))
+
+			By("Verifying the default answer is applied after the timeout")
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				if createdTask.Status.InputRequest == nil {
+					return ""
+				}
+				return createdTask.Status.InputRequest.AnsweredBy
+			}, 2*timeout, interval).Should(Equal("timeout"))
+			Expect(createdTask.Status.InputRequest.Answer).To(Equal("Use Postgres"))
+
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseRunning))
+		})
+	})
+
+	Context("When creating a Task that requires approval", func() {
+		It("Should hold the changes until approved and then push them", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-approval",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Workspace resource with secretRef")
+			ws := &axonv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.WorkspaceSpec{
+					Repo: "https://github.com/example/repo.git",
+					Ref:  "main",
+					SecretRef: &axonv1alpha1.SecretReference{
+						Name: "github-token",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ws)).Should(Succeed())
+
+			By("Creating a Task with requireApproval")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-approval",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Fix the flaky test",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+					WorkspaceRef: &axonv1alpha1.WorkspaceReference{
+						Name: ws.Name,
+					},
+					RequireApproval: true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			jobLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			createdJob := &batchv1.Job{}
+
+			By("Verifying the agent container does not receive the GitHub token")
+			Eventually(func() error {
+				return k8sClient.Get(ctx, jobLookupKey, createdJob)
+			}, timeout, interval).Should(Succeed())
+			logJobSpec(createdJob)
+			for _, env := range createdJob.Spec.Template.Spec.Containers[0].Env {
+				Expect(env.Name).NotTo(Equal("GITHUB_TOKEN"))
+				Expect(env.Name).NotTo(Equal("GH_TOKEN"))
+			}
+			Expect(createdJob.Spec.Template.Spec.Containers[0].Command).To(ContainElement("--capture-diff"))
+
+			By("Verifying the controller creates the ConfigMap the agent records its changes in")
+			diffLookupKey := types.NamespacedName{Name: task.Name + "-diff", Namespace: ns.Name}
+			diff := &corev1.ConfigMap{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, diffLookupKey, diff)
+			}, timeout, interval).Should(Succeed())
+			Expect(diff.OwnerReferences).To(HaveLen(1))
+			Expect(diff.OwnerReferences[0].Name).To(Equal(task.Name))
+
+			By("Simulating the agent recording its changes")
+			patch := []byte("diff --git a/a.txt b/a.txt\n")
+			diff.Data = map[string]string{"base": "0123456789abcdef"}
+			diff.BinaryData = map[string][]byte{"diff.patch": patch}
+			Expect(k8sClient.Update(ctx, diff)).To(Succeed())
+
+			By("Simulating Job completion")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, jobLookupKey, createdJob); err != nil {
+					return err
+				}
+				createdJob.Status.Succeeded = 1
+				return k8sClient.Status().Update(ctx, createdJob)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task is PendingApproval")
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhasePendingApproval))
+
+			By("Verifying the changes are snapshotted into an immutable ConfigMap")
+			Expect(createdTask.Status.DiffConfigMapName).To(Equal(task.Name + "-approval"))
+			snapshot := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: task.Name + "-approval", Namespace: ns.Name}, snapshot)).To(Succeed())
+			Expect(snapshot.Immutable).NotTo(BeNil())
+			Expect(*snapshot.Immutable).To(BeTrue())
+			Expect(snapshot.BinaryData["diff.patch"]).To(Equal(patch))
+			Expect(metav1.IsControlledBy(snapshot, createdTask)).To(BeTrue())
+			sum := sha256.Sum256(patch)
+			digest := hex.EncodeToString(sum[:])
+
+			By("Verifying the agent can no longer change the Task or its diff")
+			agentKey := types.NamespacedName{Name: controller.AgentServiceAccountName(task.Name), Namespace: ns.Name}
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, agentKey, &rbacv1.Role{}))
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, agentKey, &rbacv1.RoleBinding{}))
+			}, timeout, interval).Should(BeTrue())
+
+			By("Approving the Task with the digest of other changes")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				createdTask.Spec.Approved = true
+				createdTask.Spec.ApprovedDiffSHA256 = strings.Repeat("0", 64)
+				return k8sClient.Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the changes are not pushed")
+			Eventually(func() string {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Message
+			}, timeout, interval).Should(ContainSubstring("does not match"))
+			Expect(createdTask.Status.Phase).To(Equal(axonv1alpha1.TaskPhasePendingApproval))
+			pushJobLookupKey := types.NamespacedName{Name: task.Name + "-push", Namespace: ns.Name}
+			pushJob := &batchv1.Job{}
+			Consistently(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, pushJobLookupKey, pushJob))
+			}, 2*time.Second, interval).Should(BeTrue())
+
+			By("Approving the Task with the digest of the reviewed changes")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return err
+				}
+				createdTask.Spec.ApprovedDiffSHA256 = digest
+				return k8sClient.Update(ctx, createdTask)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying a push Job is created with the GitHub token")
+			Eventually(func() error {
+				return k8sClient.Get(ctx, pushJobLookupKey, pushJob)
+			}, timeout, interval).Should(Succeed())
+			logJobSpec(pushJob)
+			var envNames []string
+			for _, env := range pushJob.Spec.Template.Spec.Containers[0].Env {
+				envNames = append(envNames, env.Name)
+				if env.Name == "DIFF_SHA256" {
+					Expect(env.Value).To(Equal(digest))
+				}
+			}
+			Expect(envNames).To(ContainElements("GITHUB_TOKEN", "GH_TOKEN", "DIFF_SHA256"))
+			var configMaps []string
+			for _, v := range pushJob.Spec.Template.Spec.Volumes {
+				if v.ConfigMap != nil {
+					configMaps = append(configMaps, v.ConfigMap.Name)
+				}
+			}
+			Expect(configMaps).To(ConsistOf(snapshot.Name))
+
+			By("Simulating push Job completion")
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, pushJobLookupKey, pushJob); err != nil {
+					return err
+				}
+				pushJob.Status.Succeeded = 1
+				return k8sClient.Status().Update(ctx, pushJob)
+			}, timeout, interval).Should(Succeed())
+
+			By("Verifying the Task succeeds")
+			Eventually(func() axonv1alpha1.TaskPhase {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return ""
+				}
+				return createdTask.Status.Phase
+			}, timeout, interval).Should(Equal(axonv1alpha1.TaskPhaseSucceeded))
+			Expect(createdTask.Status.Message).To(ContainSubstring("axon/test-approval"))
+		})
+	})
+
+	Context("When creating a Task that collects artifacts", func() {
+		It("Should upload artifacts to the store from the AxonConfig", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-artifacts",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating an AxonConfig with an artifact store")
+			config := &axonv1alpha1.AxonConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.AxonConfigSpec{
+					TaskDefaults: &axonv1alpha1.TaskDefaults{
+						Artifacts: &axonv1alpha1.ArtifactsSpec{
+							Store: &axonv1alpha1.ArtifactStore{
+								S3: &axonv1alpha1.S3ArtifactStore{
+									Endpoint: "http://minio.minio:9000",
+									Bucket:   "axon",
+									SecretRef: axonv1alpha1.SecretReference{
+										Name: "minio-credentials",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, config)).Should(Succeed())
+
+			By("Creating a Task with artifact paths")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-artifacts",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Write a report",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+					Artifacts: &axonv1alpha1.ArtifactsSpec{
+						Paths: []string{"report.md"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			createdJob := &batchv1.Job{}
+
+			By("Verifying the Job uploads artifacts to the store")
+			Eventually(func() error {
+				return k8sClient.Get(ctx, taskLookupKey, createdJob)
+			}, timeout, interval).Should(Succeed())
+			logJobSpec(createdJob)
+
+			container := createdJob.Spec.Template.Spec.Containers[0]
+			Expect(container.Command).To(ContainElements("axon-agent", "--collect-artifacts", "report.md"))
+			Expect(createdJob.Spec.Template.Spec.ServiceAccountName).To(Equal(controller.AgentServiceAccountName(task.Name)))
+
+			Eventually(func() *axonv1alpha1.TaskSpec {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return nil
+				}
+				return createdTask.Status.EffectiveSpec
+			}, timeout, interval).ShouldNot(BeNil())
+			By("Verifying only the uploader sidecar holds the store's configuration")
+			for _, e := range container.Env {
+				Expect(e.Name).NotTo(HavePrefix("AXON_ARTIFACT_"))
+			}
+			initContainers := createdJob.Spec.Template.Spec.InitContainers
+			Expect(initContainers).To(HaveLen(1))
+			Expect(initContainers[0].Name).To(Equal(controller.UploaderContainerName))
+			env := map[string]string{}
+			for _, e := range initContainers[0].Env {
+				env[e.Name] = e.Value
+			}
+			Expect(env).To(HaveKeyWithValue("AXON_ARTIFACT_S3_BUCKET", "axon"))
+			Expect(env).To(HaveKeyWithValue("AXON_ARTIFACT_KEY", "artifacts/"+ns.Name+"/"+task.Name+"/"+string(createdTask.UID)))
+
+			By("Verifying the effective spec records the store")
+			Expect(createdTask.Status.EffectiveSpec.Artifacts.Store.S3.Bucket).To(Equal("axon"))
+		})
+	})
+
+	Context("When the namespace archives transcripts", func() {
+		It("Should archive the transcript keyed by the Task UID", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-task-transcript-archive",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating an AxonConfig with a transcript archive")
+			config := &axonv1alpha1.AxonConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.AxonConfigSpec{
+					TranscriptArchive: &axonv1alpha1.ArtifactStore{
+						PersistentVolumeClaim: &axonv1alpha1.PVCArtifactStore{
+							ClaimName: "transcripts",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, config)).Should(Succeed())
+
+			By("Creating a Task")
+			task := &axonv1alpha1.Task{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-archived",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpec{
+					Type:   "claude-code",
+					Prompt: "Summarize the repository",
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeAPIKey,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "anthropic-api-key",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+
+			taskLookupKey := types.NamespacedName{Name: task.Name, Namespace: ns.Name}
+			createdTask := &axonv1alpha1.Task{}
+			createdJob := &batchv1.Job{}
+
+			By("Verifying the archive location is recorded in the Task status")
+			Eventually(func() *axonv1alpha1.TranscriptArchive {
+				if err := k8sClient.Get(ctx, taskLookupKey, createdTask); err != nil {
+					return nil
+				}
+				return createdTask.Status.TranscriptArchive
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(createdTask.Status.TranscriptArchive.Key).To(Equal(
+				"transcripts/" + ns.Name + "/" + task.Name + "/" + string(createdTask.UID) + ".jsonl.gz"))
+
+			By("Verifying the Job archives the transcript to the claim")
+			Expect(k8sClient.Get(ctx, taskLookupKey, createdJob)).To(Succeed())
+			logJobSpec(createdJob)
+			podSpec := createdJob.Spec.Template.Spec
+			Expect(podSpec.Containers[0].Command).To(ContainElement("--archive-transcript"))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("PersistentVolumeClaim.ClaimName", "transcripts")))
+
+			By("Verifying only the uploader sidecar mounts the Task's directory of the claim")
+			for _, m := range podSpec.Containers[0].VolumeMounts {
+				Expect(m.Name).NotTo(Equal(controller.TranscriptVolumeName))
+			}
+			Expect(podSpec.InitContainers).To(HaveLen(1))
+			Expect(podSpec.InitContainers[0].Name).To(Equal(controller.UploaderContainerName))
+			Expect(podSpec.InitContainers[0].VolumeMounts).To(ContainElement(And(
+				HaveField("Name", controller.TranscriptVolumeName),
+				HaveField("SubPath", "transcripts/"+ns.Name+"/"+task.Name),
+			)))
 		})
 	})
 })
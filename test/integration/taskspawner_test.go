@@ -61,7 +61,7 @@ var _ = Describe("TaskSpawner Controller", func() {
 					},
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeOAuth,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "claude-credentials",
@@ -110,9 +110,23 @@ var _ = Describe("TaskSpawner Controller", func() {
 			Expect(container.Args).To(ConsistOf(
 				"--taskspawner-name="+ts.Name,
 				"--taskspawner-namespace="+ns.Name,
+				"--metrics-bind-address=:8080",
 				"--github-owner=axon-core",
 				"--github-repo=axon",
 			))
+			Expect(container.Ports).To(HaveLen(1))
+			Expect(container.Ports[0].Name).To(Equal(controller.SpawnerMetricsPortName))
+
+			By("Verifying the metrics Service")
+			svc := &corev1.Service{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: ts.Name, Namespace: ns.Name}, svc)
+			}, timeout, interval).Should(Succeed())
+			Expect(svc.Labels["app.kubernetes.io/component"]).To(Equal("spawner"))
+			Expect(svc.Spec.Selector["axon.io/taskspawner"]).To(Equal(ts.Name))
+			Expect(svc.Spec.Ports).To(HaveLen(1))
+			Expect(svc.Spec.Ports[0].Name).To(Equal("metrics"))
+			Expect(metav1.IsControlledBy(svc, createdTS)).To(BeTrue())
 
 			By("Verifying the ServiceAccount")
 			sa := &corev1.ServiceAccount{}
@@ -195,7 +209,7 @@ var _ = Describe("TaskSpawner Controller", func() {
 					},
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeOAuth,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "claude-credentials",
@@ -262,7 +276,7 @@ var _ = Describe("TaskSpawner Controller", func() {
 					},
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeOAuth,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "claude-credentials",
@@ -333,7 +347,7 @@ var _ = Describe("TaskSpawner Controller", func() {
 					},
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeOAuth,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "claude-credentials",
@@ -426,7 +440,7 @@ var _ = Describe("TaskSpawner Controller", func() {
 					},
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeOAuth,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "claude-credentials",
@@ -458,6 +472,313 @@ var _ = Describe("TaskSpawner Controller", func() {
 		})
 	})
 
+	Context("When creating a TaskSpawner with a GitHub comments source", func() {
+		It("Should create a Deployment for the Workspace's repository", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-taskspawner-comments",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Workspace")
+			ws := &axonv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-comments",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.WorkspaceSpec{
+					Repo: "https://github.com/axon-core/axon.git",
+					Ref:  "main",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ws)).Should(Succeed())
+
+			By("Creating a TaskSpawner with a GitHub comments source")
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spawner-comments",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpawnerSpec{
+					When: axonv1alpha1.When{
+						GitHubComments: &axonv1alpha1.GitHubComments{
+							WorkspaceRef: &axonv1alpha1.WorkspaceReference{
+								Name: "test-workspace-comments",
+							},
+						},
+					},
+					TaskTemplate: axonv1alpha1.TaskTemplate{
+						Type: "claude-code",
+						Credentials: &axonv1alpha1.Credentials{
+							Type: axonv1alpha1.CredentialTypeOAuth,
+							SecretRef: axonv1alpha1.SecretReference{
+								Name: "claude-credentials",
+							},
+						},
+					},
+					PollInterval: "5m",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			By("Verifying the defaults")
+			tsLookupKey := types.NamespacedName{Name: ts.Name, Namespace: ns.Name}
+			createdTS := &axonv1alpha1.TaskSpawner{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, tsLookupKey, createdTS)
+			}, timeout, interval).Should(Succeed())
+			Expect(createdTS.Spec.When.GitHubComments.Command).To(Equal("/axon"))
+			Expect(createdTS.Spec.When.GitHubComments.AuthorAssociations).To(ConsistOf("OWNER", "MEMBER", "COLLABORATOR"))
+
+			By("Verifying a Deployment is created for the repository")
+			deployLookupKey := types.NamespacedName{Name: ts.Name, Namespace: ns.Name}
+			createdDeploy := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, deployLookupKey, createdDeploy)
+			}, timeout, interval).Should(Succeed())
+			Expect(createdDeploy.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+				"--github-owner=axon-core",
+				"--github-repo=axon",
+			))
+		})
+	})
+
+	Context("When creating a TaskSpawner with a GitHub workflow runs source", func() {
+		It("Should create a Deployment for the Workspace's repository", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-taskspawner-workflow-runs",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a Workspace")
+			ws := &axonv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-workflow-runs",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.WorkspaceSpec{
+					Repo: "https://github.com/axon-core/axon.git",
+					Ref:  "main",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ws)).Should(Succeed())
+
+			By("Creating a TaskSpawner with a GitHub workflow runs source")
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spawner-workflow-runs",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpawnerSpec{
+					When: axonv1alpha1.When{
+						GitHubWorkflowRuns: &axonv1alpha1.GitHubWorkflowRuns{
+							WorkspaceRef: &axonv1alpha1.WorkspaceReference{
+								Name: "test-workspace-workflow-runs",
+							},
+							Workflows: []string{"ci.yaml"},
+							Branches:  []string{"main"},
+						},
+					},
+					TaskTemplate: axonv1alpha1.TaskTemplate{
+						Type: "claude-code",
+						Credentials: &axonv1alpha1.Credentials{
+							Type: axonv1alpha1.CredentialTypeOAuth,
+							SecretRef: axonv1alpha1.SecretReference{
+								Name: "claude-credentials",
+							},
+						},
+					},
+					PollInterval: "5m",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			By("Verifying a Deployment is created for the repository")
+			deployLookupKey := types.NamespacedName{Name: ts.Name, Namespace: ns.Name}
+			createdDeploy := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, deployLookupKey, createdDeploy)
+			}, timeout, interval).Should(Succeed())
+			Expect(createdDeploy.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+				"--github-owner=axon-core",
+				"--github-repo=axon",
+			))
+		})
+	})
+
+	Context("When creating a TaskSpawner with an HTTP source", func() {
+		It("Should create a Deployment passing the Secret-backed headers", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-taskspawner-http",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a TaskSpawner with an HTTP source")
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spawner-http",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpawnerSpec{
+					When: axonv1alpha1.When{
+						HTTP: &axonv1alpha1.HTTPSource{
+							URL: "https://tickets.example.com/api/search",
+							Headers: []axonv1alpha1.HTTPHeader{{
+								Name:         "Authorization",
+								Value:        "Bearer ",
+								SecretKeyRef: &axonv1alpha1.SecretKeySelector{Name: "tickets", Key: "token"},
+							}},
+							ItemsPath: "{.issues[*]}",
+							Fields:    axonv1alpha1.HTTPFields{ID: "{.key}", Title: "{.fields.summary}"},
+						},
+					},
+					TaskTemplate: axonv1alpha1.TaskTemplate{
+						Type: "claude-code",
+						Credentials: &axonv1alpha1.Credentials{
+							Type: axonv1alpha1.CredentialTypeOAuth,
+							SecretRef: axonv1alpha1.SecretReference{
+								Name: "claude-credentials",
+							},
+						},
+					},
+					PollInterval: "5m",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			By("Verifying a Deployment is created with the header's Secret key")
+			deployLookupKey := types.NamespacedName{Name: ts.Name, Namespace: ns.Name}
+			createdDeploy := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, deployLookupKey, createdDeploy)
+			}, timeout, interval).Should(Succeed())
+			env := createdDeploy.Spec.Template.Spec.Containers[0].Env
+			Expect(env).To(HaveLen(1))
+			Expect(env[0].Name).To(Equal("HTTP_HEADER_0"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Name).To(Equal("tickets"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Key).To(Equal("token"))
+		})
+	})
+
+	Context("When creating a TaskSpawner with a Jira source", func() {
+		It("Should create a Deployment passing the Jira credentials", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-taskspawner-jira",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a TaskSpawner with a Jira source")
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spawner-jira",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpawnerSpec{
+					When: axonv1alpha1.When{
+						JiraIssues: &axonv1alpha1.JiraIssues{
+							URL:       "https://example.atlassian.net",
+							JQL:       "project = PROJ AND labels = axon",
+							SecretRef: &axonv1alpha1.SecretReference{Name: "jira"},
+						},
+					},
+					TaskTemplate: axonv1alpha1.TaskTemplate{
+						Type: "claude-code",
+						Credentials: &axonv1alpha1.Credentials{
+							Type: axonv1alpha1.CredentialTypeOAuth,
+							SecretRef: axonv1alpha1.SecretReference{
+								Name: "claude-credentials",
+							},
+						},
+					},
+					PollInterval: "5m",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			By("Verifying the API version defaults to 3")
+			createdTS := &axonv1alpha1.TaskSpawner{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ts.Name, Namespace: ns.Name}, createdTS)).Should(Succeed())
+			Expect(createdTS.Spec.When.JiraIssues.APIVersion).To(Equal("3"))
+
+			By("Verifying a Deployment is created with the Jira credentials")
+			deployLookupKey := types.NamespacedName{Name: ts.Name, Namespace: ns.Name}
+			createdDeploy := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, deployLookupKey, createdDeploy)
+			}, timeout, interval).Should(Succeed())
+			env := createdDeploy.Spec.Template.Spec.Containers[0].Env
+			Expect(env).To(HaveLen(2))
+			Expect(env[0].Name).To(Equal("JIRA_USER"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Name).To(Equal("jira"))
+			Expect(env[1].Name).To(Equal("JIRA_TOKEN"))
+			Expect(env[1].ValueFrom.SecretKeyRef.Key).To(Equal("JIRA_TOKEN"))
+		})
+	})
+
+	Context("When creating a TaskSpawner with a Linear source", func() {
+		It("Should create a Deployment passing the Linear API key", func() {
+			By("Creating a namespace")
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-taskspawner-linear",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+
+			By("Creating a TaskSpawner with a Linear source")
+			ts := &axonv1alpha1.TaskSpawner{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spawner-linear",
+					Namespace: ns.Name,
+				},
+				Spec: axonv1alpha1.TaskSpawnerSpec{
+					When: axonv1alpha1.When{
+						LinearIssues: &axonv1alpha1.LinearIssues{
+							Team:      "ENG",
+							Labels:    []string{"Agent-ready"},
+							SecretRef: &axonv1alpha1.SecretReference{Name: "linear"},
+						},
+					},
+					TaskTemplate: axonv1alpha1.TaskTemplate{
+						Type: "claude-code",
+						Credentials: &axonv1alpha1.Credentials{
+							Type: axonv1alpha1.CredentialTypeOAuth,
+							SecretRef: axonv1alpha1.SecretReference{
+								Name: "claude-credentials",
+							},
+						},
+					},
+					PollInterval: "5m",
+					OnComplete:   &axonv1alpha1.CompletionActions{State: "In Review"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			By("Verifying a Deployment is created with the Linear API key")
+			deployLookupKey := types.NamespacedName{Name: ts.Name, Namespace: ns.Name}
+			createdDeploy := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, deployLookupKey, createdDeploy)
+			}, timeout, interval).Should(Succeed())
+			env := createdDeploy.Spec.Template.Spec.Containers[0].Env
+			Expect(env).To(HaveLen(1))
+			Expect(env[0].Name).To(Equal("LINEAR_API_KEY"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Name).To(Equal("linear"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Key).To(Equal("LINEAR_API_KEY"))
+		})
+	})
+
 	Context("When creating a TaskSpawner with a nonexistent workspace", func() {
 		It("Should fail with a meaningful error", func() {
 			By("Creating a namespace")
@@ -484,7 +805,7 @@ var _ = Describe("TaskSpawner Controller", func() {
 					},
 					TaskTemplate: axonv1alpha1.TaskTemplate{
 						Type: "claude-code",
-						Credentials: axonv1alpha1.Credentials{
+						Credentials: &axonv1alpha1.Credentials{
 							Type: axonv1alpha1.CredentialTypeOAuth,
 							SecretRef: axonv1alpha1.SecretReference{
 								Name: "claude-credentials",
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	axonv1alpha1 "github.com/axon-core/axon/api/v1alpha1"
+)
+
+var _ = Describe("Webhooks", func() {
+	var ns *corev1.Namespace
+
+	BeforeEach(func() {
+		ns = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-webhook-",
+			},
+		}
+		Expect(k8sClient.Create(ctx, ns)).Should(Succeed())
+	})
+
+	newTask := func() *axonv1alpha1.Task {
+		return &axonv1alpha1.Task{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-task",
+				Namespace: ns.Name,
+			},
+			Spec: axonv1alpha1.TaskSpec{
+				Prompt: "Fix the bug",
+				Credentials: &axonv1alpha1.Credentials{
+					Type: axonv1alpha1.CredentialTypeAPIKey,
+					SecretRef: axonv1alpha1.SecretReference{
+						Name: "anthropic-api-key",
+					},
+				},
+			},
+		}
+	}
+
+	newTaskSpawner := func() *axonv1alpha1.TaskSpawner {
+		return &axonv1alpha1.TaskSpawner{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-spawner",
+				Namespace: ns.Name,
+			},
+			Spec: axonv1alpha1.TaskSpawnerSpec{
+				When: axonv1alpha1.When{
+					GitHubIssues: &axonv1alpha1.GitHubIssues{
+						WorkspaceRef: &axonv1alpha1.WorkspaceReference{
+							Name: "test-workspace",
+						},
+					},
+				},
+				TaskTemplate: axonv1alpha1.TaskTemplate{
+					Credentials: &axonv1alpha1.Credentials{
+						Type: axonv1alpha1.CredentialTypeOAuth,
+						SecretRef: axonv1alpha1.SecretReference{
+							Name: "claude-credentials",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	newWorkspace := func() *axonv1alpha1.Workspace {
+		return &axonv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-workspace",
+				Namespace: ns.Name,
+			},
+			Spec: axonv1alpha1.WorkspaceSpec{
+				Repo: "https://github.com/axon-core/axon.git",
+				Ref:  "main",
+			},
+		}
+	}
+
+	expectInvalid := func(err error, field string) {
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+		Expect(err.Error()).To(ContainSubstring(field))
+	}
+
+	Context("Task", func() {
+		It("Should default the agent type", func() {
+			task := newTask()
+			Expect(k8sClient.Create(ctx, task)).Should(Succeed())
+			Expect(task.Spec.Type).To(Equal("claude-code"))
+		})
+
+		It("Should reject an unknown agent type", func() {
+			task := newTask()
+			task.Spec.Type = "unknown-agent"
+			expectInvalid(k8sClient.Create(ctx, task), "spec.type")
+		})
+
+		It("Should reject a Task that continues from itself", func() {
+			task := newTask()
+			task.Spec.ContinueFrom = &axonv1alpha1.TaskReference{Name: task.Name}
+			expectInvalid(k8sClient.Create(ctx, task), "spec.continueFrom.name")
+		})
+
+		It("Should reject requireApproval without a Workspace", func() {
+			task := newTask()
+			task.Spec.RequireApproval = true
+			expectInvalid(k8sClient.Create(ctx, task), "spec.workspaceRef")
+		})
+	})
+
+	Context("TaskSpawner", func() {
+		It("Should default the agent type and the poll interval", func() {
+			ts := newTaskSpawner()
+			ts.Spec.PollInterval = "300"
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+			Expect(ts.Spec.TaskTemplate.Type).To(Equal("claude-code"))
+			Expect(ts.Spec.PollInterval).To(Equal("5m0s"))
+		})
+
+		It("Should reject a TaskSpawner without a source", func() {
+			ts := newTaskSpawner()
+			ts.Spec.When = axonv1alpha1.When{}
+			expectInvalid(k8sClient.Create(ctx, ts), "spec.when")
+		})
+
+		It("Should reject an invalid prompt template", func() {
+			ts := newTaskSpawner()
+			ts.Spec.TaskTemplate.PromptTemplate = "Fix {{.Title"
+			expectInvalid(k8sClient.Create(ctx, ts), "spec.taskTemplate.promptTemplate")
+		})
+
+		It("Should reject a prompt template referencing an unknown field", func() {
+			ts := newTaskSpawner()
+			ts.Spec.TaskTemplate.PromptTemplate = "Fix {{.Tittle}}"
+			expectInvalid(k8sClient.Create(ctx, ts), "spec.taskTemplate.promptTemplate")
+		})
+
+		It("Should reject an invalid poll interval", func() {
+			ts := newTaskSpawner()
+			ts.Spec.PollInterval = "often"
+			expectInvalid(k8sClient.Create(ctx, ts), "spec.pollInterval")
+		})
+
+		It("Should reject updates that make the spec invalid", func() {
+			ts := newTaskSpawner()
+			Expect(k8sClient.Create(ctx, ts)).Should(Succeed())
+
+			// Retry on conflicts with the controller adding its finalizer
+			var err error
+			Eventually(func() bool {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ts), ts)).To(Succeed())
+				ts.Spec.PollInterval = "often"
+				err = k8sClient.Update(ctx, ts)
+				return !apierrors.IsConflict(err)
+			}).Should(BeTrue())
+			expectInvalid(err, "spec.pollInterval")
+		})
+	})
+
+	Context("Workspace", func() {
+		It("Should default the repository scheme", func() {
+			ws := newWorkspace()
+			ws.Spec.Repo = "github.com/axon-core/axon.git"
+			Expect(k8sClient.Create(ctx, ws)).Should(Succeed())
+			Expect(ws.Spec.Repo).To(Equal("https://github.com/axon-core/axon.git"))
+		})
+
+		It("Should reject an invalid repository", func() {
+			ws := newWorkspace()
+			ws.Spec.Repo = "ftp://github.com/axon-core/axon.git"
+			expectInvalid(k8sClient.Create(ctx, ws), "spec.repo")
+		})
+
+		It("Should reject an invalid ref", func() {
+			ws := newWorkspace()
+			ws.Spec.Ref = "feature..branch"
+			expectInvalid(k8sClient.Create(ctx, ws), "spec.ref")
+		})
+	})
+})